@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"crypto/rsa"
 	"database/sql"
 	"flag"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -12,14 +14,29 @@ import (
 	"time"
 
 	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	httpserver "github.com/akonovalovdev/DDD_example/internal/adapters/http"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
 	"github.com/akonovalovdev/DDD_example/internal/adapters/http/handlers"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/redis"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/repository/file"
 	"github.com/akonovalovdev/DDD_example/internal/adapters/repository/postgres"
 	"github.com/akonovalovdev/DDD_example/internal/adapters/skinport"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/tiered"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/webhook"
 	"github.com/akonovalovdev/DDD_example/internal/application"
 	"github.com/akonovalovdev/DDD_example/internal/config"
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/breaker"
 	"github.com/akonovalovdev/DDD_example/internal/pkg/cache"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/logging"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/ratelimit"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/tracing"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
 )
 
 func main() {
@@ -37,6 +54,13 @@ func main() {
 
 	logger = setupLogger(cfg.Log.Level)
 
+	shutdownTracing, err := setupTracing(cfg.Tracing, logger)
+	if err != nil {
+		logger.Error("failed to set up tracing", slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer shutdownTracing()
+
 	db, err := setupDatabase(cfg.Database)
 	if err != nil {
 		logger.Error("failed to connect to database", slog.Any("error", err))
@@ -45,33 +69,161 @@ func main() {
 	defer db.Close()
 	logger.Info("connected to database")
 
-	itemCache := cache.NewInMemoryCache(time.Minute)
-	defer itemCache.Close()
+	// Фоновые задачи останавливаются при завершении приложения
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	itemCache, localCache, closeCache := setupCache(bgCtx, cfg.Cache, logger)
+	defer closeCache()
 
-	skinportClient := skinport.NewClient(cfg.Skinport.APIURL, cfg.Skinport.Timeout)
+	skinportClient, skinportBreaker := setupSkinport(cfg.Skinport, logger)
 	userRepo := postgres.NewUserRepository(db)
 	transactionRepo := postgres.NewTransactionRepository(db)
+	priceSnapshotRepo := postgres.NewPriceSnapshotRepository(db, cfg.PriceHistory.BatchSize)
+	alertRepo := postgres.NewAlertRepository(db)
+	alertNotifier := webhook.NewNotifier(cfg.Alerts.WebhookSecret, cfg.Alerts.WebhookTimeout)
+
+	// Значение чужого типа под ключом — ошибка программы, а не обычный промах
+	onTypeMismatch := func(key string, value interface{}) {
+		logger.Error("cached value has unexpected type", slog.String("key", key), slog.String("type", fmt.Sprintf("%T", value)))
+	}
+	itemService := application.NewItemService(
+		skinportClient,
+		cache.NewTyped[application.CachedCatalogue](itemCache, onTypeMismatch),
+		cfg.Cache.TTL,
+	)
+	salesHistoryService := application.NewSalesHistoryService(
+		skinportClient,
+		cache.NewTyped[map[string]*item.SalesHistory](itemCache, onTypeMismatch),
+		cfg.Cache.SalesHistoryTTL,
+	)
+	// При общем кеше Skinport опрашивает только реплика, захватившая advisory lock
+	if cfg.RefreshLock.Enabled {
+		locker := postgres.NewAdvisoryLocker(db)
+		itemService.SetLocker(locker, cfg.RefreshLock.Wait)
+		salesHistoryService.SetLocker(locker, cfg.RefreshLock.Wait)
+	}
 
-	itemService := application.NewItemService(skinportClient, itemCache, cfg.Cache.TTL)
 	balanceService := application.NewBalanceService(userRepo, transactionRepo)
+	priceHistoryService := application.NewPriceHistoryService(priceSnapshotRepo, cfg.PriceHistory.Retention, logger)
+	alertService := application.NewAlertService(
+		alertRepo,
+		userRepo,
+		alertNotifier,
+		cfg.Alerts.MaxAttempts,
+		cfg.Alerts.InitialBackoff,
+		logger,
+	)
+
+	// Административные действия с кешем записываются в журнал аудита
+	cacheAdminService := application.NewCacheAdminService(itemCache, itemService, postgres.NewAuditLog(db), logger)
+
+	insightService := application.NewInsightService(
+		itemService,
+		decimal.NewFromFloat(*cfg.Insights.FeePercent),
+		cfg.Insights.MinQuantity,
+	)
+
+	// Каждое обновление каталога сохраняется в собственную историю цен
+	// и проверяется на срабатывание ценовых алертов
+	itemService.AddRefreshHook(priceHistoryService.RecordSnapshot)
+	itemService.AddRefreshHook(alertService.Evaluate)
+
+	// Лента продаж обновляет закешированный каталог и раздается клиентам через SSE
+	saleFeed := skinport.NewSaleFeed(cfg.SaleFeed.URL, cfg.SaleFeed.Currency, cfg.SaleFeed.Locale, logger)
+	saleFeedService := application.NewSaleFeedService(saleFeed, itemService, cfg.SaleFeed.ClientBuffer, logger)
+
+	// Метрики регистрируются до первого обращения к Skinport, чтобы прогрев тоже был учтен
+	var registry *prometheus.Registry
+	if !cfg.Metrics.Disabled {
+		registry = prometheus.NewRegistry()
+		setupMetrics(registry, db, localCache, skinportClient, skinportBreaker, itemService, balanceService)
+	}
+
+	// Последний каталог сохраняется после каждого обновления и восстанавливается при старте
+	var restored bool
+	if catalogueStore := setupCatalogueStore(cfg.CatalogueStore, db); catalogueStore != nil {
+		persistence := application.NewCataloguePersistence(catalogueStore, itemService, cfg.CatalogueStore.MaxAge, logger)
+		itemService.AddRefreshHook(persistence.Save)
+
+		if restored, err = persistence.Restore(context.Background()); err != nil {
+			logger.Warn("failed to restore catalogue", slog.Any("error", err))
+		}
+	}
 
-	// Прогрев кеша при запуске (опционально, не блокирует старт при ошибке)
-	logger.Info("warming up items cache...")
-	if err := itemService.WarmUp(context.Background()); err != nil {
-		logger.Warn("cache warm-up failed, will retry on first request", slog.Any("error", err))
+	go priceHistoryService.RunRetention(bgCtx, cfg.PriceHistory.CleanupInterval)
+
+	if cfg.SaleFeed.Enabled {
+		go itemService.RunSaleEventFlush(bgCtx, cfg.SaleFeed.FlushInterval)
+		go func() {
+			if err := saleFeedService.Run(bgCtx); err != nil {
+				logger.Error("sale feed stopped", slog.Any("error", err))
+			}
+		}()
+	}
+
+	if restored {
+		// Каталог уже в кеше — живые данные подтягиваются в фоне, старт не ждет Skinport
+		go func() {
+			if err := itemService.Refresh(bgCtx); err != nil {
+				logger.Warn("background catalogue refresh failed, serving restored catalogue", slog.Any("error", err))
+				return
+			}
+			logger.Info("restored catalogue replaced with live data")
+		}()
 	} else {
-		logger.Info("items cache warmed up successfully")
+		// Прогрев кеша при запуске (опционально, не блокирует старт при ошибке)
+		logger.Info("warming up items cache...")
+		if err := itemService.WarmUp(context.Background()); err != nil {
+			logger.Warn("cache warm-up failed, will retry on first request", slog.Any("error", err))
+		} else {
+			logger.Info("items cache warmed up successfully")
+		}
 	}
 
-	itemHandler := handlers.NewItemHandler(itemService, logger)
+	itemHandler := handlers.NewItemHandler(itemService, salesHistoryService, logger)
 	balanceHandler := handlers.NewBalanceHandler(balanceService, logger)
+	priceHistoryHandler := handlers.NewPriceHistoryHandler(priceHistoryService, logger)
+	alertHandler := handlers.NewAlertHandler(alertService, logger)
+	insightHandler := handlers.NewInsightHandler(insightService, logger)
+	saleStreamHandler := handlers.NewSaleStreamHandler(saleFeedService, cfg.SaleFeed.Heartbeat, logger)
+	adminHandler := handlers.NewAdminHandler(cacheAdminService, logger)
+	healthHandler := handlers.NewHealthHandler(
+		setupHealthChecks(cfg.Health, db, itemService, skinportBreaker),
+		cfg.Health.CheckTimeout,
+		logger,
+	)
+
+	authenticator, err := setupAuth(cfg.Auth, logger)
+	if err != nil {
+		logger.Error("failed to set up authentication", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	limiter, closeLimiter, err := setupRateLimiter(cfg.RateLimit, cfg.Cache.Redis, logger)
+	if err != nil {
+		logger.Error("failed to set up rate limiting", slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer closeLimiter()
 
 	server := httpserver.NewServer(
 		cfg.Server.Port,
 		cfg.Server.ReadTimeout,
 		cfg.Server.WriteTimeout,
-		itemHandler,
-		balanceHandler,
+		httpserver.Handlers{
+			Item:         itemHandler,
+			Balance:      balanceHandler,
+			PriceHistory: priceHistoryHandler,
+			Alert:        alertHandler,
+			Insight:      insightHandler,
+			SaleStream:   saleStreamHandler,
+			Admin:        adminHandler,
+			Health:       healthHandler,
+		},
+		auth.NewMiddleware(authenticator, logger),
+		limiter,
+		registry,
 		logger,
 	)
 
@@ -86,7 +238,14 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	// Сначала балансировщик перестает присылать новые запросы, затем сервер останавливается
+	logger.Info("draining: readiness is failing", slog.Duration("delay", cfg.Server.DrainDelay))
+	healthHandler.Drain()
+	time.Sleep(cfg.Server.DrainDelay)
+
 	logger.Info("shutting down server...")
+	stopBackground()
+	saleStreamHandler.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
@@ -117,7 +276,413 @@ func setupLogger(level string) *slog.Logger {
 		Level: logLevel,
 	})
 
-	return slog.New(handler)
+	// Записи с контекстом запроса получают request_id автоматически
+	return slog.New(logging.NewContextHandler(handler))
+}
+
+// skinportSource объединяет порты, которые реализуют и живой клиент, и фикстуры
+type skinportSource interface {
+	output.ItemFetcher
+	output.SalesHistoryFetcher
+}
+
+// setupSkinport создает источник данных Skinport. Живой клиент работает через автомат
+// защиты, который возвращается для проверки готовности; у фикстур его нет.
+func setupSkinport(cfg config.SkinportConfig, logger *slog.Logger) (skinportSource, *breaker.Breaker) {
+	var client *skinport.Client
+	switch cfg.Mode {
+	case config.SkinportModeFixture:
+		logger.Info("serving skinport data from fixtures", slog.String("dir", cfg.FixturesDir))
+		return skinport.NewFixtureFetcher(cfg.FixturesDir), nil
+	case config.SkinportModeRecord:
+		logger.Info("recording skinport responses as fixtures", slog.String("dir", cfg.FixturesDir))
+		client = skinport.NewClientWithTransport(cfg.APIURL, cfg.Timeout, skinport.NewRecordingTransport(cfg.FixturesDir, nil))
+	default:
+		client = skinport.NewClient(cfg.APIURL, cfg.Timeout)
+	}
+
+	cb := breaker.NewBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown)
+	client.SetBreaker(cb)
+	return client, cb
+}
+
+// setupCache создает кеш каталога и истории продаж. Redis позволяет репликам делить
+// один каталог и не запрашивать Skinport каждой по отдельности, а двухуровневый кеш
+// дополнительно держит горячую копию в памяти процесса. Подписка на инвалидации
+// работает до отмены ctx. Второе значение — кеш в памяти процесса, если он есть
+// (для метрик попаданий); nil для чистого Redis.
+func setupCache(ctx context.Context, cfg config.CacheConfig, logger *slog.Logger) (output.Cache, *cache.InMemoryCache, func()) {
+	switch cfg.Backend {
+	case config.CacheBackendRedis:
+		c := setupRedisCache(cfg, logger)
+		return c, nil, func() { c.Close() }
+
+	case config.CacheBackendTiered:
+		local := setupMemoryCache(cfg, logger)
+		shared := setupRedisCache(cfg, logger)
+		bus := redis.NewInvalidationBus(shared.Client(), cfg.Redis.InvalidationChannel, logger)
+
+		c := tiered.NewCache(local, shared, bus, cfg.LocalTTL, logger)
+		go func() {
+			if err := c.Run(ctx); err != nil {
+				logger.Error("cache invalidation subscription stopped", slog.Any("error", err))
+			}
+		}()
+
+		return c, local, func() {
+			local.Close()
+			shared.Close()
+		}
+
+	default:
+		c := setupMemoryCache(cfg, logger)
+		return c, c, c.Close
+	}
+}
+
+func setupMemoryCache(cfg config.CacheConfig, logger *slog.Logger) *cache.InMemoryCache {
+	return cache.NewInMemoryCacheWithOptions(cache.Options{
+		CleanupInterval: time.Minute,
+		MaxEntries:      *cfg.MaxEntries,
+		MaxBytes:        *cfg.MaxBytes,
+		OnEvict: func(key string, _ interface{}, reason cache.EvictionReason) {
+			// Вытеснение по лимиту означает, что кэш мал для рабочего набора
+			if reason == cache.EvictedCapacity {
+				logger.Warn("cache entry evicted by size limit", slog.String("key", key))
+			}
+		},
+	})
+}
+
+func setupRedisCache(cfg config.CacheConfig, logger *slog.Logger) *redis.Cache {
+	var codec redis.Codec = redis.NewJSONCodec()
+	if cfg.Redis.Codec == config.RedisCodecGob {
+		codec = redis.NewGobCodec()
+	}
+	// Имена типов хранятся в Redis вместе со значениями — менять их нельзя без сброса кеша
+	codec.Register("catalogue", application.CachedCatalogue{})
+	codec.Register("sales_history", map[string]*item.SalesHistory{})
+
+	client := redis.NewClient(redis.ClientOptions{
+		Addr:        cfg.Redis.Addr,
+		Password:    cfg.Redis.Password,
+		DB:          cfg.Redis.DB,
+		PoolSize:    cfg.Redis.PoolSize,
+		DialTimeout: cfg.Redis.DialTimeout,
+		IOTimeout:   cfg.Redis.IOTimeout,
+	})
+	c := redis.NewCache(client, codec, cfg.Redis.KeyPrefix, logger)
+
+	// Недоступный Redis не мешает старту: кеш работает как постоянный промах
+	if err := c.Ping(context.Background()); err != nil {
+		logger.Warn("redis is unavailable, cache will miss until it recovers",
+			slog.String("addr", cfg.Redis.Addr), slog.Any("error", err))
+	} else {
+		logger.Info("connected to redis", slog.String("addr", cfg.Redis.Addr))
+	}
+
+	return c
+}
+
+// setupAuth собирает цепочку аутентификации: bearer JWT, затем X-API-Key
+func setupAuth(cfg config.AuthConfig, logger *slog.Logger) (auth.Authenticator, error) {
+	if cfg.Disabled {
+		logger.Warn("authentication is disabled, every request is treated as admin")
+		return auth.Anonymous(), nil
+	}
+
+	var chain []auth.Authenticator
+
+	if cfg.JWT.Enabled() {
+		opts := auth.JWTOptions{
+			HMACSecret: []byte(cfg.JWT.HS256Secret),
+			RSAKeys:    make(map[string]*rsa.PublicKey),
+			Issuer:     cfg.JWT.Issuer,
+			Audience:   cfg.JWT.Audience,
+			Leeway:     cfg.JWT.Leeway,
+		}
+		if cfg.JWT.RS256PublicKeyFile != "" {
+			key, err := auth.LoadRSAPublicKeyFile(cfg.JWT.RS256PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			opts.RSAKeys[""] = key
+		}
+		if cfg.JWT.JWKSFile != "" {
+			keys, err := auth.LoadJWKSFile(cfg.JWT.JWKSFile)
+			if err != nil {
+				return nil, err
+			}
+			for kid, key := range keys {
+				opts.RSAKeys[kid] = key
+			}
+		}
+
+		jwtAuth, err := auth.NewJWTAuthenticator(opts)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, jwtAuth)
+	}
+
+	if cfg.APIKeys != "" {
+		keys, err := auth.ParseAPIKeys(cfg.APIKeys)
+		if err != nil {
+			return nil, err
+		}
+		keyAuth, err := auth.NewAPIKeyAuthenticator(keys)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, keyAuth)
+		logger.Info("api key authentication enabled", slog.Int("keys", len(keys)))
+	}
+
+	return auth.Chain(chain...), nil
+}
+
+// setupRateLimiter собирает ограничитель частоты запросов; nil означает, что лимиты отключены
+func setupRateLimiter(cfg config.RateLimitConfig, redisCfg config.RedisConfig, logger *slog.Logger) (*httpserver.RateLimiter, func(), error) {
+	if cfg.Disabled {
+		logger.Warn("rate limiting is disabled")
+		return nil, func() {}, nil
+	}
+
+	trusted, err := httpserver.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limits := map[string]output.RateLimit{
+		httpserver.RateLimitRead:    {Rate: cfg.Read.RPS, Burst: cfg.Read.Burst},
+		httpserver.RateLimitMoney:   {Rate: cfg.Money.RPS, Burst: cfg.Money.Burst},
+		httpserver.RateLimitDefault: {Rate: cfg.Default.RPS, Burst: cfg.Default.Burst},
+		httpserver.RateLimitIP:      {Rate: cfg.IP.RPS, Burst: cfg.IP.Burst},
+	}
+
+	var store output.RateLimitStore = ratelimit.NewMemoryStore()
+	closeStore := func() {}
+	if cfg.Backend == config.RateLimitBackendRedis {
+		// Общий бюджет на все реплики; при недоступном Redis запросы пропускаются
+		client := redis.NewClient(redis.ClientOptions{
+			Addr:        redisCfg.Addr,
+			Password:    redisCfg.Password,
+			DB:          redisCfg.DB,
+			PoolSize:    redisCfg.PoolSize,
+			DialTimeout: redisCfg.DialTimeout,
+			IOTimeout:   redisCfg.IOTimeout,
+		})
+		store = redis.NewRateLimitStore(client, cfg.RedisPrefix)
+		closeStore = func() { client.Close() }
+	}
+
+	logger.Info("rate limiting enabled",
+		slog.String("backend", cfg.Backend), slog.Int("trusted_proxies", len(trusted)))
+
+	return httpserver.NewRateLimiter(store, limits, trusted, logger), closeStore, nil
+}
+
+// setupTracing устанавливает глобальный провайдер трасс. Без экспортера span'ы не создаются,
+// но входящий traceparent по-прежнему передается в исходящие запросы.
+func setupTracing(cfg config.TracingConfig, logger *slog.Logger) (func(), error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch cfg.Exporter {
+	case config.TracingExporterStdout:
+		exporter, err = tracing.NewStdoutExporter(os.Stdout)
+	case config.TracingExporterOTLP:
+		var headers map[string]string
+		if headers, err = tracing.ParseHeaders(cfg.OTLP.Headers); err != nil {
+			return nil, err
+		}
+		exporter, err = tracing.NewOTLPExporter(context.Background(), tracing.OTLPOptions{
+			Endpoint: cfg.OTLP.Endpoint,
+			Headers:  headers,
+			Timeout:  cfg.OTLP.Timeout,
+		})
+	default:
+		return func() {}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := tracing.NewProvider(exporter, tracing.Options{
+		ServiceName: cfg.ServiceName,
+		SampleRatio: *cfg.SampleRatio,
+	})
+	otel.SetTracerProvider(provider)
+	// Ошибки экспорта SDK пишет через свой обработчик; направляем их в общий лог
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("failed to export spans", slog.Any("error", err))
+	}))
+
+	logger.Info("tracing enabled",
+		slog.String("exporter", cfg.Exporter), slog.Float64("sample_ratio", *cfg.SampleRatio))
+
+	return func() {
+		// Досылаем накопленные span'ы; после Shutdown новые span'ы не записываются
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			logger.Error("failed to flush spans", slog.Any("error", err))
+		}
+	}, nil
+}
+
+// setupHealthChecks собирает проверки GET /readyz. База данных и каталог критичны: без них
+// реплика не может обслуживать запросы. Разомкнутый автомат Skinport только помечает ответ
+// как degraded — иначе при отказе Skinport с балансировки снялись бы все реплики, хотя
+// каталог из кеша они отдают.
+func setupHealthChecks(
+	cfg config.HealthConfig,
+	db *sql.DB,
+	itemService *application.ItemServiceImpl,
+	skinportBreaker *breaker.Breaker,
+) []handlers.HealthCheck {
+	checks := []handlers.HealthCheck{
+		{Name: "database", Critical: true, Check: db.PingContext},
+		{Name: "catalogue", Critical: true, Check: func(context.Context) error {
+			return itemService.CatalogueStatus().Ready(cfg.CatalogueMaxAge, time.Now())
+		}},
+	}
+
+	if skinportBreaker != nil {
+		checks = append(checks, handlers.HealthCheck{Name: "skinport", Check: func(context.Context) error {
+			if state := skinportBreaker.State(); state != breaker.StateClosed {
+				return fmt.Errorf("circuit breaker is %s", state)
+			}
+			return nil
+		}})
+	}
+
+	return checks
+}
+
+// setupMetrics регистрирует метрики компонентов. Счетчики, которые компоненты уже ведут
+// сами (кеш, загрузчик каталога, списания, пул БД), читаются при каждом опросе /metrics.
+func setupMetrics(
+	registry *prometheus.Registry,
+	db *sql.DB,
+	localCache *cache.InMemoryCache,
+	source skinportSource,
+	skinportBreaker *breaker.Breaker,
+	itemService *application.ItemServiceImpl,
+	balanceService *application.BalanceServiceImpl,
+) {
+	if client, ok := source.(*skinport.Client); ok {
+		duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "skinport_request_duration_seconds",
+			Help:    "Duration of Skinport API requests by endpoint.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"endpoint"})
+		errs := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "skinport_request_errors_total",
+			Help: "Failed Skinport API requests by endpoint.",
+		}, []string{"endpoint"})
+		registry.MustRegister(duration, errs)
+		client.SetObserver(func(endpoint string, d time.Duration, err error) {
+			duration.WithLabelValues(endpoint).Observe(d.Seconds())
+			if err != nil {
+				errs.WithLabelValues(endpoint).Inc()
+			}
+		})
+	}
+
+	gauge := func(name, help string, fn func() float64) {
+		registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, fn))
+	}
+	counter := func(name, help string, fn func() float64) {
+		registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, fn))
+	}
+
+	if skinportBreaker != nil {
+		gauge("skinport_circuit_state",
+			"Skinport circuit breaker state: 0 closed, 1 open, 2 half-open.", func() float64 {
+				return float64(skinportBreaker.State())
+			})
+	}
+
+	if localCache != nil {
+		counter("cache_hits_total", "In-memory cache hits.", func() float64 {
+			return float64(localCache.Stats().Hits)
+		})
+		counter("cache_misses_total", "In-memory cache misses.", func() float64 {
+			return float64(localCache.Stats().Misses)
+		})
+		counter("cache_evictions_total", "In-memory cache entries evicted by size limits.", func() float64 {
+			return float64(localCache.Stats().Evictions)
+		})
+		gauge("cache_entries", "In-memory cache entries.", func() float64 {
+			return float64(localCache.Stats().Entries)
+		})
+	}
+
+	counter("catalogue_loads_total", "Catalogue loads executed on cache miss.", func() float64 {
+		return float64(itemService.LoaderStats().Loads)
+	})
+	counter("catalogue_loads_shared_total", "Catalogue requests that joined an in-flight load (singleflight).", func() float64 {
+		return float64(itemService.LoaderStats().Shared)
+	})
+
+	registry.MustRegister(withdrawalsCollector{balanceService})
+
+	dbGauge := func(name, help string, value func(sql.DBStats) float64) {
+		gauge(name, help, func() float64 { return value(db.Stats()) })
+	}
+	dbCounter := func(name, help string, value func(sql.DBStats) float64) {
+		counter(name, help, func() float64 { return value(db.Stats()) })
+	}
+	dbGauge("db_max_open_connections", "Maximum number of open database connections.",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })
+	dbGauge("db_open_connections", "Established database connections, in use and idle.",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) })
+	dbGauge("db_in_use_connections", "Database connections currently in use.",
+		func(s sql.DBStats) float64 { return float64(s.InUse) })
+	dbGauge("db_idle_connections", "Idle database connections.",
+		func(s sql.DBStats) float64 { return float64(s.Idle) })
+	dbCounter("db_wait_count_total", "Total number of waits for a database connection.",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) })
+	dbCounter("db_wait_duration_seconds_total", "Total time blocked waiting for a database connection.",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })
+	dbCounter("db_max_idle_closed_total", "Connections closed due to SetMaxIdleConns.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) })
+	dbCounter("db_max_idle_time_closed_total", "Connections closed due to SetConnMaxIdleTime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) })
+	dbCounter("db_max_lifetime_closed_total", "Connections closed due to SetConnMaxLifetime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) })
+}
+
+// withdrawalsDesc описывает счетчик списаний; метка result заранее не известна,
+// поэтому значения собираются из WithdrawStats при каждом опросе
+var withdrawalsDesc = prometheus.NewDesc("balance_withdrawals_total", "Withdrawals by result.", []string{"result"}, nil)
+
+// withdrawalsCollector отдает счетчики списаний, которые ведет BalanceService
+type withdrawalsCollector struct {
+	service *application.BalanceServiceImpl
+}
+
+func (c withdrawalsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- withdrawalsDesc
+}
+
+func (c withdrawalsCollector) Collect(ch chan<- prometheus.Metric) {
+	for result, n := range c.service.WithdrawStats() {
+		ch <- prometheus.MustNewConstMetric(withdrawalsDesc, prometheus.CounterValue, float64(n), result)
+	}
+}
+
+func setupCatalogueStore(cfg config.CatalogueStoreConfig, db *sql.DB) output.CatalogueStore {
+	switch cfg.Backend {
+	case config.CatalogueStorePostgres:
+		return postgres.NewCatalogueStore(db)
+	case config.CatalogueStoreFile:
+		return file.NewCatalogueStore(cfg.Path)
+	default:
+		return nil
+	}
 }
 
 func setupDatabase(cfg config.DatabaseConfig) (*sql.DB, error) {
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader заголовок со статическим API ключом
+const APIKeyHeader = "X-API-Key"
+
+// APIKey статический ключ и вызывающий, которого он аутентифицирует
+type APIKey struct {
+	Key     string
+	Subject string
+	Scopes  []string
+}
+
+// ParseAPIKeys разбирает список ключей вида "key:subject[:scope+scope],key:subject".
+// Субъект — id пользователя или произвольное имя сервиса.
+func ParseAPIKeys(spec string) ([]APIKey, error) {
+	var keys []APIKey
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, ":", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid api key entry %q: expected key:subject[:scopes]", redact(parts[0]))
+		}
+
+		key := APIKey{Key: parts[0], Subject: parts[1]}
+		if len(parts) == 3 && parts[2] != "" {
+			key.Scopes = strings.Split(parts[2], "+")
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// APIKeyAuthenticator аутентифицирует запросы по заголовку X-API-Key
+type APIKeyAuthenticator struct {
+	// Ключи хранятся как SHA-256: поиск по хэшу не раскрывает ключ через время сравнения
+	keys map[[sha256.Size]byte]APIKey
+}
+
+// NewAPIKeyAuthenticator создает новый APIKeyAuthenticator
+func NewAPIKeyAuthenticator(keys []APIKey) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{keys: make(map[[sha256.Size]byte]APIKey, len(keys))}
+	for _, k := range keys {
+		hash := sha256.Sum256([]byte(k.Key))
+		if _, ok := a.keys[hash]; ok {
+			return nil, fmt.Errorf("duplicate api key for subject %q", k.Subject)
+		}
+		a.keys[hash] = APIKey{Subject: k.Subject, Scopes: k.Scopes}
+	}
+	return a, nil
+}
+
+// Authenticate реализует Authenticator
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	k, ok := a.keys[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, invalid("unknown api key")
+	}
+
+	return &Principal{Subject: k.Subject, Scopes: k.Scopes, Method: "api_key"}, nil
+}
+
+// redact оставляет от ключа только начало, чтобы он не попал в логи целиком
+func redact(key string) string {
+	if len(key) <= 4 {
+		return "***"
+	}
+	return key[:4] + "***"
+}
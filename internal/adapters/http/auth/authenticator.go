@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
+)
+
+var (
+	// ErrNoCredentials запрос не содержит учетных данных, которые понимает аутентификатор
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials учетные данные есть, но не прошли проверку
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authenticator определяет способ аутентификации запроса.
+// Если в запросе нет подходящих учетных данных, возвращает ErrNoCredentials,
+// чтобы цепочка попробовала следующий способ.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// AuthenticatorFunc позволяет использовать функцию как Authenticator
+type AuthenticatorFunc func(r *http.Request) (*Principal, error)
+
+// Authenticate вызывает f(r)
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (*Principal, error) {
+	return f(r)
+}
+
+// Chain пробует аутентификаторы по порядку. Решает первый, нашедший свои учетные данные:
+// невалидный JWT не дает перейти к проверке API ключа.
+func Chain(authenticators ...Authenticator) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		for _, a := range authenticators {
+			p, err := a.Authenticate(r)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			return p, err
+		}
+		return nil, ErrNoCredentials
+	})
+}
+
+// Anonymous пропускает все запросы с ролью admin. Используется только при AUTH_DISABLED=true.
+func Anonymous() Authenticator {
+	return AuthenticatorFunc(func(*http.Request) (*Principal, error) {
+		return &Principal{Subject: "anonymous", Scopes: []string{RoleAdmin}, Method: "none"}, nil
+	})
+}
+
+// Middleware аутентифицирует запросы, проверяет права и кладет Principal в контекст.
+// Нет учетных данных или они невалидны — 401, не хватает права — 403.
+type Middleware struct {
+	authenticator Authenticator
+	logger        *slog.Logger
+}
+
+// NewMiddleware создает новый Middleware
+func NewMiddleware(authenticator Authenticator, logger *slog.Logger) *Middleware {
+	return &Middleware{
+		authenticator: authenticator,
+		logger:        logger,
+	}
+}
+
+// Require оборачивает обработчик, требуя только аутентификацию
+func (m *Middleware) Require(next http.HandlerFunc) http.HandlerFunc {
+	return m.RequireScope("", next)
+}
+
+// RequireScope оборачивает обработчик, требуя аутентификацию и право scope.
+// Пустой scope означает любого аутентифицированного вызывающего.
+func (m *Middleware) RequireScope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := m.authenticator.Authenticate(r)
+		if err != nil {
+			m.unauthorized(w, r, err)
+			return
+		}
+		if scope != "" && !p.HasScope(scope) {
+			m.forbidden(w, r, p, scope)
+			return
+		}
+		next(w, r.WithContext(WithPrincipal(r.Context(), p)))
+	}
+}
+
+func (m *Middleware) unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	p := problem.New(http.StatusUnauthorized, problem.CodeUnauthenticated, "authentication required")
+	challenge := `Bearer realm="api"`
+	if !errors.Is(err, ErrNoCredentials) {
+		p = problem.New(http.StatusUnauthorized, problem.CodeInvalidCredentials, "invalid credentials")
+		challenge = `Bearer realm="api", error="invalid_token"`
+		m.logger.WarnContext(r.Context(), "authentication failed",
+			slog.String("path", r.URL.Path),
+			slog.String("remote_addr", r.RemoteAddr),
+			slog.Any("error", err),
+		)
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge)
+	problem.Write(w, r, p, m.logger)
+}
+
+func (m *Middleware) forbidden(w http.ResponseWriter, r *http.Request, p *Principal, scope string) {
+	m.logger.WarnContext(r.Context(), "insufficient scope",
+		slog.String("path", r.URL.Path),
+		slog.String("subject", p.Subject),
+		slog.String("required_scope", scope),
+	)
+
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="api", error="insufficient_scope", scope=%q`, scope))
+	problem.Write(w, r, problem.New(http.StatusForbidden, problem.CodeInsufficientScope, "insufficient scope").
+		With("required_scope", scope), m.logger)
+}
+
+// invalid оборачивает причину отказа в ErrInvalidCredentials
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidCredentials, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys(" user-key:1 , ops-key:ops:admin+balance:read ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(keys))
+	}
+	if keys[0].Key != "user-key" || keys[0].Subject != "1" || len(keys[0].Scopes) != 0 {
+		t.Errorf("unexpected first key: %+v", keys[0])
+	}
+	if keys[1].Subject != "ops" || len(keys[1].Scopes) != 2 || keys[1].Scopes[0] != RoleAdmin {
+		t.Errorf("unexpected second key: %+v", keys[1])
+	}
+
+	for _, spec := range []string{"only-key", ":1", "key:"} {
+		if _, err := ParseAPIKeys(spec); err == nil {
+			t.Errorf("spec %q: expected error", spec)
+		}
+	}
+}
+
+func TestPrincipal_CanAccessUser(t *testing.T) {
+	owner := &Principal{Subject: "1"}
+	if !owner.CanAccessUser(1) || owner.CanAccessUser(2) {
+		t.Error("user must access only their own id")
+	}
+
+	service := &Principal{Subject: "billing"}
+	if service.CanAccessUser(1) {
+		t.Error("non-numeric subject must not access users")
+	}
+
+	admin := &Principal{Subject: "billing", Scopes: []string{RoleAdmin}}
+	if !admin.CanAccessUser(2) {
+		t.Error("admin must access any user")
+	}
+
+	finance := &Principal{Subject: "billing", Scopes: []string{RoleFinance}}
+	if !finance.CanAccessUser(2) {
+		t.Error("finance must access any user")
+	}
+}
+
+func TestPrincipal_RoleScopes(t *testing.T) {
+	tests := []struct {
+		role   string
+		scope  string
+		expect bool
+	}{
+		{RoleReader, ScopeItemsRead, true},
+		{RoleReader, ScopeBalanceRead, true},
+		{RoleReader, ScopeBalanceWrite, false},
+		{RoleReader, ScopeAlertsRead, false},
+		{RoleUser, ScopeBalanceWrite, true},
+		{RoleUser, ScopeAlertsWrite, true},
+		{RoleUser, ScopeBalanceDeposit, false},
+		{RoleUser, ScopeAnyUser, false},
+		{RoleFinance, ScopeBalanceWrite, true},
+		{RoleFinance, ScopeBalanceDeposit, true},
+		{RoleFinance, ScopeAnyUser, true},
+		{RoleFinance, ScopeAccountsFreeze, false},
+		{RoleFinance, ScopeCacheAdmin, false},
+		{RoleAdmin, ScopeAccountsFreeze, true},
+		{RoleAdmin, ScopeCacheAdmin, true},
+		{ScopeBalanceRead, ScopeBalanceRead, true},
+		{ScopeBalanceRead, ScopeBalanceWrite, false},
+	}
+
+	for _, tt := range tests {
+		p := &Principal{Subject: "1", Scopes: []string{tt.role}}
+		if got := p.HasScope(tt.scope); got != tt.expect {
+			t.Errorf("%s has %s: expected %v, got %v", tt.role, tt.scope, tt.expect, got)
+		}
+	}
+}
+
+func TestMiddleware_Require(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	jwtAuth, _ := NewJWTAuthenticator(JWTOptions{HMACSecret: secret, Now: func() time.Time { return testNow }})
+	keyAuth, _ := NewAPIKeyAuthenticator([]APIKey{{Key: "user-key", Subject: "7"}})
+	m := NewMiddleware(Chain(jwtAuth, keyAuth), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var got *Principal
+	handler := m.Require(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	serve := func(header, value string) *httptest.ResponseRecorder {
+		got = nil
+		req := httptest.NewRequest(http.MethodGet, "/users/1/balance", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rec := serve("", "")
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != `Bearer realm="api"` {
+		t.Errorf("missing credentials: expected 401 with challenge, got %d %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+
+	rec = serve("Authorization", "Bearer garbage")
+	if rec.Code != http.StatusUnauthorized || got != nil {
+		t.Errorf("invalid token: expected 401, got %d", rec.Code)
+	}
+
+	rec = serve(APIKeyHeader, "wrong-key")
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("invalid api key: expected 401, got %d", rec.Code)
+	}
+
+	rec = serve(APIKeyHeader, "user-key")
+	if rec.Code != http.StatusNoContent || got == nil || got.Subject != "7" || got.Method != "api_key" {
+		t.Errorf("api key: expected principal 7, got %d %+v", rec.Code, got)
+	}
+
+	token := signHS256(t, secret, map[string]interface{}{"alg": "HS256"}, validClaims())
+	rec = serve("Authorization", "Bearer "+token)
+	if rec.Code != http.StatusNoContent || got == nil || got.Subject != "1" {
+		t.Errorf("jwt: expected principal 1, got %d %+v", rec.Code, got)
+	}
+}
+
+func TestChain_FirstMatchDecides(t *testing.T) {
+	rejecting := AuthenticatorFunc(func(*http.Request) (*Principal, error) { return nil, ErrInvalidCredentials })
+	accepting := AuthenticatorFunc(func(*http.Request) (*Principal, error) { return &Principal{Subject: "1"}, nil })
+
+	_, err := Chain(rejecting, accepting).Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected invalid credentials to stop the chain, got %v", err)
+	}
+
+	_, err = Chain().Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials from empty chain, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultLeeway допуск расхождения часов при проверке exp и nbf
+const defaultLeeway = 30 * time.Second
+
+// JWTOptions параметры проверки JWT
+type JWTOptions struct {
+	// HMACSecret секрет для HS256; пустой отключает HS256
+	HMACSecret []byte
+	// RSAKeys открытые ключи RS256 по kid. Ключ с пустым kid подходит для любого токена.
+	RSAKeys map[string]*rsa.PublicKey
+	// Issuer ожидаемый iss; пустой не проверяется
+	Issuer string
+	// Audience ожидаемый aud; пустой не проверяется
+	Audience string
+	// Leeway допуск расхождения часов
+	Leeway time.Duration
+	// Now источник времени (для тестов)
+	Now func() time.Time
+}
+
+// JWTAuthenticator проверяет bearer токены JWT, подписанные HS256 или RS256
+type JWTAuthenticator struct {
+	opts   JWTOptions
+	parser *jwt.Parser
+}
+
+// NewJWTAuthenticator создает новый JWTAuthenticator
+func NewJWTAuthenticator(opts JWTOptions) (*JWTAuthenticator, error) {
+	if len(opts.HMACSecret) == 0 && len(opts.RSAKeys) == 0 {
+		return nil, errors.New("jwt authenticator requires an hmac secret or rsa keys")
+	}
+	if opts.Leeway == 0 {
+		opts.Leeway = defaultLeeway
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	// Принимаются только алгоритмы настроенных ключей: открытый RSA ключ нельзя
+	// подсунуть как HMAC секрет, а alg=none не проходит никогда
+	var methods []string
+	if len(opts.HMACSecret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(opts.RSAKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(opts.Leeway),
+		jwt.WithTimeFunc(opts.Now),
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+
+	return &JWTAuthenticator{opts: opts, parser: jwt.NewParser(parserOpts...)}, nil
+}
+
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	// Scope права через пробел (RFC 8693); scp — массивом, как у некоторых провайдеров
+	Scope  string   `json:"scope"`
+	Scopes []string `json:"scp"`
+	// Roles роли вызывающего, раскрываются в права через roleScopes
+	Roles []string `json:"roles"`
+}
+
+// Authenticate реализует Authenticator
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+	return a.Verify(strings.TrimSpace(token))
+}
+
+// Verify проверяет подпись и claims токена
+func (a *JWTAuthenticator) Verify(token string) (*Principal, error) {
+	var claims jwtClaims
+	if _, err := a.parser.ParseWithClaims(token, &claims, a.key); err != nil {
+		return nil, invalid("%v", err)
+	}
+	if claims.Subject == "" {
+		return nil, invalid("missing sub")
+	}
+
+	scopes := strings.Fields(claims.Scope)
+	scopes = append(scopes, claims.Scopes...)
+	scopes = append(scopes, claims.Roles...)
+
+	return &Principal{Subject: claims.Subject, Scopes: scopes, Method: "jwt"}, nil
+}
+
+// key выбирает ключ проверки подписи; алгоритм уже сверен с WithValidMethods
+func (a *JWTAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method {
+	case jwt.SigningMethodHS256:
+		return a.opts.HMACSecret, nil
+	case jwt.SigningMethodRS256:
+		kid, _ := token.Header["kid"].(string)
+		if key := a.rsaKey(kid); key != nil {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	default:
+		return nil, fmt.Errorf("unsupported alg %q", token.Method.Alg())
+	}
+}
+
+func (a *JWTAuthenticator) rsaKey(kid string) *rsa.PublicKey {
+	if key, ok := a.opts.RSAKeys[kid]; ok {
+		return key
+	}
+	if key, ok := a.opts.RSAKeys[""]; ok {
+		return key
+	}
+	// Токен без kid при единственном ключе
+	if kid == "" && len(a.opts.RSAKeys) == 1 {
+		for _, key := range a.opts.RSAKeys {
+			return key
+		}
+	}
+	return nil
+}
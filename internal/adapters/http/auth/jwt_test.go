@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal segment: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, secret []byte, header, claims map[string]interface{}) string {
+	t.Helper()
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, header, claims map[string]interface{}) string {
+	t.Helper()
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "1",
+		"iss":   "https://issuer.example",
+		"aud":   []string{"other", "ddd_example"},
+		"exp":   testNow.Add(time.Hour).Unix(),
+		"scope": "balance:read balance:write",
+	}
+}
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	return key
+}
+
+func TestJWTAuthenticator_HS256(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	a, err := NewJWTAuthenticator(JWTOptions{
+		HMACSecret: secret,
+		Issuer:     "https://issuer.example",
+		Audience:   "ddd_example",
+		Now:        func() time.Time { return testNow },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p, err := a.Verify(signHS256(t, secret, map[string]interface{}{"alg": "HS256", "typ": "JWT"}, validClaims()))
+	if err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+	if p.Subject != "1" || !p.HasScope("balance:write") || p.Method != "jwt" {
+		t.Errorf("unexpected principal: %+v", p)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(header, claims map[string]interface{})
+		secret []byte
+	}{
+		{name: "wrong secret", secret: []byte("another-secret-another-secret-xx")},
+		{name: "expired", mutate: func(_, c map[string]interface{}) { c["exp"] = testNow.Add(-time.Minute).Unix() }},
+		{name: "missing exp", mutate: func(_, c map[string]interface{}) { delete(c, "exp") }},
+		{name: "not yet valid", mutate: func(_, c map[string]interface{}) { c["nbf"] = testNow.Add(time.Minute).Unix() }},
+		{name: "wrong issuer", mutate: func(_, c map[string]interface{}) { c["iss"] = "https://evil.example" }},
+		{name: "wrong audience", mutate: func(_, c map[string]interface{}) { c["aud"] = "other" }},
+		{name: "missing sub", mutate: func(_, c map[string]interface{}) { delete(c, "sub") }},
+		{name: "alg none", mutate: func(h, _ map[string]interface{}) { h["alg"] = "none" }},
+		{name: "rs256 without keys", mutate: func(h, _ map[string]interface{}) { h["alg"] = "RS256" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := map[string]interface{}{"alg": "HS256"}
+			claims := validClaims()
+			if tt.mutate != nil {
+				tt.mutate(header, claims)
+			}
+			key := secret
+			if tt.secret != nil {
+				key = tt.secret
+			}
+
+			_, err := a.Verify(signHS256(t, key, header, claims))
+			if !errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("expected ErrInvalidCredentials, got %v", err)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticator_ClockLeeway(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	a, _ := NewJWTAuthenticator(JWTOptions{HMACSecret: secret, Now: func() time.Time { return testNow }})
+
+	claims := validClaims()
+	claims["exp"] = testNow.Add(-10 * time.Second).Unix()
+	if _, err := a.Verify(signHS256(t, secret, map[string]interface{}{"alg": "HS256"}, claims)); err != nil {
+		t.Errorf("expected token within leeway to be accepted, got %v", err)
+	}
+}
+
+func TestJWTAuthenticator_RS256WithJWKS(t *testing.T) {
+	key := newTestRSAKey(t)
+	other := newTestRSAKey(t)
+
+	jwks := map[string]interface{}{"keys": []map[string]interface{}{
+		{
+			"kty": "RSA", "kid": "primary", "use": "sig", "alg": "RS256",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		},
+		{"kty": "oct", "kid": "symmetric", "k": "c2VjcmV0"},
+	}}
+	data, _ := json.Marshal(jwks)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write jwks: %v", err)
+	}
+
+	keys, err := LoadJWKSFile(path)
+	if err != nil {
+		t.Fatalf("failed to load jwks: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("expected only the RSA signing key, got %d", len(keys))
+	}
+
+	a, _ := NewJWTAuthenticator(JWTOptions{RSAKeys: keys, Now: func() time.Time { return testNow }})
+
+	if _, err := a.Verify(signRS256(t, key, map[string]interface{}{"alg": "RS256", "kid": "primary"}, validClaims())); err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+	if _, err := a.Verify(signRS256(t, key, map[string]interface{}{"alg": "RS256"}, validClaims())); err != nil {
+		t.Errorf("expected token without kid to use the only key, got %v", err)
+	}
+	if _, err := a.Verify(signRS256(t, other, map[string]interface{}{"alg": "RS256", "kid": "primary"}, validClaims())); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected foreign signature to be rejected, got %v", err)
+	}
+	if _, err := a.Verify(signRS256(t, key, map[string]interface{}{"alg": "RS256", "kid": "rotated"}, validClaims())); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected unknown kid to be rejected, got %v", err)
+	}
+
+	// Открытый ключ, использованный как HMAC секрет, не должен проходить
+	pub := key.N.Bytes()
+	if _, err := a.Verify(signHS256(t, pub, map[string]interface{}{"alg": "HS256", "kid": "primary"}, validClaims())); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected HS256 with RSA-only config to be rejected, got %v", err)
+	}
+}
+
+func TestLoadRSAPublicKeyFile(t *testing.T) {
+	key := newTestRSAKey(t)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "public.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	pub, err := LoadRSAPublicKeyFile(path)
+	if err != nil {
+		t.Fatalf("failed to load key: %v", err)
+	}
+	if !pub.Equal(&key.PublicKey) {
+		t.Error("loaded key differs from the original")
+	}
+
+	a, _ := NewJWTAuthenticator(JWTOptions{RSAKeys: map[string]*rsa.PublicKey{"": pub}, Now: func() time.Time { return testNow }})
+	if _, err := a.Verify(signRS256(t, key, map[string]interface{}{"alg": "RS256", "kid": "any"}, validClaims())); err != nil {
+		t.Errorf("expected key without kid to verify any token, got %v", err)
+	}
+}
+
+func TestJWTAuthenticator_Malformed(t *testing.T) {
+	a, _ := NewJWTAuthenticator(JWTOptions{HMACSecret: []byte("secret")})
+
+	for _, token := range []string{"", "abc", "a.b", "a.b.c.d", "!!.e30.sig", strings.Repeat("x", 10) + ".e30.e30"} {
+		if _, err := a.Verify(token); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("token %q: expected ErrInvalidCredentials, got %v", token, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MicahParks/jwkset"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// LoadRSAPublicKeyFile читает открытый RSA ключ из PEM файла (PKIX, PKCS#1 или сертификат)
+func LoadRSAPublicKeyFile(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return key, nil
+}
+
+// LoadJWKSFile читает RSA ключи подписи из локального JWKS файла (RFC 7517).
+// Ключи других типов и ключи шифрования пропускаются.
+func LoadJWKSFile(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks: %w", err)
+	}
+
+	var set jwkset.JWKSMarshal
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, m := range set.Keys {
+		if m.KTY != jwkset.KtyRSA || (m.USE != "" && m.USE != jwkset.UseSig) || (m.ALG != "" && m.ALG != jwkset.AlgRS256) {
+			continue
+		}
+		k, err := jwkset.NewJWKFromMarshal(m, jwkset.JWKMarshalOptions{}, jwkset.JWKValidateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %q: %w", m.KID, err)
+		}
+		key, ok := k.Key().(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("jwk %q is %T, expected RSA public key", m.KID, k.Key())
+		}
+		if _, ok := keys[m.KID]; ok {
+			return nil, fmt.Errorf("duplicate jwk kid %q", m.KID)
+		}
+		keys[m.KID] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("jwks contains no RS256 signing keys")
+	}
+	return keys, nil
+}
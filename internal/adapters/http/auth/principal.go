@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strconv"
+)
+
+// Права (scopes), которые требуют маршруты
+const (
+	ScopeItemsRead      = "items:read"
+	ScopeBalanceRead    = "balance:read"
+	ScopeBalanceWrite   = "balance:withdraw"
+	ScopeBalanceDeposit = "balance:deposit"
+	ScopeAlertsRead     = "alerts:read"
+	ScopeAlertsWrite    = "alerts:write"
+	ScopeAccountsFreeze = "accounts:freeze"
+	ScopeCacheAdmin     = "cache:admin"
+	// ScopeAnyUser позволяет действовать от имени любого {id}, а не только своего
+	ScopeAnyUser = "users:any"
+)
+
+// Роли — именованные наборы прав. В токене или API ключе роль указывается наравне с правами.
+const (
+	// RoleReader клиент только для чтения: каталог и собственный баланс
+	RoleReader = "reader"
+	// RoleUser владелец счета: чтение, списание со своего счета и свои алерты
+	RoleUser = "user"
+	// RoleFinance финансовый оператор: списание и зачисление для любого пользователя
+	RoleFinance = "finance"
+	// RoleAdmin имеет все права
+	RoleAdmin = "admin"
+)
+
+// roleScopes права, которые дает каждая роль. Роль admin обрабатывается отдельно.
+var roleScopes = map[string][]string{
+	RoleReader:  {ScopeItemsRead, ScopeBalanceRead},
+	RoleUser:    {ScopeItemsRead, ScopeBalanceRead, ScopeBalanceWrite, ScopeAlertsRead, ScopeAlertsWrite},
+	RoleFinance: {ScopeItemsRead, ScopeBalanceRead, ScopeBalanceWrite, ScopeBalanceDeposit, ScopeAnyUser},
+}
+
+// Principal аутентифицированный вызывающий
+type Principal struct {
+	// Subject идентификатор вызывающего (claim sub или субъект API ключа)
+	Subject string
+	// Scopes права и роли, выданные токеном или ключом
+	Scopes []string
+	// Method способ аутентификации: jwt, api_key или none
+	Method string
+}
+
+// HasScope проверяет наличие права напрямую или через одну из ролей
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == RoleAdmin {
+			return true
+		}
+		if slices.Contains(roleScopes[s], scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// UserID возвращает id пользователя, если субъект — числовой id
+func (p *Principal) UserID() (int64, bool) {
+	id, err := strconv.ParseInt(p.Subject, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// CanAccessUser проверяет, может ли вызывающий действовать от имени пользователя:
+// это его собственный id или у него есть право users:any
+func (p *Principal) CanAccessUser(userID int64) bool {
+	if p.HasScope(ScopeAnyUser) {
+		return true
+	}
+	id, ok := p.UserID()
+	return ok && id == userID
+}
+
+type principalKey struct{}
+
+// WithPrincipal возвращает контекст с аутентифицированным вызывающим
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext извлекает вызывающего, сохраненного middleware
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
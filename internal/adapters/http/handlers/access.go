@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
+)
+
+// authorizeUser проверяет, что вызывающий действует от своего имени или имеет право users:any.
+// Права на сам маршрут проверяет middleware сервера, здесь — только принадлежность {id}.
+func authorizeUser(w http.ResponseWriter, r *http.Request, userID int64, logger *slog.Logger) bool {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, r, problem.New(http.StatusUnauthorized, problem.CodeUnauthenticated, "authentication required"), logger)
+		return false
+	}
+
+	if !principal.CanAccessUser(userID) {
+		logger.WarnContext(r.Context(), "access to another user denied",
+			slog.String("subject", principal.Subject),
+			slog.Int64("user_id", userID),
+			slog.String("path", r.URL.Path),
+		)
+		respondWithError(w, r, problem.New(http.StatusForbidden, problem.CodeForbidden, "access to another user is denied"), logger)
+		return false
+	}
+
+	return true
+}
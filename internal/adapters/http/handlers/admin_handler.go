@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+// AdminHandler обрабатывает административные HTTP запросы к кэшу.
+// Право cache:admin проверяет middleware сервера; субъект вызывающего попадает в журнал аудита.
+type AdminHandler struct {
+	service input.CacheAdminService
+	logger  *slog.Logger
+}
+
+// NewAdminHandler создает новый AdminHandler
+func NewAdminHandler(service input.CacheAdminService, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListCacheKeys обрабатывает GET /admin/cache/keys
+func (h *AdminHandler) ListCacheKeys(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.ListEntries(r.Context(), actor)
+	if err != nil {
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, map[string]interface{}{
+		"count": len(entries),
+		"keys":  entries,
+	}, h.logger)
+}
+
+// RefreshCatalogue обрабатывает POST /admin/cache/refresh
+func (h *AdminHandler) RefreshCatalogue(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RefreshCatalogue(r.Context(), actor); err != nil {
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, map[string]string{"status": "refreshed"}, h.logger)
+}
+
+// DeleteCacheKey обрабатывает DELETE /admin/cache/keys/{key...}
+func (h *AdminHandler) DeleteCacheKey(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	key := r.PathValue("key")
+	if key == "" {
+		respondWithError(w, r, problem.BadRequest("cache key is required"), h.logger)
+		return
+	}
+
+	if err := h.service.DeleteKey(r.Context(), actor, key); err != nil {
+		respondWithError(w, r, fmt.Errorf("failed to delete cache key %q: %w", key, err), h.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ClearCache обрабатывает DELETE /admin/cache
+func (h *AdminHandler) ClearCache(w http.ResponseWriter, r *http.Request) {
+	actor, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Clear(r.Context(), actor); err != nil {
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorize возвращает вызывающего для журнала аудита
+func (h *AdminHandler) authorize(w http.ResponseWriter, r *http.Request) (input.AdminActor, bool) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, r, problem.New(http.StatusUnauthorized, problem.CodeUnauthenticated, "authentication required"), h.logger)
+		return input.AdminActor{}, false
+	}
+
+	return input.AdminActor{Name: principal.Subject, RemoteAddr: r.RemoteAddr}, true
+}
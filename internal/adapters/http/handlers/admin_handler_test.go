@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+type mockCacheAdminService struct {
+	deleted []string
+	cleared int
+	actors  []input.AdminActor
+}
+
+func (m *mockCacheAdminService) ListEntries(_ context.Context, actor input.AdminActor) ([]input.CacheEntry, error) {
+	m.actors = append(m.actors, actor)
+	return []input.CacheEntry{{Key: "skinport:items", ExpiresInSeconds: 42, SizeBytes: 1024}}, nil
+}
+
+func (m *mockCacheAdminService) RefreshCatalogue(_ context.Context, actor input.AdminActor) error {
+	m.actors = append(m.actors, actor)
+	return nil
+}
+
+func (m *mockCacheAdminService) DeleteKey(_ context.Context, actor input.AdminActor, key string) error {
+	m.actors = append(m.actors, actor)
+	m.deleted = append(m.deleted, key)
+	return nil
+}
+
+func (m *mockCacheAdminService) Clear(_ context.Context, actor input.AdminActor) error {
+	m.actors = append(m.actors, actor)
+	m.cleared++
+	return nil
+}
+
+func newTestAdminMux() (*http.ServeMux, *mockCacheAdminService) {
+	service := &mockCacheAdminService{}
+	h := NewAdminHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/cache/keys", h.ListCacheKeys)
+	mux.HandleFunc("POST /admin/cache/refresh", h.RefreshCatalogue)
+	mux.HandleFunc("DELETE /admin/cache/keys/{key...}", h.DeleteCacheKey)
+	mux.HandleFunc("DELETE /admin/cache", h.ClearCache)
+	return mux, service
+}
+
+func serveAdmin(mux *http.ServeMux, method, path string, principal *auth.Principal) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if principal != nil {
+		req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminHandler_RequiresPrincipal(t *testing.T) {
+	mux, service := newTestAdminMux()
+
+	rec := serveAdmin(mux, http.MethodDelete, "/admin/cache", nil)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+	if service.cleared != 0 || len(service.actors) != 0 {
+		t.Fatal("service must not be called without a principal")
+	}
+}
+
+func TestAdminHandler_Endpoints(t *testing.T) {
+	mux, service := newTestAdminMux()
+	admin := &auth.Principal{Subject: "ops", Scopes: []string{auth.ScopeCacheAdmin}, Method: "api_key"}
+
+	rec := serveAdmin(mux, http.MethodGet, "/admin/cache/keys", admin)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Count int                `json:"count"`
+		Keys  []input.CacheEntry `json:"keys"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode list response: %v", err)
+	}
+	if body.Count != 1 || body.Keys[0].Key != "skinport:items" || body.Keys[0].SizeBytes != 1024 {
+		t.Errorf("unexpected list response: %+v", body)
+	}
+
+	if rec := serveAdmin(mux, http.MethodPost, "/admin/cache/refresh", admin); rec.Code != http.StatusOK {
+		t.Errorf("refresh: expected 200, got %d", rec.Code)
+	}
+
+	if rec := serveAdmin(mux, http.MethodDelete, "/admin/cache/keys/skinport:sales_history", admin); rec.Code != http.StatusNoContent {
+		t.Errorf("delete: expected 204, got %d", rec.Code)
+	}
+	if len(service.deleted) != 1 || service.deleted[0] != "skinport:sales_history" {
+		t.Errorf("unexpected deleted keys: %v", service.deleted)
+	}
+
+	if rec := serveAdmin(mux, http.MethodDelete, "/admin/cache", admin); rec.Code != http.StatusNoContent {
+		t.Errorf("clear: expected 204, got %d", rec.Code)
+	}
+	if service.cleared != 1 {
+		t.Errorf("expected cache to be cleared once, got %d", service.cleared)
+	}
+
+	// В журнал аудита попадает субъект вызывающего
+	for _, actor := range service.actors {
+		if actor.Name != "ops" || actor.RemoteAddr == "" {
+			t.Errorf("unexpected actor: %+v", actor)
+		}
+	}
+}
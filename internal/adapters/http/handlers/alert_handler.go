@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+// AlertHandler обрабатывает HTTP запросы для работы с ценовыми алертами
+type AlertHandler struct {
+	service input.AlertService
+	logger  *slog.Logger
+}
+
+// NewAlertHandler создает новый AlertHandler
+func NewAlertHandler(service input.AlertService, logger *slog.Logger) *AlertHandler {
+	return &AlertHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateAlertRequest представляет запрос на создание алерта
+type CreateAlertRequest struct {
+	MarketHashName string          `json:"market_hash_name"`
+	Direction      alert.Direction `json:"direction"`
+	PriceType      alert.PriceType `json:"price_type"`
+	Threshold      decimal.Decimal `json:"threshold"`
+	WebhookURL     string          `json:"webhook_url"`
+}
+
+// CreateAlert обрабатывает POST /users/{id}/alerts
+func (h *AlertHandler) CreateAlert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userIDStr := r.PathValue("id")
+	if userIDStr == "" {
+		respondWithError(w, r, problem.BadRequest("user id is required"), h.logger)
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, problem.BadRequest("invalid user id"), h.logger)
+		return
+	}
+
+	if !authorizeUser(w, r, userID, h.logger) {
+		return
+	}
+
+	var req CreateAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, problem.BadRequest("invalid request body"), h.logger)
+		return
+	}
+
+	created, err := h.service.CreateAlert(ctx, userID, input.CreateAlertRequest{
+		MarketHashName: req.MarketHashName,
+		Direction:      req.Direction,
+		PriceType:      req.PriceType,
+		Threshold:      req.Threshold,
+		WebhookURL:     req.WebhookURL,
+	})
+	if err != nil {
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusCreated, created, h.logger)
+}
+
+// ListAlerts обрабатывает GET /users/{id}/alerts
+func (h *AlertHandler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userIDStr := r.PathValue("id")
+	if userIDStr == "" {
+		respondWithError(w, r, problem.BadRequest("user id is required"), h.logger)
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, problem.BadRequest("invalid user id"), h.logger)
+		return
+	}
+
+	if !authorizeUser(w, r, userID, h.logger) {
+		return
+	}
+
+	alerts, err := h.service.ListAlerts(ctx, userID)
+	if err != nil {
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	if alerts == nil {
+		alerts = []*alert.Alert{}
+	}
+
+	respondWithJSON(w, r, http.StatusOK, alerts, h.logger)
+}
@@ -2,13 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
 	"github.com/akonovalovdev/DDD_example/internal/domain/user"
 	"github.com/akonovalovdev/DDD_example/internal/ports/input"
 )
@@ -45,48 +47,32 @@ func (h *BalanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Извлекаем userID из URL
-	userIDStr := r.PathValue("id")
-	if userIDStr == "" {
-		respondWithError(w, http.StatusBadRequest, "user id is required", h.logger)
-		return
-	}
-
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "invalid user id", h.logger)
+	userID, ok := h.parseUserID(w, r)
+	if !ok || !authorizeUser(w, r, userID, h.logger) {
 		return
 	}
 
 	// Декодируем тело запроса
 	var req WithdrawRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "invalid request body", h.logger)
+		respondWithError(w, r, problem.BadRequest("invalid request body"), h.logger)
 		return
 	}
 
 	// Валидация суммы
-	if req.Amount.LessThanOrEqual(decimal.Zero) {
-		respondWithError(w, http.StatusBadRequest, "amount must be positive", h.logger)
+	if !user.IsValidAmount(req.Amount) {
+		respondWithError(w, r, user.ErrInvalidAmount, h.logger)
 		return
 	}
 
 	// Выполняем списание
 	result, err := h.service.WithdrawBalance(ctx, userID, req.Amount)
 	if err != nil {
-		switch {
-		case errors.Is(err, user.ErrUserNotFound):
-			respondWithError(w, http.StatusNotFound, "user not found", h.logger)
-		case errors.Is(err, user.ErrInsufficientBalance):
-			respondWithError(w, http.StatusBadRequest, "insufficient balance", h.logger)
-		case errors.Is(err, user.ErrInvalidAmount):
-			respondWithError(w, http.StatusBadRequest, "invalid amount", h.logger)
-		default:
-			respondWithError(w, http.StatusInternalServerError, "internal server error", h.logger)
-		}
+		respondWithError(w, r, err, h.logger)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, WithdrawResponse{
+	respondWithJSON(w, r, http.StatusOK, WithdrawResponse{
 		Success:       true,
 		TransactionID: result.Transaction.ID.String(),
 		BalanceBefore: result.BalanceBefore,
@@ -94,34 +80,122 @@ func (h *BalanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 	}, h.logger)
 }
 
-// GetBalance обрабатывает GET /users/{id}/balance
-func (h *BalanceHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+// DepositRequest представляет запрос на зачисление
+type DepositRequest struct {
+	Amount decimal.Decimal `json:"amount"`
+}
+
+// DepositResponse представляет ответ на зачисление
+type DepositResponse struct {
+	Success       bool            `json:"success"`
+	TransactionID string          `json:"transaction_id"`
+	BalanceBefore decimal.Decimal `json:"balance_before"`
+	BalanceAfter  decimal.Decimal `json:"balance_after"`
+}
+
+// Deposit обрабатывает POST /users/{id}/deposit
+func (h *BalanceHandler) Deposit(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	userIDStr := r.PathValue("id")
-	if userIDStr == "" {
-		respondWithError(w, http.StatusBadRequest, "user id is required", h.logger)
+	userID, ok := h.parseUserID(w, r)
+	if !ok || !authorizeUser(w, r, userID, h.logger) {
 		return
 	}
 
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	var req DepositRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, problem.BadRequest("invalid request body"), h.logger)
+		return
+	}
+
+	if !user.IsValidAmount(req.Amount) {
+		respondWithError(w, r, user.ErrInvalidAmount, h.logger)
+		return
+	}
+
+	result, err := h.service.DepositBalance(ctx, userID, req.Amount)
+	if err != nil {
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, DepositResponse{
+		Success:       true,
+		TransactionID: result.Transaction.ID.String(),
+		BalanceBefore: result.BalanceBefore,
+		BalanceAfter:  result.BalanceAfter,
+	}, h.logger)
+}
+
+// Freeze обрабатывает POST /users/{id}/freeze
+func (h *BalanceHandler) Freeze(w http.ResponseWriter, r *http.Request) {
+	h.setFrozen(w, r, true)
+}
+
+// Unfreeze обрабатывает DELETE /users/{id}/freeze
+func (h *BalanceHandler) Unfreeze(w http.ResponseWriter, r *http.Request) {
+	h.setFrozen(w, r, false)
+}
+
+func (h *BalanceHandler) setFrozen(w http.ResponseWriter, r *http.Request, frozen bool) {
+	ctx := r.Context()
+
+	userID, ok := h.parseUserID(w, r)
+	if !ok || !authorizeUser(w, r, userID, h.logger) {
+		return
+	}
+
+	var err error
+	if frozen {
+		err = h.service.FreezeAccount(ctx, userID)
+	} else {
+		err = h.service.UnfreezeAccount(ctx, userID)
+	}
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "invalid user id", h.logger)
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, map[string]interface{}{
+		"user_id": userID,
+		"frozen":  frozen,
+	}, h.logger)
+}
+
+// GetBalance обрабатывает GET /users/{id}/balance
+func (h *BalanceHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := h.parseUserID(w, r)
+	if !ok || !authorizeUser(w, r, userID, h.logger) {
 		return
 	}
 
 	balance, err := h.service.GetBalance(ctx, userID)
 	if err != nil {
-		if errors.Is(err, user.ErrUserNotFound) {
-			respondWithError(w, http.StatusNotFound, "user not found", h.logger)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "internal server error", h.logger)
+		respondWithError(w, r, err, h.logger)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+	respondWithJSON(w, r, http.StatusOK, map[string]interface{}{
 		"user_id": userID,
 		"balance": balance,
 	}, h.logger)
 }
+
+func (h *BalanceHandler) parseUserID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userIDStr := r.PathValue("id")
+	if userIDStr == "" {
+		respondWithError(w, r, problem.BadRequest("user id is required"), h.logger)
+		return 0, false
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		respondWithError(w, r, problem.BadRequest("invalid user id"), h.logger)
+		return 0, false
+	}
+
+	trace.SpanFromContext(r.Context()).SetAttributes(attribute.Int64("user.id", userID))
+	return userID, true
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
+	"github.com/akonovalovdev/DDD_example/internal/domain/transaction"
+	"github.com/akonovalovdev/DDD_example/internal/domain/user"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+type mockBalanceService struct {
+	withdrawn []int64
+	deposited []int64
+	frozen    map[int64]bool
+}
+
+func (m *mockBalanceService) WithdrawBalance(_ context.Context, userID int64, amount decimal.Decimal) (*input.WithdrawResult, error) {
+	m.withdrawn = append(m.withdrawn, userID)
+	return &input.WithdrawResult{
+		Transaction:   &transaction.Transaction{ID: uuid.New()},
+		BalanceBefore: decimal.NewFromInt(1000),
+		BalanceAfter:  decimal.NewFromInt(1000).Sub(amount),
+	}, nil
+}
+
+func (m *mockBalanceService) DepositBalance(_ context.Context, userID int64, amount decimal.Decimal) (*input.DepositResult, error) {
+	if m.frozen[userID] {
+		return nil, user.ErrAccountFrozen
+	}
+	m.deposited = append(m.deposited, userID)
+	return &input.DepositResult{
+		Transaction:   &transaction.Transaction{ID: uuid.New()},
+		BalanceBefore: decimal.NewFromInt(1000),
+		BalanceAfter:  decimal.NewFromInt(1000).Add(amount),
+	}, nil
+}
+
+func (m *mockBalanceService) FreezeAccount(_ context.Context, userID int64) error {
+	m.frozen[userID] = true
+	return nil
+}
+
+func (m *mockBalanceService) UnfreezeAccount(_ context.Context, userID int64) error {
+	delete(m.frozen, userID)
+	return nil
+}
+
+func (m *mockBalanceService) GetBalance(context.Context, int64) (decimal.Decimal, error) {
+	return decimal.NewFromInt(1000), nil
+}
+
+func newBalanceMux(service *mockBalanceService) *http.ServeMux {
+	h := NewBalanceHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users/{id}/withdraw", h.Withdraw)
+	mux.HandleFunc("POST /users/{id}/deposit", h.Deposit)
+	mux.HandleFunc("GET /users/{id}/balance", h.GetBalance)
+	mux.HandleFunc("POST /users/{id}/freeze", h.Freeze)
+	mux.HandleFunc("DELETE /users/{id}/freeze", h.Unfreeze)
+	return mux
+}
+
+func serveBalance(principal *auth.Principal, method, path, body string) (*httptest.ResponseRecorder, *mockBalanceService) {
+	service := &mockBalanceService{frozen: make(map[int64]bool)}
+	mux := newBalanceMux(service)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if principal != nil {
+		req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec, service
+}
+
+func TestBalanceHandler_Ownership(t *testing.T) {
+	owner := &auth.Principal{Subject: "1", Method: "jwt"}
+	finance := &auth.Principal{Subject: "ops", Scopes: []string{auth.RoleFinance}, Method: "api_key"}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		method    string
+		path      string
+		want      int
+	}{
+		{"owner reads balance", owner, http.MethodGet, "/users/1/balance", http.StatusOK},
+		{"owner withdraws", owner, http.MethodPost, "/users/1/withdraw", http.StatusOK},
+		{"other user's balance", owner, http.MethodGet, "/users/2/balance", http.StatusForbidden},
+		{"other user's withdraw", owner, http.MethodPost, "/users/2/withdraw", http.StatusForbidden},
+		{"finance withdraws for anyone", finance, http.MethodPost, "/users/2/withdraw", http.StatusOK},
+		{"finance deposits for anyone", finance, http.MethodPost, "/users/2/deposit", http.StatusOK},
+		{"owner freezes other account", owner, http.MethodPost, "/users/2/freeze", http.StatusForbidden},
+		{"no principal", nil, http.MethodPost, "/users/1/withdraw", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, service := serveBalance(tt.principal, tt.method, tt.path, `{"amount": "10.00"}`)
+			if rec.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if tt.want != http.StatusOK && (len(service.withdrawn) != 0 || len(service.deposited) != 0) {
+				t.Error("service must not be called for a rejected request")
+			}
+		})
+	}
+}
+
+func TestBalanceHandler_FrozenAccount(t *testing.T) {
+	service := &mockBalanceService{frozen: make(map[int64]bool)}
+	mux := newBalanceMux(service)
+	admin := &auth.Principal{Subject: "root", Scopes: []string{auth.RoleAdmin}, Method: "jwt"}
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(auth.WithPrincipal(req.Context(), admin))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve(http.MethodPost, "/users/1/freeze", ""); rec.Code != http.StatusOK {
+		t.Fatalf("freeze: expected 200, got %d", rec.Code)
+	}
+	rec := serve(http.MethodPost, "/users/1/deposit", `{"amount": "10.00"}`)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("deposit to frozen account: expected 409, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != problem.ContentType {
+		t.Errorf("expected %s, got %q", problem.ContentType, ct)
+	}
+	var body problem.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	if body.Code != problem.CodeAccountFrozen || body.Instance != "/users/1/deposit" {
+		t.Errorf("unexpected problem: %+v", body)
+	}
+	if rec := serve(http.MethodDelete, "/users/1/freeze", ""); rec.Code != http.StatusOK {
+		t.Fatalf("unfreeze: expected 200, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPost, "/users/1/deposit", `{"amount": "10.00"}`); rec.Code != http.StatusOK {
+		t.Errorf("deposit after unfreeze: expected 200, got %d", rec.Code)
+	}
+}
+
+func TestBalanceHandler_RejectsSubCentAmount(t *testing.T) {
+	owner := &auth.Principal{Subject: "1", Method: "jwt"}
+
+	for _, path := range []string{"/users/1/withdraw", "/users/1/deposit"} {
+		t.Run(path, func(t *testing.T) {
+			rec, service := serveBalance(owner, http.MethodPost, path, `{"amount": "10.005"}`)
+			if rec.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var body problem.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode problem: %v", err)
+			}
+			if body.Code != problem.CodeInvalidAmount {
+				t.Errorf("expected code %s, got %s", problem.CodeInvalidAmount, body.Code)
+			}
+			if len(service.withdrawn) != 0 || len(service.deposited) != 0 {
+				t.Error("service must not be called for a rejected amount")
+			}
+		})
+	}
+}
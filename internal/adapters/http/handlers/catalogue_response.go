@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+const (
+	encodingBrotli   = "br"
+	encodingGzip     = "gzip"
+	encodingIdentity = "identity"
+
+	// catalogueBrotliQuality компромисс между размером и временем: каталог перерисовывается
+	// при каждой новой версии, включая пачки событий ленты продаж, поэтому максимальное качество слишком медленное
+	catalogueBrotliQuality = 6
+)
+
+// renderedCatalogue сериализованная и сжатая версия каталога, готовая к отдаче
+type renderedCatalogue struct {
+	version      uint64
+	etag         string            // базовый тег без кавычек, от содержимого
+	lastModified time.Time         // с полной точностью; в заголовок попадают целые секунды
+	bodies       map[string][]byte // тело по content-coding
+}
+
+// renderCatalogue сериализует каталог один раз и заранее сжимает его gzip и brotli
+func renderCatalogue(cat *item.Catalogue) (*renderedCatalogue, error) {
+	var identity bytes.Buffer
+	if err := json.NewEncoder(&identity).Encode(cat.Items); err != nil {
+		return nil, err
+	}
+
+	var gz bytes.Buffer
+	gw, err := gzip.NewWriterLevel(&gz, gzip.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gw.Write(identity.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+
+	var br bytes.Buffer
+	bw := brotli.NewWriterLevel(&br, catalogueBrotliQuality)
+	if _, err := bw.Write(identity.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := bw.Close(); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(identity.Bytes())
+
+	return &renderedCatalogue{
+		version:      cat.Version,
+		etag:         hex.EncodeToString(sum[:16]),
+		lastModified: cat.UpdatedAt.UTC(),
+		bodies: map[string][]byte{
+			encodingIdentity: identity.Bytes(),
+			encodingGzip:     gz.Bytes(),
+			encodingBrotli:   br.Bytes(),
+		},
+	}, nil
+}
+
+// etagFor возвращает строгий ETag представления: у каждой content-coding свой тег,
+// так как сжатые байты различаются
+func (rc *renderedCatalogue) etagFor(encoding string) string {
+	if encoding == encodingIdentity {
+		return `"` + rc.etag + `"`
+	}
+	return `"` + rc.etag + "-" + encoding + `"`
+}
+
+// notModified проверяет условные заголовки запроса (RFC 9110, раздел 13).
+// If-None-Match имеет приоритет; If-Modified-Since учитывается только без него.
+// Дата в If-Modified-Since с точностью до секунды, поэтому версия, записанная позже в ту же
+// секунду, считается измененной: надежный 304 внутри секунды дает только ETag.
+func (rc *renderedCatalogue) notModified(r *http.Request) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return rc.matchesAny(inm)
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !rc.lastModified.After(t)
+	}
+
+	return false
+}
+
+// matchesAny реализует слабое сравнение If-None-Match с любым из представлений каталога
+func (rc *renderedCatalogue) matchesAny(header string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		tag = strings.TrimPrefix(tag, "W/")
+		for encoding := range rc.bodies {
+			if tag == rc.etagFor(encoding) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// negotiateEncoding выбирает content-coding по Accept-Encoding с учетом q-значений.
+// При равном весе предпочитается brotli, затем gzip.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return encodingIdentity
+	}
+
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(key), "q") {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		weights[name] = q
+	}
+
+	weight := func(encoding string) float64 {
+		if q, ok := weights[encoding]; ok {
+			return q
+		}
+		if q, ok := weights["*"]; ok {
+			return q
+		}
+		return 0
+	}
+
+	best, bestQ := encodingIdentity, 0.0
+	for _, encoding := range []string{encodingBrotli, encodingGzip} {
+		if q := weight(encoding); q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
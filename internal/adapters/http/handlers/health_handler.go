@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Статусы ответа GET /readyz
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusDegraded    = "degraded"
+	HealthStatusUnavailable = "unavailable"
+	HealthStatusDraining    = "draining"
+	HealthStatusFail        = "fail"
+)
+
+// HealthCheck проверка зависимости для GET /readyz
+type HealthCheck struct {
+	Name string
+	// Critical отказ проверки снимает реплику с балансировки (503); отказ некритичной
+	// проверки только помечает ответ как degraded
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// HealthHandler обрабатывает пробы живости и готовности
+type HealthHandler struct {
+	checks   []HealthCheck
+	timeout  time.Duration
+	draining atomic.Bool
+	logger   *slog.Logger
+}
+
+// NewHealthHandler создает новый HealthHandler. timeout ограничивает все проверки одного запроса.
+func NewHealthHandler(checks []HealthCheck, timeout time.Duration, logger *slog.Logger) *HealthHandler {
+	return &HealthHandler{
+		checks:  checks,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// Drain переводит готовность в 503 до конца жизни процесса: балансировщик перестает
+// направлять новые запросы, пока сервер дообслуживает текущие
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+type checkResult struct {
+	Status     string  `json:"status"`
+	Critical   bool    `json:"critical"`
+	DurationMS float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type readinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks,omitempty"`
+}
+
+// Live обрабатывает GET /livez: процесс жив и обслуживает HTTP. Зависимости не проверяются,
+// чтобы отказ базы данных не приводил к перезапуску всех реплик.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, r, http.StatusOK, readinessResponse{Status: HealthStatusOK}, h.logger)
+}
+
+// Ready обрабатывает GET /readyz: реплика готова принимать трафик
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		respondWithJSON(w, r, http.StatusServiceUnavailable, readinessResponse{Status: HealthStatusDraining}, h.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	results := make([]checkResult, len(h.checks))
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	resp := readinessResponse{Status: HealthStatusOK, Checks: make(map[string]checkResult, len(results))}
+	for i, res := range results {
+		resp.Checks[h.checks[i].Name] = res
+		if res.Status == HealthStatusOK {
+			continue
+		}
+		if res.Critical {
+			resp.Status = HealthStatusUnavailable
+		} else if resp.Status == HealthStatusOK {
+			resp.Status = HealthStatusDegraded
+		}
+	}
+
+	status := http.StatusOK
+	if resp.Status == HealthStatusUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	respondWithJSON(w, r, status, resp, h.logger)
+}
+
+func (h *HealthHandler) run(ctx context.Context, check HealthCheck) checkResult {
+	start := time.Now()
+	err := check.Check(ctx)
+	res := checkResult{
+		Status:     HealthStatusOK,
+		Critical:   check.Critical,
+		DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err == nil {
+		return res
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", h.timeout)
+	}
+	h.logger.WarnContext(ctx, "readiness check failed", slog.String("check", check.Name), slog.Any("error", err))
+
+	res.Status = HealthStatusFail
+	res.Error = err.Error()
+	return res
+}
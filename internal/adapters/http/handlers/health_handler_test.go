@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func passing(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("connection refused") }
+
+func hanging(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []HealthCheck
+		wantCode   int
+		wantStatus string
+		wantFailed map[string]string
+	}{
+		{
+			name: "all checks pass",
+			checks: []HealthCheck{
+				{Name: "database", Critical: true, Check: passing},
+				{Name: "skinport", Check: passing},
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: HealthStatusOK,
+		},
+		{
+			name: "non-critical failure degrades",
+			checks: []HealthCheck{
+				{Name: "database", Critical: true, Check: passing},
+				{Name: "skinport", Check: failing},
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: HealthStatusDegraded,
+			wantFailed: map[string]string{"skinport": "connection refused"},
+		},
+		{
+			name: "critical failure takes replica out",
+			checks: []HealthCheck{
+				{Name: "database", Critical: true, Check: failing},
+				{Name: "skinport", Check: failing},
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: HealthStatusUnavailable,
+			wantFailed: map[string]string{"database": "connection refused", "skinport": "connection refused"},
+		},
+		{
+			name: "slow check times out",
+			checks: []HealthCheck{
+				{Name: "database", Critical: true, Check: hanging},
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: HealthStatusUnavailable,
+			wantFailed: map[string]string{"database": "timed out after 20ms"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(tt.checks, 20*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			rec := httptest.NewRecorder()
+			h.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, rec.Code)
+			}
+			var resp readinessResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, resp.Status)
+			}
+			if len(resp.Checks) != len(tt.checks) {
+				t.Fatalf("expected %d checks in breakdown, got %v", len(tt.checks), resp.Checks)
+			}
+			for name, res := range resp.Checks {
+				if want, failed := tt.wantFailed[name]; failed != (res.Status == HealthStatusFail) || res.Error != want {
+					t.Errorf("check %s: got %+v, want error %q", name, res, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthHandler_Drain(t *testing.T) {
+	called := false
+	h := NewHealthHandler([]HealthCheck{{Name: "database", Critical: true, Check: func(context.Context) error {
+		called = true
+		return nil
+	}}}, time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	h.Drain()
+
+	rec := httptest.NewRecorder()
+	h.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while draining, got %d", rec.Code)
+	}
+	if called {
+		t.Error("checks must not run while draining")
+	}
+
+	// Живость при этом не меняется
+	rec = httptest.NewRecorder()
+	h.Live(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 from /livez while draining, got %d", rec.Code)
+	}
+}
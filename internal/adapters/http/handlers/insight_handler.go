@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+const (
+	// defaultInsightsLimit количество предметов в выдаче по умолчанию
+	defaultInsightsLimit = 50
+	// maxInsightsLimit максимальное количество предметов в выдаче
+	maxInsightsLimit = 500
+)
+
+// InsightHandler обрабатывает HTTP запросы аналитики по каталогу
+type InsightHandler struct {
+	service input.InsightService
+	logger  *slog.Logger
+}
+
+// NewInsightHandler создает новый InsightHandler
+func NewInsightHandler(service input.InsightService, logger *slog.Logger) *InsightHandler {
+	return &InsightHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetInsights обрабатывает GET /items/insights?sort=&fee_percent=&min_quantity=&limit=
+func (h *InsightHandler) GetInsights(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	q := input.InsightQuery{
+		SortBy: item.InsightSortSpread,
+		Limit:  defaultInsightsLimit,
+	}
+
+	if v := query.Get("sort"); v != "" {
+		sortBy, err := item.ParseInsightSort(v)
+		if err != nil {
+			respondWithError(w, r, err, h.logger)
+			return
+		}
+		q.SortBy = sortBy
+	}
+
+	if v := query.Get("fee_percent"); v != "" {
+		fee, err := decimal.NewFromString(v)
+		if err != nil {
+			respondWithError(w, r, problem.BadRequest("invalid fee_percent"), h.logger)
+			return
+		}
+		q.FeePercent = &fee
+	}
+
+	if v := query.Get("min_quantity"); v != "" {
+		minQuantity, err := strconv.Atoi(v)
+		if err != nil || minQuantity < 0 {
+			respondWithError(w, r, problem.BadRequest("invalid min_quantity"), h.logger)
+			return
+		}
+		q.MinQuantity = &minQuantity
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxInsightsLimit {
+			respondWithError(w, r, problem.BadRequest("invalid limit: must be between 1 and 500"), h.logger)
+			return
+		}
+		q.Limit = limit
+	}
+
+	insights, err := h.service.GetInsights(ctx, q)
+	if err != nil {
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, insights, h.logger)
+}
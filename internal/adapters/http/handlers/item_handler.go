@@ -2,48 +2,187 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
 
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
 	"github.com/akonovalovdev/DDD_example/internal/ports/input"
 )
 
+// includeSalesHistory значение параметра include, добавляющее историю продаж в ответ GET /items
+const includeSalesHistory = "sales_history"
+
 // ItemHandler обрабатывает HTTP запросы для работы с предметами
 type ItemHandler struct {
 	service input.ItemService
+	history input.SalesHistoryService
 	logger  *slog.Logger
+
+	// rendered хранит каталог, сериализованный для последней версии
+	renderMu sync.Mutex
+	rendered atomic.Pointer[renderedCatalogue]
 }
 
 // NewItemHandler создает новый ItemHandler
-func NewItemHandler(service input.ItemService, logger *slog.Logger) *ItemHandler {
+func NewItemHandler(service input.ItemService, history input.SalesHistoryService, logger *slog.Logger) *ItemHandler {
 	return &ItemHandler{
 		service: service,
+		history: history,
 		logger:  logger,
 	}
 }
 
-// GetItems обрабатывает GET /items
+// SalesPeriods представляет статистику продаж предмета по периодам
+type SalesPeriods struct {
+	Last24Hours item.SalesStats `json:"last_24_hours"`
+	Last7Days   item.SalesStats `json:"last_7_days"`
+	Last30Days  item.SalesStats `json:"last_30_days"`
+	Last90Days  item.SalesStats `json:"last_90_days"`
+}
+
+// ItemWithHistory представляет предмет с опциональной историей продаж
+type ItemWithHistory struct {
+	*item.Item
+	SalesHistory *SalesPeriods `json:"sales_history,omitempty"`
+}
+
+// GetItems обрабатывает GET /items.
+// Каталог отдается из заранее сериализованного и сжатого представления с ETag и Last-Modified,
+// поэтому повторные запросы без изменений получают 304 без тела.
 func (h *ItemHandler) GetItems(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if r.URL.Query().Get("include") == includeSalesHistory {
+		h.getItemsWithHistory(w, r)
+		return
+	}
+
+	cat, err := h.service.GetCatalogue(ctx)
+	if err != nil {
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	rendered, err := h.renderedCatalogue(cat)
+	if err != nil {
+		respondWithError(w, r, fmt.Errorf("failed to render catalogue: %w", err), h.logger)
+		return
+	}
+
+	encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+
+	header := w.Header()
+	header.Set("ETag", rendered.etagFor(encoding))
+	header.Set("Last-Modified", rendered.lastModified.Format(http.TimeFormat))
+	header.Set("Cache-Control", "no-cache")
+	header.Add("Vary", "Accept-Encoding")
+
+	if rendered.notModified(r) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body := rendered.bodies[encoding]
+	header.Set("Content-Type", "application/json")
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	if encoding != encodingIdentity {
+		header.Set("Content-Encoding", encoding)
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		h.logger.WarnContext(r.Context(), "Failed to write response", "error", err)
+	}
+}
+
+// renderedCatalogue возвращает сериализованный каталог, перерисовывая его один раз на версию
+func (h *ItemHandler) renderedCatalogue(cat *item.Catalogue) (*renderedCatalogue, error) {
+	if rc := h.rendered.Load(); rc != nil && rc.version >= cat.Version {
+		return rc, nil
+	}
+
+	h.renderMu.Lock()
+	defer h.renderMu.Unlock()
+
+	// Повторная проверка: пока ждали блокировку, версию мог отрисовать другой запрос
+	if rc := h.rendered.Load(); rc != nil && rc.version >= cat.Version {
+		return rc, nil
+	}
+
+	rc, err := renderCatalogue(cat)
+	if err != nil {
+		return nil, err
+	}
+	h.rendered.Store(rc)
+	return rc, nil
+}
+
+// getItemsWithHistory отдает каталог вместе с историей продаж (без предварительной сериализации)
+func (h *ItemHandler) getItemsWithHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
 	items, err := h.service.GetItems(ctx)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to fetch items", h.logger)
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	// История продаж опциональна: при ошибке отдаем предметы без нее
+	histories, err := h.history.GetSalesHistories(ctx)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "failed to fetch sales history, responding without it", slog.Any("error", err))
+	}
+
+	result := make([]ItemWithHistory, len(items))
+	for i, it := range items {
+		result[i] = ItemWithHistory{Item: it}
+		if sh, ok := histories[it.MarketHashName]; ok {
+			result[i].SalesHistory = &SalesPeriods{
+				Last24Hours: sh.Last24Hours,
+				Last7Days:   sh.Last7Days,
+				Last30Days:  sh.Last30Days,
+				Last90Days:  sh.Last90Days,
+			}
+		}
+	}
+
+	respondWithJSON(w, r, http.StatusOK, result, h.logger)
+}
+
+// GetSalesHistory обрабатывает GET /items/{market_hash_name}/history
+func (h *ItemHandler) GetSalesHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	name := r.PathValue("market_hash_name")
+	if name == "" {
+		respondWithError(w, r, problem.BadRequest("market hash name is required"), h.logger)
+		return
+	}
+
+	history, err := h.history.GetSalesHistory(ctx, name)
+	if err != nil {
+		respondWithError(w, r, err, h.logger)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, items, h.logger)
+	respondWithJSON(w, r, http.StatusOK, history, h.logger)
 }
 
-func respondWithJSON(w http.ResponseWriter, status int, data interface{}, logger *slog.Logger) {
+func respondWithJSON(w http.ResponseWriter, r *http.Request, status int, data interface{}, logger *slog.Logger) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	err := json.NewEncoder(w).Encode(data)
 	if err != nil {
-		logger.Warn("Failed to encode response", "error", err)
+		logger.WarnContext(r.Context(), "Failed to encode response", "error", err)
 	}
 }
 
-func respondWithError(w http.ResponseWriter, status int, message string, logger *slog.Logger) {
-	respondWithJSON(w, status, map[string]string{"error": message}, logger)
+// respondWithError отвечает ошибкой в формате application/problem+json. Доменные ошибки
+// переводятся в код и статус единой таблицей пакета problem.
+func respondWithError(w http.ResponseWriter, r *http.Request, err error, logger *slog.Logger) {
+	problem.Write(w, r, err, logger)
 }
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/logging"
+)
+
+type mockItemService struct {
+	catalogue *item.Catalogue
+}
+
+func (m *mockItemService) GetItems(context.Context) ([]*item.Item, error) {
+	return m.catalogue.Items, nil
+}
+
+func (m *mockItemService) GetCatalogue(context.Context) (*item.Catalogue, error) {
+	return m.catalogue, nil
+}
+
+func newTestItemHandler() (*ItemHandler, *mockItemService) {
+	price := decimal.NewFromFloat(12.5)
+	service := &mockItemService{catalogue: &item.Catalogue{
+		Items:     []*item.Item{{MarketHashName: "AK-47 | Redline (Field-Tested)", Currency: "USD", TradableMinPrice: &price, Quantity: 3}},
+		Version:   1,
+		UpdatedAt: time.Date(2024, 1, 15, 10, 30, 0, 500, time.UTC),
+	}}
+	return NewItemHandler(service, nil, slog.New(slog.NewTextHandler(io.Discard, nil))), service
+}
+
+func getItems(h *ItemHandler, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.GetItems(rec, req)
+	return rec
+}
+
+func TestItemHandler_GetItems_ConditionalRequests(t *testing.T) {
+	h, service := newTestItemHandler()
+
+	first := getItems(h, nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", first.Code)
+	}
+	etag := first.Header().Get("ETag")
+	lastModified := first.Header().Get("Last-Modified")
+	if etag == "" || etag[0] != '"' {
+		t.Fatalf("expected strong ETag, got %q", etag)
+	}
+	if lastModified != "Mon, 15 Jan 2024 10:30:00 GMT" {
+		t.Errorf("unexpected Last-Modified %q", lastModified)
+	}
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected int
+	}{
+		{"matching etag", http.Header{"If-None-Match": {etag}}, http.StatusNotModified},
+		{"weak form of etag", http.Header{"If-None-Match": {"W/" + etag}}, http.StatusNotModified},
+		{"etag in list", http.Header{"If-None-Match": {`"other", ` + etag}}, http.StatusNotModified},
+		{"wildcard", http.Header{"If-None-Match": {"*"}}, http.StatusNotModified},
+		{"stale etag", http.Header{"If-None-Match": {`"stale"`}}, http.StatusOK},
+		{"not modified since", http.Header{"If-Modified-Since": {"Mon, 15 Jan 2024 10:30:01 GMT"}}, http.StatusNotModified},
+		// Версия записана позже в ту же секунду: ее Last-Modified не подтверждает актуальность
+		{"modified within the second", http.Header{"If-Modified-Since": {lastModified}}, http.StatusOK},
+		{"modified since", http.Header{"If-Modified-Since": {"Sun, 14 Jan 2024 10:30:00 GMT"}}, http.StatusOK},
+		{"etag takes precedence", http.Header{"If-None-Match": {`"stale"`}, "If-Modified-Since": {lastModified}}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getItems(h, tt.header)
+			if rec.Code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, rec.Code)
+			}
+			if tt.expected == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("expected empty body for 304, got %d bytes", rec.Body.Len())
+			}
+		})
+	}
+
+	// Новая версия каталога с другим содержимым — прежний ETag больше не совпадает
+	price := decimal.NewFromFloat(11)
+	service.catalogue = &item.Catalogue{
+		Items:     []*item.Item{{MarketHashName: "AK-47 | Redline (Field-Tested)", Currency: "USD", TradableMinPrice: &price, Quantity: 4}},
+		Version:   2,
+		UpdatedAt: time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC),
+	}
+	if rec := getItems(h, http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 after catalogue change, got %d", rec.Code)
+	}
+}
+
+func TestItemHandler_GetItems_Compression(t *testing.T) {
+	h, _ := newTestItemHandler()
+
+	plain := getItems(h, nil)
+	if plain.Header().Get("Content-Encoding") != "" {
+		t.Errorf("expected identity encoding, got %q", plain.Header().Get("Content-Encoding"))
+	}
+	if plain.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("expected Vary: Accept-Encoding, got %q", plain.Header().Get("Vary"))
+	}
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"br":   func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+	}
+
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"gzip, deflate, br", "br"},
+		{"gzip", "gzip"},
+		{"br;q=0.5, gzip;q=0.8", "gzip"},
+		{"*", "br"},
+		{"deflate", ""},
+		{"br;q=0, gzip;q=0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			rec := getItems(h, http.Header{"Accept-Encoding": {tt.acceptEncoding}})
+			if got := rec.Header().Get("Content-Encoding"); got != tt.expected {
+				t.Fatalf("expected encoding %q, got %q", tt.expected, got)
+			}
+			if rec.Header().Get("ETag") == plain.Header().Get("ETag") && tt.expected != "" {
+				t.Error("expected compressed representation to have its own ETag")
+			}
+
+			body := rec.Body.Bytes()
+			if decode, ok := decoders[tt.expected]; ok {
+				r, err := decode(bytes.NewReader(body))
+				if err != nil {
+					t.Fatalf("failed to create decoder: %v", err)
+				}
+				if body, err = io.ReadAll(r); err != nil {
+					t.Fatalf("failed to decompress: %v", err)
+				}
+			}
+			if !bytes.Equal(body, plain.Body.Bytes()) {
+				t.Error("expected decompressed body to match identity body")
+			}
+		})
+	}
+}
+
+func TestItemHandler_GetItems_RendersOncePerVersion(t *testing.T) {
+	h, _ := newTestItemHandler()
+
+	getItems(h, nil)
+	first := h.rendered.Load()
+	getItems(h, http.Header{"Accept-Encoding": {"gzip"}})
+
+	if h.rendered.Load() != first {
+		t.Error("expected catalogue to be rendered once per version")
+	}
+}
+
+func TestRespondWithJSON_LogsEncodeErrorWithRequestID(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(logging.NewContextHandler(slog.NewJSONHandler(&logs, nil)))
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req = req.WithContext(logging.WithRequestID(req.Context(), "req-42"))
+	respondWithJSON(httptest.NewRecorder(), req, http.StatusOK, map[string]interface{}{"bad": make(chan int)}, logger)
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("expected one JSON log record, got %q: %v", logs.String(), err)
+	}
+	if record[logging.RequestIDKey] != "req-42" {
+		t.Errorf("expected request_id in encode failure log, got %v", record)
+	}
+}
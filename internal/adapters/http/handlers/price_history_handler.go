@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+const (
+	// defaultPriceHistoryRange период истории цен по умолчанию
+	defaultPriceHistoryRange = 24 * time.Hour
+	// defaultPriceHistoryInterval шаг агрегации по умолчанию
+	defaultPriceHistoryInterval = time.Hour
+)
+
+// PriceHistoryHandler обрабатывает HTTP запросы собственной истории цен
+type PriceHistoryHandler struct {
+	service input.PriceHistoryService
+	logger  *slog.Logger
+}
+
+// NewPriceHistoryHandler создает новый PriceHistoryHandler
+func NewPriceHistoryHandler(service input.PriceHistoryService, logger *slog.Logger) *PriceHistoryHandler {
+	return &PriceHistoryHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// PriceHistoryResponse представляет ответ с историей цен предмета
+type PriceHistoryResponse struct {
+	MarketHashName string              `json:"market_hash_name"`
+	From           time.Time           `json:"from"`
+	To             time.Time           `json:"to"`
+	Interval       string              `json:"interval"`
+	Candles        []*item.PriceCandle `json:"candles"`
+}
+
+// GetPrices обрабатывает GET /items/{market_hash_name}/prices?from=&to=&interval=
+func (h *PriceHistoryHandler) GetPrices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	name := r.PathValue("market_hash_name")
+	if name == "" {
+		respondWithError(w, r, problem.BadRequest("market hash name is required"), h.logger)
+		return
+	}
+
+	query := r.URL.Query()
+
+	to := time.Now().UTC()
+	if v := query.Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondWithError(w, r, problem.BadRequest("invalid to: expected RFC 3339 timestamp"), h.logger)
+			return
+		}
+		to = t
+	}
+
+	from := to.Add(-defaultPriceHistoryRange)
+	if v := query.Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondWithError(w, r, problem.BadRequest("invalid from: expected RFC 3339 timestamp"), h.logger)
+			return
+		}
+		from = t
+	}
+
+	interval := defaultPriceHistoryInterval
+	if v := query.Get("interval"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			respondWithError(w, r, problem.BadRequest("invalid interval: expected duration like 15m or 1h"), h.logger)
+			return
+		}
+		interval = d
+	}
+
+	candles, err := h.service.GetPriceCandles(ctx, name, from, to, interval)
+	if err != nil {
+		respondWithError(w, r, err, h.logger)
+		return
+	}
+
+	if candles == nil {
+		candles = []*item.PriceCandle{}
+	}
+
+	respondWithJSON(w, r, http.StatusOK, PriceHistoryResponse{
+		MarketHashName: name,
+		From:           from,
+		To:             to,
+		Interval:       interval.String(),
+		Candles:        candles,
+	}, h.logger)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+const (
+	// sseWriteTimeout ограничивает запись одного SSE сообщения: зависший клиент не держит горутину вечно
+	sseWriteTimeout = 10 * time.Second
+	// defaultSSEHeartbeat интервал комментариев-пингов, не дающих прокси закрыть простаивающее соединение
+	defaultSSEHeartbeat = 15 * time.Second
+)
+
+// SaleStreamHandler раздает ленту продаж клиентам через Server-Sent Events
+type SaleStreamHandler struct {
+	service   input.SaleFeedService
+	heartbeat time.Duration
+	logger    *slog.Logger
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSaleStreamHandler создает новый SaleStreamHandler
+func NewSaleStreamHandler(service input.SaleFeedService, heartbeat time.Duration, logger *slog.Logger) *SaleStreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = defaultSSEHeartbeat
+	}
+	return &SaleStreamHandler{
+		service:   service,
+		heartbeat: heartbeat,
+		logger:    logger,
+		done:      make(chan struct{}),
+	}
+}
+
+// Close завершает открытые потоки. Вызывается перед остановкой сервера:
+// Shutdown не прерывает активные запросы и иначе ждал бы SSE клиентов до таймаута.
+func (h *SaleStreamHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+// Stream обрабатывает GET /items/stream.
+// Каждое событие отправляется как `event: sale`; если клиент не успевает читать,
+// сервер присылает `event: lagged` и закрывает поток — клиент должен переподключиться.
+func (h *SaleStreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rc := http.NewResponseController(w)
+
+	// Поток живет дольше WriteTimeout сервера, дедлайн выставляется на каждую запись отдельно
+	if err := rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout)); err != nil {
+		respondWithError(w, r, fmt.Errorf("streaming is not supported: %w", err), h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	events := h.service.Subscribe(ctx)
+
+	write := func(format string, args ...interface{}) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if !write(": connected\n\n") {
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.done:
+			return
+		case <-ticker.C:
+			if !write(": ping\n\n") {
+				return
+			}
+		case ev, ok := <-events:
+			if !ok {
+				if ctx.Err() == nil {
+					h.logger.WarnContext(r.Context(), "sale stream client lagged behind, closing stream")
+					write("event: lagged\ndata: {}\n\n")
+				}
+				return
+			}
+
+			data, err := json.Marshal(ev)
+			if err != nil {
+				h.logger.WarnContext(r.Context(), "failed to encode sale event", slog.Any("error", err))
+				continue
+			}
+			if !write("event: sale\ndata: %s\n\n", data) {
+				return
+			}
+		}
+	}
+}
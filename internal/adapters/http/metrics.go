@@ -0,0 +1,63 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute метка запросов, не попавших ни в один маршрут (404, 405)
+const unmatchedRoute = "unmatched"
+
+// httpMetrics счетчики и гистограмма длительности HTTP запросов
+type httpMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newHTTPMetrics(registry *prometheus.Registry) *httpMetrics {
+	m := &httpMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests by route pattern and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route pattern and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+	}
+	registry.MustRegister(m.requests, m.duration)
+	return m
+}
+
+// metricsMiddleware оборачивает mux напрямую: ServeMux записывает найденный шаблон
+// в r.Pattern, и метка route не растет от значений {id} в пути
+func (s *Server) metricsMiddleware(next http.Handler) http.Handler {
+	if s.metrics == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(wrapped, r)
+
+		route := unmatchedRoute
+		if r.Pattern != "" {
+			_, path, found := strings.Cut(r.Pattern, " ")
+			if !found {
+				path = r.Pattern
+			}
+			route = path
+		}
+		status := strconv.Itoa(wrapped.statusCode)
+
+		s.metrics.requests.WithLabelValues(r.Method, route, status).Inc()
+		s.metrics.duration.WithLabelValues(r.Method, route, status).Observe(time.Since(start).Seconds())
+	})
+}
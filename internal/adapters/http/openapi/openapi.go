@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"embed"
+	"net/http"
+	"strconv"
+)
+
+// Спецификация, страница и сам Swagger UI встроены в бинарник: документация всегда
+// соответствует развернутой версии сервера и не зависит от доступности CDN
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed swagger.html
+var swaggerUI []byte
+
+// assets файлы swagger-ui-dist, версия и лицензия — в swagger-ui/NOTICE
+//
+//go:embed swagger-ui/swagger-ui-bundle.js swagger-ui/swagger-ui.css
+var assets embed.FS
+
+// assetTypes типы файлов, которые отдает ServeAsset
+var assetTypes = map[string]string{
+	"swagger-ui-bundle.js": "text/javascript; charset=utf-8",
+	"swagger-ui.css":       "text/css; charset=utf-8",
+}
+
+// Spec возвращает документ OpenAPI 3.1 в JSON. Срез общий, изменять его нельзя.
+func Spec() []byte {
+	return spec
+}
+
+// ServeSpec обрабатывает GET /openapi.json
+func ServeSpec(w http.ResponseWriter, _ *http.Request) {
+	serve(w, "application/json", spec)
+}
+
+// ServeUI обрабатывает GET /docs: страница Swagger UI, открывающая /openapi.json
+func ServeUI(w http.ResponseWriter, _ *http.Request) {
+	serve(w, "text/html; charset=utf-8", swaggerUI)
+}
+
+// ServeAsset обрабатывает GET /docs/{file}: скрипт и стили Swagger UI
+func ServeAsset(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("file")
+	contentType, ok := assetTypes[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := assets.ReadFile("swagger-ui/" + name)
+	if err != nil {
+		// Файл из assetTypes не встроен — ошибка сборки, а не запроса
+		panic(err)
+	}
+	serve(w, contentType, body)
+}
+
+func serve(w http.ResponseWriter, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body) //nolint:errcheck // the client is gone, nothing to report
+}
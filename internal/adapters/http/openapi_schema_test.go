@@ -0,0 +1,347 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/openapi"
+)
+
+// openAPIDoc разобранная спецификация и проверка значений по подмножеству JSON Schema, которое
+// она использует: $ref, type, properties, required, additionalProperties, items, allOf, enum,
+// pattern, format (date-time, uuid, uri), minimum и maximum.
+//
+// Проверка строже JSON Schema: свойство, не описанное в схеме, считается ошибкой, если
+// additionalProperties не задан явно. Так новое поле ответа не появится без правки спецификации.
+type openAPIDoc struct {
+	root  map[string]any
+	paths map[string]map[string]any
+}
+
+func loadOpenAPI(t *testing.T) *openAPIDoc {
+	t.Helper()
+
+	var root map[string]any
+	if err := json.Unmarshal(openapi.Spec(), &root); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	if root["openapi"] != "3.1.0" {
+		t.Fatalf("expected OpenAPI 3.1.0, got %v", root["openapi"])
+	}
+
+	doc := &openAPIDoc{root: root, paths: make(map[string]map[string]any)}
+	for path, item := range root["paths"].(map[string]any) {
+		doc.paths[path] = item.(map[string]any)
+	}
+	return doc
+}
+
+// operation находит операцию по методу и фактическому пути запроса
+func (d *openAPIDoc) operation(method, path string) (template string, op map[string]any, ok bool) {
+	for tmpl, item := range d.paths {
+		if !matchTemplate(tmpl, path) {
+			continue
+		}
+		op, ok := item[strings.ToLower(method)].(map[string]any)
+		return tmpl, op, ok
+	}
+	return "", nil, false
+}
+
+// matchTemplate сопоставляет путь с шаблоном OpenAPI; параметр занимает один сегмент
+func matchTemplate(template, path string) bool {
+	want := strings.Split(template, "/")
+	got := strings.Split(path, "/")
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if strings.HasPrefix(want[i], "{") {
+			if got[i] == "" {
+				return false
+			}
+			continue
+		}
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkResponse проверяет, что статус и тип ответа описаны у операции, а тело соответствует схеме
+func (d *openAPIDoc) checkResponse(op map[string]any, status int, header string, body []byte) error {
+	responses := op["responses"].(map[string]any)
+	raw, ok := responses[strconv.Itoa(status)]
+	if !ok {
+		return fmt.Errorf("status %d is not documented", status)
+	}
+	resp := d.resolve(raw.(map[string]any))
+
+	content, _ := resp["content"].(map[string]any)
+	if len(content) == 0 {
+		if len(body) != 0 {
+			return fmt.Errorf("status %d is documented without a body, got %q", status, body)
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return fmt.Errorf("invalid Content-Type %q: %w", header, err)
+	}
+	media, ok := content[mediaType].(map[string]any)
+	if !ok {
+		return fmt.Errorf("content type %q is not documented for status %d", mediaType, status)
+	}
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("body is not valid JSON: %w", err)
+	}
+	return errorList(d.validate(media["schema"].(map[string]any), value, "$"))
+}
+
+// validateRef проверяет значение по именованной схеме из components
+func (d *openAPIDoc) validateRef(name string, value any) error {
+	return errorList(d.validate(map[string]any{"$ref": "#/components/schemas/" + name}, value, "$"))
+}
+
+func (d *openAPIDoc) resolve(node map[string]any) map[string]any {
+	for {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node
+		}
+		var cur any = d.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+			cur = cur.(map[string]any)[part]
+		}
+		if cur == nil {
+			panic("unresolved $ref " + ref)
+		}
+		node = cur.(map[string]any)
+	}
+}
+
+// flatten объединяет ветви allOf в одну схему: свойства, обязательные поля и тип
+func (d *openAPIDoc) flatten(schema map[string]any) map[string]any {
+	schema = d.resolve(schema)
+	branches, ok := schema["allOf"].([]any)
+	if !ok {
+		return schema
+	}
+
+	out := make(map[string]any, len(schema))
+	props := make(map[string]any)
+	var required []any
+	merge := func(s map[string]any) {
+		for k, v := range s {
+			switch k {
+			case "allOf":
+			case "properties":
+				for name, p := range v.(map[string]any) {
+					props[name] = p
+				}
+			case "required":
+				required = append(required, v.([]any)...)
+			default:
+				out[k] = v
+			}
+		}
+	}
+	for _, b := range branches {
+		merge(d.flatten(b.(map[string]any)))
+	}
+	merge(schema)
+
+	out["properties"] = props
+	out["required"] = required
+	return out
+}
+
+func (d *openAPIDoc) validate(schema map[string]any, value any, at string) []string {
+	schema = d.flatten(schema)
+	var errs []string
+	fail := func(format string, args ...any) {
+		errs = append(errs, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, value) {
+		fail("expected type %v, got %s", t, jsonType(value))
+		return errs
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return reflect.DeepEqual(e, value) }) {
+		fail("%v is not one of %v", value, enum)
+	}
+
+	switch v := value.(type) {
+	case string:
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			fail("%q does not match %s", v, pattern)
+		}
+		if format, ok := schema["format"].(string); ok {
+			if err := checkFormat(format, v); err != nil {
+				fail("%q is not a valid %s: %v", v, format, err)
+			}
+		}
+
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			fail("%v is less than minimum %v", v, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			fail("%v is greater than maximum %v", v, max)
+		}
+
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, el := range v {
+				errs = append(errs, d.validate(items, el, fmt.Sprintf("%s[%d]", at, i))...)
+			}
+		}
+
+	case map[string]any:
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := v[name.(string)]; !ok {
+				fail("required property %q is missing", name)
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for name, el := range v {
+			if p, ok := props[name]; ok {
+				errs = append(errs, d.validate(p.(map[string]any), el, at+"."+name)...)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case map[string]any:
+				errs = append(errs, d.validate(extra, el, at+"."+name)...)
+			case bool:
+				if !extra {
+					fail("property %q is not allowed", name)
+				}
+			default:
+				fail("property %q is not documented", name)
+			}
+		}
+	}
+
+	return errs
+}
+
+func checkFormat(format, v string) error {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, v)
+		return err
+	case "uuid":
+		_, err := uuid.Parse(v)
+		return err
+	case "uri":
+		u, err := url.Parse(v)
+		if err == nil && !u.IsAbs() {
+			err = errors.New("not absolute")
+		}
+		return err
+	default:
+		return nil
+	}
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func matchesType(want, value any) bool {
+	types, ok := want.([]any)
+	if !ok {
+		types = []any{want}
+	}
+	got := jsonType(value)
+	for _, t := range types {
+		if t == got || (t == "number" && got == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func errorList(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(errs, "\n"))
+}
+
+func TestOpenAPIDoc_Validate(t *testing.T) {
+	doc := loadOpenAPI(t)
+
+	tests := []struct {
+		name    string
+		schema  string
+		value   string
+		wantErr string
+	}{
+		{"valid", "BalanceOperation", `{"success":true,"transaction_id":"550e8400-e29b-41d4-a716-446655440000","balance_before":"1000","balance_after":"900.50"}`, ""},
+		{"decimal as number", "Balance", `{"user_id":1,"balance":900.5}`, "expected type string"},
+		{"missing required", "Balance", `{"user_id":1}`, `required property "balance" is missing`},
+		{"undocumented property", "Balance", `{"user_id":1,"balance":"1","currency":"EUR"}`, `property "currency" is not documented`},
+		{"enum", "RefreshResult", `{"status":"done"}`, "is not one of"},
+		{"allOf", "ItemWithHistory", `{"market_hash_name":"AK-47","currency":"EUR","item_page":"https://skinport.com/item/ak-47","market_page":"https://skinport.com/market","quantity":1,"created_at":1,"updated_at":1,"sales_history":{"last_24_hours":{"volume":1}}}`, `required property "last_7_days" is missing`},
+		{"additionalProperties schema", "Readiness", `{"status":"ok","checks":{"database":{"status":"maybe","critical":true,"duration_ms":1}}}`, "$.checks.database.status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value any
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatal(err)
+			}
+			err := doc.validateRef(tt.schema, value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
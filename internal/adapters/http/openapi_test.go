@@ -0,0 +1,567 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/handlers"
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/domain/transaction"
+	"github.com/akonovalovdev/DDD_example/internal/domain/user"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/ratelimit"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// openAPIPath переводит шаблон маршрута ServeMux в путь спецификации: {key...} → {key}
+func openAPIPath(pattern string) (method, path string) {
+	method, path, _ = strings.Cut(pattern, " ")
+	return strings.ToLower(method), strings.ReplaceAll(path, "...}", "}")
+}
+
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	doc := loadOpenAPI(t)
+	_, routes := newStubMux(t)
+
+	documented := make(map[string]bool)
+	for path, item := range doc.paths {
+		for method := range item {
+			if method != "parameters" {
+				documented[method+" "+path] = true
+			}
+		}
+	}
+
+	for _, rt := range routes {
+		method, path := openAPIPath(rt.pattern)
+		op, ok := doc.paths[path][method].(map[string]any)
+		if !ok {
+			t.Errorf("route %q is not documented", rt.pattern)
+			continue
+		}
+		delete(documented, method+" "+path)
+
+		// security описывает то же право, что таблица маршрутов
+		security, _ := op["security"].([]any)
+		if rt.public {
+			if security == nil || len(security) != 0 {
+				t.Errorf("%s: public route must declare empty security, got %v", rt.pattern, op["security"])
+			}
+		} else {
+			if len(security) == 0 {
+				t.Errorf("%s: security is not documented", rt.pattern)
+			}
+			for _, req := range security {
+				for scheme, scopes := range req.(map[string]any) {
+					if !slices.Equal(scopes.([]any), []any{rt.scope}) {
+						t.Errorf("%s: %s requires %v, route requires %q", rt.pattern, scheme, scopes, rt.scope)
+					}
+				}
+			}
+		}
+
+		responses := op["responses"].(map[string]any)
+		var want []string
+		if !rt.public {
+			want = append(want, "401", "403", "500")
+		}
+		if rt.limit != "" {
+			want = append(want, "429")
+		}
+		for _, status := range want {
+			if _, ok := responses[status]; !ok {
+				t.Errorf("%s: response %s is not documented", rt.pattern, status)
+			}
+		}
+	}
+
+	for op := range documented {
+		t.Errorf("documented operation %q has no route", op)
+	}
+}
+
+// contractCase запрос к серверу, ответ на который проверяется по спецификации
+type contractCase struct {
+	name   string
+	method string
+	path   string
+	body   string
+	header map[string]string
+	// scopes права вызывающего; nil — запрос без учетных данных
+	scopes []string
+	want   int
+}
+
+func TestOpenAPI_ResponsesMatchSpec(t *testing.T) {
+	doc := loadOpenAPI(t)
+	srv := newContractServer(t, map[string]output.RateLimit{
+		RateLimitRead:    {Rate: 1000, Burst: 1000},
+		RateLimitMoney:   {Rate: 1000, Burst: 1000},
+		RateLimitDefault: {Rate: 1000, Burst: 1000},
+		RateLimitIP:      {Rate: 1000, Burst: 1000},
+	})
+
+	admin := []string{auth.RoleAdmin}
+	tests := []contractCase{
+		{name: "health", method: http.MethodGet, path: "/health", want: http.StatusOK},
+		{name: "liveness", method: http.MethodGet, path: "/livez", want: http.StatusOK},
+		{name: "readiness", method: http.MethodGet, path: "/readyz", want: http.StatusOK},
+		{name: "metrics", method: http.MethodGet, path: "/metrics", want: http.StatusOK},
+		{name: "spec", method: http.MethodGet, path: "/openapi.json", want: http.StatusOK},
+		{name: "swagger ui", method: http.MethodGet, path: "/docs", want: http.StatusOK},
+		{name: "swagger ui script", method: http.MethodGet, path: "/docs/swagger-ui-bundle.js", want: http.StatusOK},
+		{name: "swagger ui styles", method: http.MethodGet, path: "/docs/swagger-ui.css", want: http.StatusOK},
+		{name: "unknown docs asset", method: http.MethodGet, path: "/docs/NOTICE", want: http.StatusNotFound},
+
+		{name: "catalogue", method: http.MethodGet, path: "/items", scopes: admin, want: http.StatusOK},
+		{name: "catalogue not modified", method: http.MethodGet, path: "/items", header: map[string]string{"If-None-Match": "*"}, scopes: admin, want: http.StatusNotModified},
+		{name: "catalogue with history", method: http.MethodGet, path: "/items?include=sales_history", scopes: admin, want: http.StatusOK},
+		{name: "catalogue without credentials", method: http.MethodGet, path: "/items", want: http.StatusUnauthorized},
+		{name: "catalogue without scope", method: http.MethodGet, path: "/items", scopes: []string{auth.ScopeBalanceRead}, want: http.StatusForbidden},
+		{name: "insights", method: http.MethodGet, path: "/items/insights?sort=discount&limit=2", scopes: admin, want: http.StatusOK},
+		{name: "insights bad limit", method: http.MethodGet, path: "/items/insights?limit=0", scopes: admin, want: http.StatusBadRequest},
+		{name: "insights bad sort", method: http.MethodGet, path: "/items/insights?sort=price", scopes: admin, want: http.StatusUnprocessableEntity},
+		{name: "sales history", method: http.MethodGet, path: "/items/AK-47/history", scopes: admin, want: http.StatusOK},
+		{name: "sales history unknown item", method: http.MethodGet, path: "/items/missing/history", scopes: admin, want: http.StatusNotFound},
+		{name: "prices", method: http.MethodGet, path: "/items/AK-47/prices?interval=1h", scopes: admin, want: http.StatusOK},
+		{name: "prices bad from", method: http.MethodGet, path: "/items/AK-47/prices?from=yesterday", scopes: admin, want: http.StatusBadRequest},
+		{name: "prices short interval", method: http.MethodGet, path: "/items/AK-47/prices?interval=1s", scopes: admin, want: http.StatusUnprocessableEntity},
+
+		{name: "balance", method: http.MethodGet, path: "/users/1/balance", scopes: []string{auth.RoleUser}, want: http.StatusOK},
+		{name: "balance of another user", method: http.MethodGet, path: "/users/2/balance", scopes: []string{auth.RoleUser}, want: http.StatusForbidden},
+		{name: "balance unknown user", method: http.MethodGet, path: "/users/404/balance", scopes: admin, want: http.StatusNotFound},
+		{name: "balance bad id", method: http.MethodGet, path: "/users/abc/balance", scopes: admin, want: http.StatusBadRequest},
+		{name: "withdraw", method: http.MethodPost, path: "/users/1/withdraw", body: `{"amount": "100.00"}`, scopes: []string{auth.RoleUser}, want: http.StatusOK},
+		{name: "withdraw insufficient balance", method: http.MethodPost, path: "/users/1/withdraw", body: `{"amount": "5000"}`, scopes: []string{auth.RoleUser}, want: http.StatusUnprocessableEntity},
+		{name: "withdraw negative amount", method: http.MethodPost, path: "/users/1/withdraw", body: `{"amount": "-1"}`, scopes: []string{auth.RoleUser}, want: http.StatusUnprocessableEntity},
+		{name: "withdraw bad body", method: http.MethodPost, path: "/users/1/withdraw", body: `{"amount":`, scopes: []string{auth.RoleUser}, want: http.StatusBadRequest},
+		{name: "withdraw frozen", method: http.MethodPost, path: "/users/409/withdraw", body: `{"amount": "1"}`, scopes: admin, want: http.StatusConflict},
+		{name: "deposit", method: http.MethodPost, path: "/users/1/deposit", body: `{"amount": "25.5"}`, scopes: []string{auth.RoleFinance}, want: http.StatusOK},
+		{name: "freeze", method: http.MethodPost, path: "/users/1/freeze", scopes: admin, want: http.StatusOK},
+		{name: "unfreeze", method: http.MethodDelete, path: "/users/1/freeze", scopes: admin, want: http.StatusOK},
+
+		{name: "create alert", method: http.MethodPost, path: "/users/1/alerts", body: `{"market_hash_name": "AK-47", "direction": "below", "price_type": "tradable", "threshold": "10.50", "webhook_url": "https://example.com/hook"}`, scopes: []string{auth.RoleUser}, want: http.StatusCreated},
+		{name: "create invalid alert", method: http.MethodPost, path: "/users/1/alerts", body: `{"market_hash_name": "AK-47", "direction": "sideways", "price_type": "tradable", "threshold": "10.50", "webhook_url": "https://example.com/hook"}`, scopes: []string{auth.RoleUser}, want: http.StatusUnprocessableEntity},
+		{name: "list alerts", method: http.MethodGet, path: "/users/1/alerts", scopes: []string{auth.RoleUser}, want: http.StatusOK},
+
+		{name: "cache keys", method: http.MethodGet, path: "/admin/cache/keys", scopes: admin, want: http.StatusOK},
+		{name: "delete cache key", method: http.MethodDelete, path: "/admin/cache/keys/skinport:items", scopes: admin, want: http.StatusNoContent},
+		{name: "clear cache", method: http.MethodDelete, path: "/admin/cache", scopes: admin, want: http.StatusNoContent},
+		{name: "refresh catalogue", method: http.MethodPost, path: "/admin/cache/refresh", scopes: admin, want: http.StatusOK},
+	}
+
+	// Каждая операция спецификации должна получить хотя бы один успешный ответ
+	unexercised := make(map[string]bool)
+	for path, item := range doc.paths {
+		for method := range item {
+			if method != "parameters" {
+				unexercised[method+" "+path] = true
+			}
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := srv.do(t, tt)
+			if resp.StatusCode != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, resp.StatusCode, body)
+			}
+
+			template, op := requireOperation(t, doc, tt.method, tt.path)
+			if err := doc.checkResponse(op, resp.StatusCode, resp.Header.Get("Content-Type"), body); err != nil {
+				t.Errorf("%s %s: response does not match spec:\n%v", tt.method, template, err)
+			}
+			if resp.StatusCode < http.StatusBadRequest {
+				delete(unexercised, strings.ToLower(tt.method)+" "+template)
+			}
+		})
+	}
+
+	t.Run("sale stream", func(t *testing.T) {
+		template, op := requireOperation(t, doc, http.MethodGet, "/items/stream")
+		delete(unexercised, "get "+template)
+
+		req := srv.request(t, contractCase{method: http.MethodGet, path: "/items/stream", scopes: []string{auth.RoleReader}})
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if err := doc.checkResponse(op, resp.StatusCode, resp.Header.Get("Content-Type"), nil); err != nil {
+			t.Fatalf("stream response does not match spec: %v", err)
+		}
+
+		// Данные события sale описаны схемой SaleEvent
+		scanner := bufio.NewScanner(resp.Body)
+		event := ""
+		for scanner.Scan() {
+			line := scanner.Text()
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				event = name
+			}
+			data, ok := strings.CutPrefix(line, "data: ")
+			if !ok || event != "sale" {
+				continue
+			}
+			var value any
+			if err := json.Unmarshal([]byte(data), &value); err != nil {
+				t.Fatalf("invalid event data %q: %v", data, err)
+			}
+			if err := doc.validateRef("SaleEvent", value); err != nil {
+				t.Errorf("sale event does not match spec:\n%v", err)
+			}
+			return
+		}
+		t.Fatalf("stream ended without a sale event: %v", scanner.Err())
+	})
+
+	for op := range unexercised {
+		t.Errorf("operation %q has no successful response in the contract test", op)
+	}
+}
+
+func TestOpenAPI_RateLimitedResponseMatchesSpec(t *testing.T) {
+	doc := loadOpenAPI(t)
+	srv := newContractServer(t, map[string]output.RateLimit{
+		RateLimitRead:    {Rate: 1000, Burst: 1000},
+		RateLimitMoney:   {Rate: 0.001, Burst: 1},
+		RateLimitDefault: {Rate: 1000, Burst: 1000},
+		RateLimitIP:      {Rate: 1000, Burst: 1000},
+	})
+
+	deposit := contractCase{method: http.MethodPost, path: "/users/1/deposit", body: `{"amount": "1"}`, scopes: []string{auth.RoleAdmin}}
+	if resp, body := srv.do(t, deposit); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected first deposit to pass, got %d: %s", resp.StatusCode, body)
+	}
+
+	resp, body := srv.do(t, deposit)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d: %s", resp.StatusCode, body)
+	}
+	_, op := requireOperation(t, doc, deposit.method, deposit.path)
+	if err := doc.checkResponse(op, resp.StatusCode, resp.Header.Get("Content-Type"), body); err != nil {
+		t.Errorf("429 does not match spec:\n%v", err)
+	}
+}
+
+// TestOpenAPI_SwaggerUIIsSelfContained проверяет, что страница /docs ссылается только на
+// встроенные файлы и каждый из них отдается сервером
+func TestOpenAPI_SwaggerUIIsSelfContained(t *testing.T) {
+	srv := newContractServer(t, map[string]output.RateLimit{
+		RateLimitRead:    {Rate: 1000, Burst: 1000},
+		RateLimitMoney:   {Rate: 1000, Burst: 1000},
+		RateLimitDefault: {Rate: 1000, Burst: 1000},
+		RateLimitIP:      {Rate: 1000, Burst: 1000},
+	})
+
+	_, page := srv.do(t, contractCase{method: http.MethodGet, path: "/docs"})
+	if bytes.Contains(page, []byte("://")) {
+		t.Fatalf("swagger ui page references an external resource:\n%s", page)
+	}
+
+	refs := regexp.MustCompile(`(?:src|href)="([^"]+)"`).FindAllSubmatch(page, -1)
+	if len(refs) == 0 {
+		t.Fatal("expected swagger ui page to reference its script and styles")
+	}
+	for _, ref := range refs {
+		path := "/" + string(ref[1])
+		resp, body := srv.do(t, contractCase{method: http.MethodGet, path: path})
+		if resp.StatusCode != http.StatusOK || len(body) == 0 {
+			t.Errorf("%s: expected 200 with a body, got %d (%d bytes)", path, resp.StatusCode, len(body))
+		}
+	}
+}
+
+func requireOperation(t *testing.T, doc *openAPIDoc, method, target string) (string, map[string]any) {
+	t.Helper()
+
+	path, _, _ := strings.Cut(target, "?")
+	template, op, ok := doc.operation(method, path)
+	if !ok {
+		t.Fatalf("%s %s is not documented", method, path)
+	}
+	return template, op
+}
+
+// contractServer настоящий сервер с настоящими обработчиками поверх сервисов-заглушек
+type contractServer struct {
+	*httptest.Server
+}
+
+func newContractServer(t *testing.T, limits map[string]output.RateLimit) *contractServer {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	items := contractItems{}
+	stream := handlers.NewSaleStreamHandler(contractFeed{}, time.Hour, logger)
+
+	s := NewServer(0, time.Second, time.Second,
+		Handlers{
+			Item:         handlers.NewItemHandler(items, items, logger),
+			Balance:      handlers.NewBalanceHandler(contractBalance{}, logger),
+			PriceHistory: handlers.NewPriceHistoryHandler(contractPrices{}, logger),
+			Alert:        handlers.NewAlertHandler(contractAlerts{}, logger),
+			Insight:      handlers.NewInsightHandler(contractInsights{}, logger),
+			SaleStream:   stream,
+			Admin:        handlers.NewAdminHandler(contractCacheAdmin{}, logger),
+			Health: handlers.NewHealthHandler([]handlers.HealthCheck{
+				{Name: "database", Critical: true, Check: func(context.Context) error { return nil }},
+			}, time.Second, logger),
+		},
+		auth.NewMiddleware(testAuthenticator, logger),
+		NewRateLimiter(ratelimit.NewMemoryStore(), limits, nil, logger),
+		prometheus.NewRegistry(),
+		logger,
+	)
+
+	srv := httptest.NewServer(s.server.Handler)
+	t.Cleanup(func() {
+		stream.Close()
+		srv.Close()
+	})
+	return &contractServer{Server: srv}
+}
+
+func (s *contractServer) request(t *testing.T, tc contractCase) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(tc.method, s.URL+tc.path, strings.NewReader(tc.body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tc.scopes != nil {
+		req.Header.Set("X-Test-Scopes", strings.Join(tc.scopes, " "))
+	}
+	for k, v := range tc.header {
+		req.Header.Set(k, v)
+	}
+	return req
+}
+
+func (s *contractServer) do(t *testing.T, tc contractCase) (*http.Response, []byte) {
+	t.Helper()
+
+	resp, err := s.Client().Do(s.request(t, tc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, body
+}
+
+func price(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
+var contractCatalogue = []*item.Item{
+	{
+		MarketHashName:   "AK-47",
+		Currency:         "EUR",
+		SuggestedPrice:   price("12.40"),
+		ItemPage:         "https://skinport.com/item/ak-47",
+		MarketPage:       "https://skinport.com/market?item=AK-47",
+		TradableMinPrice: price("10.05"),
+		MeanPrice:        price("11"),
+		Quantity:         25,
+		CreatedAt:        1700000000,
+		UpdatedAt:        1700003600,
+	},
+	{
+		MarketHashName: "Sticker | Crown (Foil)",
+		Currency:       "EUR",
+		ItemPage:       "https://skinport.com/item/sticker-crown-foil",
+		MarketPage:     "https://skinport.com/market?item=Sticker",
+		CreatedAt:      1700000000,
+		UpdatedAt:      1700003600,
+	},
+}
+
+type contractItems struct{}
+
+func (contractItems) GetItems(context.Context) ([]*item.Item, error) {
+	return contractCatalogue, nil
+}
+
+func (contractItems) GetCatalogue(context.Context) (*item.Catalogue, error) {
+	return &item.Catalogue{Items: contractCatalogue, Version: 1, UpdatedAt: time.Unix(1700003600, 0)}, nil
+}
+
+func (contractItems) GetSalesHistory(_ context.Context, name string) (*item.SalesHistory, error) {
+	if name != "AK-47" {
+		return nil, item.ErrItemNotFound
+	}
+	week := item.SalesStats{Min: price("9.90"), Max: price("13"), Avg: price("11.2"), Median: price("11.05"), Volume: 40}
+	return &item.SalesHistory{
+		MarketHashName: name,
+		Currency:       "EUR",
+		ItemPage:       "https://skinport.com/item/ak-47",
+		MarketPage:     "https://skinport.com/market?item=AK-47",
+		Last7Days:      week,
+		Last30Days:     week,
+		Last90Days:     week,
+	}, nil
+}
+
+func (c contractItems) GetSalesHistories(ctx context.Context) (map[string]*item.SalesHistory, error) {
+	h, err := c.GetSalesHistory(ctx, "AK-47")
+	return map[string]*item.SalesHistory{"AK-47": h}, err
+}
+
+type contractInsights struct{}
+
+func (contractInsights) GetInsights(_ context.Context, q input.InsightQuery) ([]*item.Insight, error) {
+	fee := decimal.NewFromInt(12)
+	if q.FeePercent != nil {
+		fee = *q.FeePercent
+	}
+	return []*item.Insight{item.NewInsight(contractCatalogue[0], fee)}, nil
+}
+
+type contractPrices struct{}
+
+func (contractPrices) GetPriceCandles(_ context.Context, name string, from, to time.Time, interval time.Duration) ([]*item.PriceCandle, error) {
+	if interval < time.Minute {
+		return nil, item.ErrInvalidInterval
+	}
+	return []*item.PriceCandle{{
+		Time:     from.Truncate(interval),
+		Open:     price("10.05"),
+		Close:    price("10.40"),
+		Low:      price("9.99"),
+		High:     price("10.60"),
+		Quantity: 25,
+		Samples:  4,
+	}}, nil
+}
+
+// contractBalance отвечает по номеру пользователя: 404 — не найден, 409 — счет заморожен
+type contractBalance struct{}
+
+func (contractBalance) check(userID int64) error {
+	switch userID {
+	case 404:
+		return user.ErrUserNotFound
+	case 409:
+		return user.ErrAccountFrozen
+	default:
+		return nil
+	}
+}
+
+func (b contractBalance) WithdrawBalance(_ context.Context, userID int64, amount decimal.Decimal) (*input.WithdrawResult, error) {
+	if err := b.check(userID); err != nil {
+		return nil, err
+	}
+	balance := decimal.NewFromInt(1000)
+	if amount.GreaterThan(balance) {
+		return nil, user.ErrInsufficientBalance
+	}
+	return &input.WithdrawResult{
+		Transaction:   &transaction.Transaction{ID: uuid.New()},
+		BalanceBefore: balance,
+		BalanceAfter:  balance.Sub(amount),
+	}, nil
+}
+
+func (b contractBalance) DepositBalance(_ context.Context, userID int64, amount decimal.Decimal) (*input.DepositResult, error) {
+	if err := b.check(userID); err != nil {
+		return nil, err
+	}
+	balance := decimal.NewFromInt(1000)
+	return &input.DepositResult{
+		Transaction:   &transaction.Transaction{ID: uuid.New()},
+		BalanceBefore: balance,
+		BalanceAfter:  balance.Add(amount),
+	}, nil
+}
+
+func (b contractBalance) GetBalance(_ context.Context, userID int64) (decimal.Decimal, error) {
+	return decimal.RequireFromString("1000.00"), b.check(userID)
+}
+
+func (b contractBalance) FreezeAccount(_ context.Context, userID int64) error {
+	return b.check(userID)
+}
+
+func (b contractBalance) UnfreezeAccount(_ context.Context, userID int64) error {
+	return b.check(userID)
+}
+
+type contractAlerts struct{}
+
+func (contractAlerts) CreateAlert(_ context.Context, userID int64, req input.CreateAlertRequest) (*alert.Alert, error) {
+	return alert.NewAlert(userID, req.MarketHashName, req.Direction, req.PriceType, req.Threshold, req.WebhookURL)
+}
+
+func (a contractAlerts) ListAlerts(ctx context.Context, userID int64) ([]*alert.Alert, error) {
+	created, err := a.CreateAlert(ctx, userID, input.CreateAlertRequest{
+		MarketHashName: "AK-47",
+		Direction:      alert.DirectionAbove,
+		PriceType:      alert.PriceTypeNonTradable,
+		Threshold:      decimal.NewFromInt(15),
+		WebhookURL:     "https://example.com/hook",
+	})
+	if err != nil {
+		return nil, err
+	}
+	triggered := created.CreatedAt.Add(time.Minute)
+	created.Active = false
+	created.TriggeredAt = &triggered
+	return []*alert.Alert{created}, nil
+}
+
+type contractCacheAdmin struct{}
+
+func (contractCacheAdmin) ListEntries(context.Context, input.AdminActor) ([]input.CacheEntry, error) {
+	return []input.CacheEntry{
+		{Key: "skinport:items", ExpiresInSeconds: 240.5, SizeBytes: 1 << 20},
+		{Key: "skinport:sales_history", ExpiresInSeconds: -1, SizeBytes: 4096},
+	}, nil
+}
+
+func (contractCacheAdmin) RefreshCatalogue(context.Context, input.AdminActor) error { return nil }
+
+func (contractCacheAdmin) DeleteKey(context.Context, input.AdminActor, string) error { return nil }
+
+func (contractCacheAdmin) Clear(context.Context, input.AdminActor) error { return nil }
+
+type contractFeed struct{}
+
+func (contractFeed) Subscribe(context.Context) <-chan *item.SaleEvent {
+	ev, err := item.NewSaleEvent(item.SaleEventSold, 42, "AK-47", "EUR", decimal.RequireFromString("10.05"), true, time.Now())
+	if err != nil {
+		panic(err)
+	}
+	wear := 0.153
+	ev.Wear = &wear
+	ev.SuggestedPrice = price("12.40")
+
+	// Канал не закрывается: закрытый канал означает отставшего клиента
+	events := make(chan *item.SaleEvent, 1)
+	events <- ev
+	return events
+}
@@ -0,0 +1,204 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/domain/user"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/logging"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+// ContentType тип ответа об ошибке (RFC 7807)
+const ContentType = "application/problem+json"
+
+// Code машиночитаемый код ошибки. Коды стабильны: клиенты ветвятся по ним, а не по тексту.
+type Code string
+
+const (
+	// CodeInvalidRequest запрос синтаксически неверен: тело не разбирается, параметр не того формата
+	CodeInvalidRequest Code = "invalid_request"
+	// CodeValidationFailed запрос разобран, но значения недопустимы
+	CodeValidationFailed    Code = "validation_failed"
+	CodeInvalidAmount       Code = "invalid_amount"
+	CodeInsufficientBalance Code = "insufficient_balance"
+	CodeAccountFrozen       Code = "account_frozen"
+	CodeUserAlreadyExists   Code = "user_already_exists"
+
+	CodeUnauthenticated    Code = "unauthenticated"
+	CodeInvalidCredentials Code = "invalid_credentials"
+	CodeInsufficientScope  Code = "insufficient_scope"
+	CodeForbidden          Code = "forbidden"
+
+	CodeUserNotFound  Code = "user_not_found"
+	CodeItemNotFound  Code = "item_not_found"
+	CodeAlertNotFound Code = "alert_not_found"
+
+	CodeRateLimited         Code = "rate_limited"
+	CodeUpstreamUnavailable Code = "upstream_unavailable"
+	CodeNotImplemented      Code = "not_implemented"
+	CodeInternal            Code = "internal_error"
+)
+
+// Problem тело ответа об ошибке. Type всегда about:blank, поэтому Title — текст статуса;
+// различать ошибки следует по Code.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      Code   `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+
+	// extensions дополнительные члены ответа, например required_scope
+	extensions map[string]interface{}
+	// cause исходная ошибка для лога; клиенту не отдается
+	cause error
+}
+
+// New создает описание ошибки
+func New(status int, code Code, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// BadRequest описывает синтаксически неверный запрос
+func BadRequest(detail string) *Problem {
+	return New(http.StatusBadRequest, CodeInvalidRequest, detail)
+}
+
+// Error реализует error, чтобы описание можно было передавать наравне с доменными ошибками
+func (p *Problem) Error() string {
+	return string(p.Code) + ": " + p.Detail
+}
+
+// Unwrap возвращает исходную ошибку
+func (p *Problem) Unwrap() error {
+	return p.cause
+}
+
+// With добавляет член ответа
+func (p *Problem) With(key string, value interface{}) *Problem {
+	if p.extensions == nil {
+		p.extensions = make(map[string]interface{})
+	}
+	p.extensions[key] = value
+	return p
+}
+
+// MarshalJSON выводит дополнительные члены на одном уровне со стандартными
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type plain Problem
+	if len(p.extensions) == 0 {
+		return json.Marshal((*plain)(p))
+	}
+
+	base, err := json.Marshal((*plain)(p))
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{}, len(p.extensions)+8)
+	for k, v := range p.extensions {
+		out[k] = v
+	}
+	if err := json.Unmarshal(base, &out); err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// mapping сопоставление доменной ошибки коду и статусу. Пустой detail — текст ошибки,
+// он содержит подробности вроде "invalid interval: must be at least 1m0s".
+type mapping struct {
+	target error
+	status int
+	code   Code
+	detail string
+}
+
+// mappings единая таблица перевода доменных ошибок в ответы API
+var mappings = []mapping{
+	{user.ErrUserNotFound, http.StatusNotFound, CodeUserNotFound, ""},
+	{user.ErrInsufficientBalance, http.StatusUnprocessableEntity, CodeInsufficientBalance, ""},
+	{user.ErrInvalidAmount, http.StatusUnprocessableEntity, CodeInvalidAmount, ""},
+	{user.ErrAccountFrozen, http.StatusConflict, CodeAccountFrozen, ""},
+	{user.ErrUserAlreadyExists, http.StatusConflict, CodeUserAlreadyExists, ""},
+
+	{item.ErrItemNotFound, http.StatusNotFound, CodeItemNotFound, ""},
+	{item.ErrInvalidTimeRange, http.StatusUnprocessableEntity, CodeValidationFailed, ""},
+	{item.ErrInvalidInterval, http.StatusUnprocessableEntity, CodeValidationFailed, ""},
+	{item.ErrInvalidInsightSort, http.StatusUnprocessableEntity, CodeValidationFailed, ""},
+	{item.ErrInvalidFeePercent, http.StatusUnprocessableEntity, CodeValidationFailed, ""},
+	// Причина отказа Skinport (адреса, статусы) остается в логе
+	{item.ErrFetchFailed, http.StatusServiceUnavailable, CodeUpstreamUnavailable, "market data provider is unavailable"},
+	{item.ErrEmptyResponse, http.StatusServiceUnavailable, CodeUpstreamUnavailable, "market data provider is unavailable"},
+
+	{alert.ErrAlertNotFound, http.StatusNotFound, CodeAlertNotFound, ""},
+	{alert.ErrInvalidDirection, http.StatusUnprocessableEntity, CodeValidationFailed, ""},
+	{alert.ErrInvalidPriceType, http.StatusUnprocessableEntity, CodeValidationFailed, ""},
+	{alert.ErrInvalidThreshold, http.StatusUnprocessableEntity, CodeValidationFailed, ""},
+	{alert.ErrInvalidMarketHashName, http.StatusUnprocessableEntity, CodeValidationFailed, ""},
+	{alert.ErrInvalidWebhookURL, http.StatusUnprocessableEntity, CodeValidationFailed, ""},
+
+	{input.ErrCacheInspectionUnsupported, http.StatusNotImplemented, CodeNotImplemented, ""},
+}
+
+// FromError переводит ошибку в описание ответа. Неизвестные ошибки становятся
+// internal_error без подробностей: их текст может раскрыть внутреннее устройство.
+func FromError(err error) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		return p
+	}
+
+	for _, m := range mappings {
+		if !errors.Is(err, m.target) {
+			continue
+		}
+		detail := m.detail
+		if detail == "" {
+			detail = err.Error()
+		}
+		p = New(m.status, m.code, detail)
+		p.cause = err
+		return p
+	}
+
+	p = New(http.StatusInternalServerError, CodeInternal, "internal server error")
+	p.cause = err
+	return p
+}
+
+// Write отвечает ошибкой err. Ответы 5xx логируются вместе с исходной ошибкой.
+func Write(w http.ResponseWriter, r *http.Request, err error, logger *slog.Logger) {
+	p := FromError(err)
+
+	// Копия: описание может быть общим (например, переиспользуемая переменная)
+	resp := *p
+	resp.Instance = r.URL.Path
+	resp.RequestID = logging.RequestID(r.Context())
+
+	if resp.Status >= http.StatusInternalServerError {
+		logger.ErrorContext(r.Context(), "request failed",
+			slog.String("code", string(resp.Code)),
+			slog.String("path", r.URL.Path),
+			slog.Any("error", err),
+		)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(resp.Status)
+	if err := json.NewEncoder(w).Encode(&resp); err != nil {
+		logger.WarnContext(r.Context(), "Failed to encode response", "error", err)
+	}
+}
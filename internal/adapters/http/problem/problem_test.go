@@ -0,0 +1,129 @@
+package problem
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/domain/user"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/logging"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   Code
+		wantDetail string
+	}{
+		{
+			name:       "wrapped domain error",
+			err:        fmt.Errorf("withdraw: %w", user.ErrInsufficientBalance),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   CodeInsufficientBalance,
+			wantDetail: "withdraw: insufficient balance",
+		},
+		{
+			name:       "upstream details are hidden",
+			err:        fmt.Errorf("%w: unexpected status 502 from https://api.skinport.com", item.ErrFetchFailed),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   CodeUpstreamUnavailable,
+			wantDetail: "market data provider is unavailable",
+		},
+		{
+			name:       "unknown error is internal",
+			err:        errors.New("pq: connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternal,
+			wantDetail: "internal server error",
+		},
+		{
+			name:       "problem passes through wrapping",
+			err:        fmt.Errorf("parse: %w", BadRequest("invalid user ID")),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeInvalidRequest,
+			wantDetail: "invalid user ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := FromError(tt.err)
+			if p.Status != tt.wantStatus || p.Code != tt.wantCode || p.Detail != tt.wantDetail {
+				t.Errorf("got %d %s %q, want %d %s %q", p.Status, p.Code, p.Detail, tt.wantStatus, tt.wantCode, tt.wantDetail)
+			}
+			if p.Type != "about:blank" || p.Title != http.StatusText(tt.wantStatus) {
+				t.Errorf("unexpected type/title: %q %q", p.Type, p.Title)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/users/1/withdraw", nil)
+	req = req.WithContext(logging.WithRequestID(req.Context(), "req-42"))
+	rec := httptest.NewRecorder()
+
+	Write(rec, req, New(http.StatusForbidden, CodeInsufficientScope, "insufficient scope").With("required_scope", "admin"), logger)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("expected %s, got %q", ContentType, ct)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := map[string]interface{}{
+		"type":           "about:blank",
+		"title":          "Forbidden",
+		"status":         float64(http.StatusForbidden),
+		"detail":         "insufficient scope",
+		"instance":       "/users/1/withdraw",
+		"code":           "insufficient_scope",
+		"request_id":     "req-42",
+		"required_scope": "admin",
+	}
+	for k, v := range want {
+		if body[k] != v {
+			t.Errorf("%s: expected %v, got %v", k, v, body[k])
+		}
+	}
+	if logs.Len() != 0 {
+		t.Errorf("4xx must not be logged, got %s", logs.String())
+	}
+}
+
+func TestWrite_LogsServerErrors(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	rec := httptest.NewRecorder()
+	Write(rec, httptest.NewRequest(http.MethodGet, "/items", nil), errors.New("pq: connection refused"), logger)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	if strings.Contains(string(body), "pq:") {
+		t.Errorf("internal error leaked to client: %s", body)
+	}
+	if !strings.Contains(logs.String(), "pq: connection refused") {
+		t.Errorf("expected cause in log, got %s", logs.String())
+	}
+}
@@ -0,0 +1,203 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// Классы бюджетов запросов: дешевое чтение, движение денег и все остальное
+const (
+	RateLimitRead    = "read"
+	RateLimitMoney   = "money"
+	RateLimitDefault = "default"
+	// RateLimitIP грубый бюджет адреса клиента, который расходуется до аутентификации
+	RateLimitIP = "ip"
+)
+
+// RateLimiter ограничивает частоту запросов token bucket'ом. Бюджет считается
+// отдельно для каждого класса маршрутов и каждого клиента: API ключа,
+// аутентифицированного пользователя или IP адреса.
+type RateLimiter struct {
+	store          output.RateLimitStore
+	limits         map[string]output.RateLimit
+	trustedProxies []netip.Prefix
+	logger         *slog.Logger
+}
+
+// NewRateLimiter создает новый RateLimiter
+func NewRateLimiter(
+	store output.RateLimitStore,
+	limits map[string]output.RateLimit,
+	trustedProxies []netip.Prefix,
+	logger *slog.Logger,
+) *RateLimiter {
+	return &RateLimiter{
+		store:          store,
+		limits:         limits,
+		trustedProxies: trustedProxies,
+		logger:         logger,
+	}
+}
+
+// Wrap оборачивает обработчик лимитом класса class. Внутри аутентификации
+// ключом становится вызывающий, для публичных маршрутов — IP клиента.
+func (l *RateLimiter) Wrap(class string, next http.HandlerFunc) http.HandlerFunc {
+	return l.wrap(class, l.clientKey, next)
+}
+
+// WrapClientIP оборачивает обработчик лимитом класса class по IP клиента независимо от
+// аутентификации. Ставится перед проверкой учетных данных, чтобы ответы 401 тоже расходовали
+// бюджет и перебор ключей упирался в 429.
+func (l *RateLimiter) WrapClientIP(class string, next http.HandlerFunc) http.HandlerFunc {
+	return l.wrap(class, func(r *http.Request) string {
+		return "ip:" + ClientIP(r, l.trustedProxies)
+	}, next)
+}
+
+func (l *RateLimiter) wrap(class string, clientKey func(*http.Request) string, next http.HandlerFunc) http.HandlerFunc {
+	limit, ok := l.limits[class]
+	if !ok {
+		panic(fmt.Sprintf("unknown rate limit class %q", class))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := l.store.Take(r.Context(), class+":"+clientKey(r), limit)
+		if err != nil {
+			// Недоступное хранилище не должно останавливать сервис: пропускаем запрос
+			l.logger.WarnContext(r.Context(), "rate limit store failed, allowing request", slog.Any("error", err))
+			next(w, r)
+			return
+		}
+
+		setRateLimitHeaders(w.Header(), limit, result)
+
+		if !result.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			problem.Write(w, r, problem.New(http.StatusTooManyRequests, problem.CodeRateLimited, "rate limit exceeded"), l.logger)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// clientKey определяет, чей бюджет расходует запрос
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok && p.Method != "none" {
+		if p.Method == "api_key" {
+			return "key:" + p.Subject
+		}
+		return "user:" + p.Subject
+	}
+
+	return "ip:" + ClientIP(r, l.trustedProxies)
+}
+
+// setRateLimitHeaders выставляет заголовки RateLimit-* (draft-ietf-httpapi-ratelimit-headers).
+// Запрос проходит через бюджет IP и бюджет класса маршрута, поэтому заголовки уже могут быть
+// выставлены внешним слоем: в ответе остается самый строгий бюджет — с меньшим остатком,
+// а при равном остатке с более поздним восстановлением.
+func setRateLimitHeaders(h http.Header, limit output.RateLimit, result output.RateLimitResult) {
+	if remaining, err := strconv.Atoi(h.Get("RateLimit-Remaining")); err == nil {
+		reset, _ := strconv.Atoi(h.Get("RateLimit-Reset"))
+		if remaining < result.Remaining || (remaining == result.Remaining && reset >= ceilSeconds(result.ResetAfter)) {
+			return
+		}
+	}
+
+	h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+	h.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, ceilSeconds(limit.Window())))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// ClientIP возвращает адрес клиента. X-Forwarded-For учитывается, только если запрос
+// пришел от доверенного прокси: цепочка разбирается справа налево до первого
+// недоверенного адреса, поэтому подставленные клиентом значения игнорируются.
+func ClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	remote := remoteIP(r.RemoteAddr)
+	if !remote.IsValid() {
+		return r.RemoteAddr
+	}
+	if !isTrusted(remote, trustedProxies) {
+		return remote.String()
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		hop = hop.Unmap()
+		if !isTrusted(hop, trustedProxies) {
+			return hop.String()
+		}
+	}
+
+	return remote.String()
+}
+
+// ParseTrustedProxies разбирает список доверенных прокси: адреса или CIDR через запятую
+func ParseTrustedProxies(spec string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if strings.Contains(item, "/") {
+			prefix, err := netip.ParsePrefix(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", item, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", item, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+func remoteIP(remoteAddr string) netip.Addr {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+func isTrusted(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	for _, p := range trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/ratelimit"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.1")
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        []string
+		want       string
+	}{
+		{"direct client", "203.0.113.5:4000", nil, "203.0.113.5"},
+		{"untrusted peer cannot spoof", "203.0.113.5:4000", []string{"1.2.3.4"}, "203.0.113.5"},
+		{"trusted proxy", "10.0.0.2:4000", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"chain of trusted proxies", "10.0.0.2:4000", []string{"198.51.100.7, 192.168.1.1"}, "198.51.100.7"},
+		{"spoofed left entry ignored", "10.0.0.2:4000", []string{"1.2.3.4, 198.51.100.7"}, "198.51.100.7"},
+		{"multiple headers", "10.0.0.2:4000", []string{"1.2.3.4", "198.51.100.7"}, "198.51.100.7"},
+		{"garbage stops the walk", "10.0.0.2:4000", []string{"198.51.100.7, nonsense"}, "10.0.0.2"},
+		{"only proxies", "10.0.0.2:4000", []string{"10.1.1.1"}, "10.0.0.2"},
+		{"ipv6 client", "[2001:db8::1]:4000", nil, "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/items", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+
+			if got := ClientIP(r, trusted); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	if _, err := ParseTrustedProxies("10.0.0.0/33"); err == nil {
+		t.Error("expected error for invalid CIDR")
+	}
+	if _, err := ParseTrustedProxies("proxy.local"); err == nil {
+		t.Error("expected error for hostname")
+	}
+}
+
+func newTestLimiter(store output.RateLimitStore) *RateLimiter {
+	limits := map[string]output.RateLimit{
+		RateLimitRead:  {Rate: 1, Burst: 2},
+		RateLimitMoney: {Rate: 0.1, Burst: 1},
+		RateLimitIP:    {Rate: 1, Burst: 2},
+	}
+	return NewRateLimiter(store, limits, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func okHandler(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestRateLimiter_RejectsOverBudget(t *testing.T) {
+	h := newTestLimiter(ratelimit.NewMemoryStore()).Wrap(RateLimitRead, okHandler)
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i, rec.Code)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("request %d: RateLimit-Remaining = %q, want %q", i, got, wantRemaining)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: RateLimit-Limit = %q, want 2", i, got)
+		}
+		if got := rec.Header().Get("RateLimit-Policy"); got != "2;w=2" {
+			t.Errorf("request %d: RateLimit-Policy = %q, want 2;w=2", i, got)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1", got)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, want 0", got)
+	}
+}
+
+func TestRateLimiter_SeparateBudgets(t *testing.T) {
+	limiter := newTestLimiter(ratelimit.NewMemoryStore())
+	read := limiter.Wrap(RateLimitRead, okHandler)
+	money := limiter.Wrap(RateLimitMoney, okHandler)
+
+	withPrincipal := func(p *auth.Principal, remoteAddr string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/users/1/withdraw", nil)
+		r.RemoteAddr = remoteAddr
+		return r.WithContext(auth.WithPrincipal(r.Context(), p))
+	}
+	alice := &auth.Principal{Subject: "1", Method: "jwt"}
+	bob := &auth.Principal{Subject: "2", Method: "jwt"}
+
+	serve := func(h http.HandlerFunc, r *http.Request) int {
+		rec := httptest.NewRecorder()
+		h(rec, r)
+		return rec.Code
+	}
+
+	if code := serve(money, withPrincipal(alice, "203.0.113.5:1")); code != http.StatusOK {
+		t.Fatalf("first money request: expected 200, got %d", code)
+	}
+	// Тот же пользователь с другого адреса расходует тот же бюджет
+	if code := serve(money, withPrincipal(alice, "198.51.100.7:1")); code != http.StatusTooManyRequests {
+		t.Fatalf("second money request: expected 429, got %d", code)
+	}
+	// Исчерпанный денежный бюджет не трогает чтение
+	if code := serve(read, withPrincipal(alice, "203.0.113.5:1")); code != http.StatusOK {
+		t.Fatalf("read request: expected 200, got %d", code)
+	}
+	// Другой пользователь с того же адреса имеет свой бюджет
+	if code := serve(money, withPrincipal(bob, "203.0.113.5:1")); code != http.StatusOK {
+		t.Fatalf("other user money request: expected 200, got %d", code)
+	}
+}
+
+func TestRateLimiter_NestedReportsMostRestrictiveBudget(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), map[string]output.RateLimit{
+		RateLimitRead:  {Rate: 1, Burst: 5},
+		RateLimitMoney: {Rate: 0.1, Burst: 1},
+		RateLimitIP:    {Rate: 1, Burst: 3},
+	}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	serve := func(class string) http.Header {
+		rec := httptest.NewRecorder()
+		limiter.WrapClientIP(RateLimitIP, limiter.Wrap(class, okHandler))(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+		return rec.Header()
+	}
+
+	// Бюджет IP строже бюджета чтения: внутренний слой не перезаписывает его заголовки
+	h := serve(RateLimitRead)
+	if got := h.Get("RateLimit-Remaining"); got != "2" {
+		t.Errorf("RateLimit-Remaining = %q, want 2", got)
+	}
+	if got := h.Get("RateLimit-Policy"); got != "3;w=3" {
+		t.Errorf("RateLimit-Policy = %q, want 3;w=3", got)
+	}
+
+	// Денежный бюджет строже бюджета IP: в ответе заголовки внутреннего слоя
+	h = serve(RateLimitMoney)
+	if got := h.Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, want 0", got)
+	}
+	if got := h.Get("RateLimit-Policy"); got != "1;w=10" {
+		t.Errorf("RateLimit-Policy = %q, want 1;w=10", got)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, output.RateLimit) (output.RateLimitResult, error) {
+	return output.RateLimitResult{}, errors.New("connection refused")
+}
+
+func TestRateLimiter_FailsOpen(t *testing.T) {
+	h := newTestLimiter(failingStore{}).Wrap(RateLimitMoney, okHandler)
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/users/1/withdraw", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 when store fails, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("RateLimit-Limit"); got != "" {
+		t.Errorf("expected no rate limit headers, got RateLimit-Limit %q", got)
+	}
+}
+
+func TestRateLimiter_UnknownClassPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for unknown class")
+		}
+	}()
+	newTestLimiter(ratelimit.NewMemoryStore()).Wrap("bulk", okHandler)
+}
@@ -7,15 +7,43 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
 	"github.com/akonovalovdev/DDD_example/internal/adapters/http/handlers"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/openapi"
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/problem"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/logging"
 )
 
+// RequestIDHeader заголовок с идентификатором запроса
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength ограничивает принятый от клиента идентификатор, чтобы он не раздувал логи
+const maxRequestIDLength = 128
+
+// Handlers содержит HTTP обработчики, которые регистрирует сервер
+type Handlers struct {
+	Item         *handlers.ItemHandler
+	Balance      *handlers.BalanceHandler
+	PriceHistory *handlers.PriceHistoryHandler
+	Alert        *handlers.AlertHandler
+	Insight      *handlers.InsightHandler
+	SaleStream   *handlers.SaleStreamHandler
+	Admin        *handlers.AdminHandler
+	Health       *handlers.HealthHandler
+}
+
 // Server представляет HTTP сервер
 type Server struct {
-	server         *http.Server
-	itemHandler    *handlers.ItemHandler
-	balanceHandler *handlers.BalanceHandler
-	logger         *slog.Logger
+	server   *http.Server
+	handlers Handlers
+	authn    *auth.Middleware
+	limiter  *RateLimiter
+	registry *prometheus.Registry
+	metrics  *httpMetrics
+	logger   *slog.Logger
 }
 
 // NewServer создает новый HTTP сервер
@@ -23,21 +51,28 @@ func NewServer(
 	port int,
 	readTimeout time.Duration,
 	writeTimeout time.Duration,
-	itemHandler *handlers.ItemHandler,
-	balanceHandler *handlers.BalanceHandler,
+	h Handlers,
+	authn *auth.Middleware,
+	limiter *RateLimiter,
+	registry *prometheus.Registry,
 	logger *slog.Logger,
 ) *Server {
 	s := &Server{
-		itemHandler:    itemHandler,
-		balanceHandler: balanceHandler,
-		logger:         logger,
+		handlers: h,
+		authn:    authn,
+		limiter:  limiter,
+		registry: registry,
+		logger:   logger,
+	}
+	if registry != nil {
+		s.metrics = newHTTPMetrics(registry)
 	}
 
 	mux := s.setupRoutes()
 
 	s.server = &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
-		Handler:      s.withMiddleware(mux),
+		Handler:      s.withMiddleware(s.metricsMiddleware(routeSpanMiddleware(mux))),
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
 	}
@@ -45,24 +80,130 @@ func NewServer(
 	return s
 }
 
-func (s *Server) setupRoutes() *http.ServeMux {
-	mux := http.NewServeMux()
+// route связывает маршрут с правом, которое он требует
+type route struct {
+	pattern string
+	// scope право, без которого маршрут отвечает 403
+	scope string
+	// public маршрут доступен без аутентификации
+	public bool
+	// limit класс бюджета запросов; пустой — без ограничения частоты
+	limit   string
+	handler http.HandlerFunc
+}
 
-	mux.HandleFunc("GET /items", s.itemHandler.GetItems)
+// routes декларативная таблица маршрутов: каждый маршрут либо публичный, либо требует право,
+// и относится к классу бюджета запросов. Принадлежность {id} вызывающему дополнительно
+// проверяют обработчики.
+func (s *Server) routes() []route {
+	h := s.handlers
 
-	mux.HandleFunc("POST /users/{id}/withdraw", s.balanceHandler.Withdraw)
-	mux.HandleFunc("GET /users/{id}/balance", s.balanceHandler.GetBalance)
+	routes := []route{
+		{pattern: "GET /health", public: true, handler: s.health},
+		{pattern: "GET /livez", public: true, handler: h.Health.Live},
+		{pattern: "GET /readyz", public: true, handler: h.Health.Ready},
+		{pattern: "GET /openapi.json", public: true, handler: openapi.ServeSpec},
+		{pattern: "GET /docs", public: true, handler: openapi.ServeUI},
+		{pattern: "GET /docs/{file}", public: true, handler: openapi.ServeAsset},
 
-	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`)) //nolint:errcheck // it's ok
-	})
+		{pattern: "GET /items", scope: auth.ScopeItemsRead, limit: RateLimitRead, handler: h.Item.GetItems},
+		{pattern: "GET /items/insights", scope: auth.ScopeItemsRead, limit: RateLimitRead, handler: h.Insight.GetInsights},
+		{pattern: "GET /items/stream", scope: auth.ScopeItemsRead, limit: RateLimitRead, handler: h.SaleStream.Stream},
+		{pattern: "GET /items/{market_hash_name}/history", scope: auth.ScopeItemsRead, limit: RateLimitRead, handler: h.Item.GetSalesHistory},
+		{pattern: "GET /items/{market_hash_name}/prices", scope: auth.ScopeItemsRead, limit: RateLimitRead, handler: h.PriceHistory.GetPrices},
+
+		{pattern: "GET /users/{id}/balance", scope: auth.ScopeBalanceRead, limit: RateLimitRead, handler: h.Balance.GetBalance},
+		{pattern: "POST /users/{id}/withdraw", scope: auth.ScopeBalanceWrite, limit: RateLimitMoney, handler: h.Balance.Withdraw},
+		{pattern: "POST /users/{id}/deposit", scope: auth.ScopeBalanceDeposit, limit: RateLimitMoney, handler: h.Balance.Deposit},
+		{pattern: "POST /users/{id}/freeze", scope: auth.ScopeAccountsFreeze, limit: RateLimitDefault, handler: h.Balance.Freeze},
+		{pattern: "DELETE /users/{id}/freeze", scope: auth.ScopeAccountsFreeze, limit: RateLimitDefault, handler: h.Balance.Unfreeze},
 
+		{pattern: "POST /users/{id}/alerts", scope: auth.ScopeAlertsWrite, limit: RateLimitDefault, handler: h.Alert.CreateAlert},
+		{pattern: "GET /users/{id}/alerts", scope: auth.ScopeAlertsRead, limit: RateLimitRead, handler: h.Alert.ListAlerts},
+
+		{pattern: "GET /admin/cache/keys", scope: auth.ScopeCacheAdmin, limit: RateLimitRead, handler: h.Admin.ListCacheKeys},
+		{pattern: "DELETE /admin/cache/keys/{key...}", scope: auth.ScopeCacheAdmin, limit: RateLimitDefault, handler: h.Admin.DeleteCacheKey},
+		{pattern: "DELETE /admin/cache", scope: auth.ScopeCacheAdmin, limit: RateLimitDefault, handler: h.Admin.ClearCache},
+		{pattern: "POST /admin/cache/refresh", scope: auth.ScopeCacheAdmin, limit: RateLimitDefault, handler: h.Admin.RefreshCatalogue},
+	}
+
+	// Метрики открыты, как /health: их опрашивает Prometheus без учетных данных
+	if s.registry != nil {
+		routes = append(routes, route{pattern: "GET /metrics", public: true, handler: promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{}).ServeHTTP})
+	}
+
+	return routes
+}
+
+func (s *Server) setupRoutes() *http.ServeMux {
+	mux := http.NewServeMux()
+	s.register(mux, s.routes())
 	return mux
 }
 
+// register регистрирует маршруты, оборачивая их лимитом частоты и проверкой права.
+// Лимит класса маршрута применяется после аутентификации, чтобы бюджет считался по вызывающему,
+// а не по IP. Перед аутентификацией стоит общий бюджет IP: без него запросы с неверными
+// учетными данными не ограничены вовсе.
+func (s *Server) register(mux *http.ServeMux, routes []route) {
+	for _, rt := range routes {
+		handler := rt.handler
+		if s.limiter != nil && rt.limit != "" {
+			handler = s.limiter.Wrap(rt.limit, handler)
+		}
+		if !rt.public {
+			// Маршрут без права и без пометки public — ошибка в таблице, а не открытый доступ
+			if rt.scope == "" {
+				panic(fmt.Sprintf("route %q has no required scope", rt.pattern))
+			}
+			handler = s.authn.RequireScope(rt.scope, handler)
+			if s.limiter != nil {
+				handler = s.limiter.WrapClientIP(RateLimitIP, handler)
+			}
+		}
+		mux.HandleFunc(rt.pattern, handler)
+	}
+}
+
+func (s *Server) health(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"ok"}`)) //nolint:errcheck // it's ok
+}
+
 func (s *Server) withMiddleware(next http.Handler) http.Handler {
-	return s.loggingMiddleware(s.recoveryMiddleware(s.corsMiddleware(next)))
+	return s.requestIDMiddleware(s.tracingMiddleware(s.loggingMiddleware(s.recoveryMiddleware(s.corsMiddleware(next)))))
+}
+
+// requestIDMiddleware принимает X-Request-ID клиента или прокси либо генерирует новый,
+// кладет его в контекст (логи с этим контекстом получают request_id) и возвращает в ответе
+func (s *Server) requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = logging.NewRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID пропускает только короткие идентификаторы из безопасных символов:
+// значение попадает в логи и заголовки ответа
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/', c == '+', c == '=':
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
@@ -73,7 +214,7 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 
 		next.ServeHTTP(wrapped, r)
 
-		s.logger.Info("request completed",
+		s.logger.InfoContext(r.Context(), "request completed",
 			slog.String("method", r.Method),
 			slog.String("path", r.URL.Path),
 			slog.Int("status", wrapped.statusCode),
@@ -86,8 +227,7 @@ func (s *Server) recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				s.logger.Error("panic recovered", slog.Any("error", err))
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				problem.Write(w, r, fmt.Errorf("panic recovered: %v", err), s.logger)
 			}
 		}()
 		next.ServeHTTP(w, r)
@@ -98,7 +238,8 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID, traceparent, tracestate, If-None-Match, If-Modified-Since")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified, WWW-Authenticate, X-Request-ID, Retry-After, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
@@ -119,6 +260,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap дает http.ResponseController доступ к исходному writer (Flush, дедлайны записи)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Start запускает HTTP сервер
 func (s *Server) Start() error {
 	s.logger.Info("starting HTTP server", slog.String("addr", s.server.Addr))
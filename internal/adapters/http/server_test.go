@@ -0,0 +1,291 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/logging"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/ratelimit"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// expectedRoutes фиксирует право и класс лимита каждого маршрута; пустое право — публичный маршрут.
+// Изменение таблицы маршрутов без правки этого списка ломает тест намеренно.
+var expectedRoutes = map[string]struct {
+	scope string
+	limit string
+}{
+	"GET /health":       {"", ""},
+	"GET /livez":        {"", ""},
+	"GET /readyz":       {"", ""},
+	"GET /metrics":      {"", ""},
+	"GET /openapi.json": {"", ""},
+	"GET /docs":         {"", ""},
+	"GET /docs/{file}":  {"", ""},
+
+	"GET /items":                            {auth.ScopeItemsRead, RateLimitRead},
+	"GET /items/insights":                   {auth.ScopeItemsRead, RateLimitRead},
+	"GET /items/stream":                     {auth.ScopeItemsRead, RateLimitRead},
+	"GET /items/{market_hash_name}/history": {auth.ScopeItemsRead, RateLimitRead},
+	"GET /items/{market_hash_name}/prices":  {auth.ScopeItemsRead, RateLimitRead},
+
+	"GET /users/{id}/balance":   {auth.ScopeBalanceRead, RateLimitRead},
+	"POST /users/{id}/withdraw": {auth.ScopeBalanceWrite, RateLimitMoney},
+	"POST /users/{id}/deposit":  {auth.ScopeBalanceDeposit, RateLimitMoney},
+	"POST /users/{id}/freeze":   {auth.ScopeAccountsFreeze, RateLimitDefault},
+	"DELETE /users/{id}/freeze": {auth.ScopeAccountsFreeze, RateLimitDefault},
+	"POST /users/{id}/alerts":   {auth.ScopeAlertsWrite, RateLimitDefault},
+	"GET /users/{id}/alerts":    {auth.ScopeAlertsRead, RateLimitRead},
+
+	"GET /admin/cache/keys":             {auth.ScopeCacheAdmin, RateLimitRead},
+	"POST /admin/cache/refresh":         {auth.ScopeCacheAdmin, RateLimitDefault},
+	"DELETE /admin/cache":               {auth.ScopeCacheAdmin, RateLimitDefault},
+	"DELETE /admin/cache/keys/{key...}": {auth.ScopeCacheAdmin, RateLimitDefault},
+}
+
+// testAuthenticator берет права из заголовка X-Test-Scopes; без заголовка учетных данных нет
+var testAuthenticator = auth.AuthenticatorFunc(func(r *http.Request) (*auth.Principal, error) {
+	scopes, ok := r.Header["X-Test-Scopes"]
+	if !ok {
+		return nil, auth.ErrNoCredentials
+	}
+	return &auth.Principal{Subject: "1", Scopes: strings.Fields(strings.Join(scopes, " ")), Method: "test"}, nil
+})
+
+// newStubMux регистрирует таблицу маршрутов сервера с заглушками вместо обработчиков
+func newStubMux(t *testing.T) (*http.ServeMux, []route) {
+	t.Helper()
+	return newLimitedStubMux(t, nil)
+}
+
+// newLimitedStubMux как newStubMux, но с ограничителем частоты запросов
+func newLimitedStubMux(t *testing.T, limiter *RateLimiter) (*http.ServeMux, []route) {
+	t.Helper()
+
+	s := &Server{
+		authn:    auth.NewMiddleware(testAuthenticator, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		limiter:  limiter,
+		registry: prometheus.NewRegistry(),
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	routes := s.routes()
+	for i := range routes {
+		routes[i].handler = func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}
+
+	mux := http.NewServeMux()
+	s.register(mux, routes)
+	return mux, routes
+}
+
+// requestFor строит запрос, подходящий под шаблон маршрута
+func requestFor(pattern string) *http.Request {
+	method, path, _ := strings.Cut(pattern, " ")
+	path = strings.NewReplacer("{id}", "1", "{market_hash_name}", "AK-47", "{key...}", "skinport:items", "{file}", "swagger-ui.css").Replace(path)
+	return httptest.NewRequest(method, path, nil)
+}
+
+func TestServer_RouteTableMatchesExpectedRoutes(t *testing.T) {
+	_, routes := newStubMux(t)
+
+	seen := make(map[string]bool)
+	for _, rt := range routes {
+		seen[rt.pattern] = true
+
+		want, ok := expectedRoutes[rt.pattern]
+		if !ok {
+			t.Errorf("route %q is missing from expectedRoutes", rt.pattern)
+			continue
+		}
+		if rt.public != (want.scope == "") || rt.scope != want.scope {
+			t.Errorf("route %q: expected scope %q, got scope %q public=%v", rt.pattern, want.scope, rt.scope, rt.public)
+		}
+		if rt.limit != want.limit {
+			t.Errorf("route %q: expected rate limit %q, got %q", rt.pattern, want.limit, rt.limit)
+		}
+	}
+
+	for pattern := range expectedRoutes {
+		if !seen[pattern] {
+			t.Errorf("expected route %q is not registered", pattern)
+		}
+	}
+}
+
+func TestServer_RouteAuthorization(t *testing.T) {
+	mux, routes := newStubMux(t)
+
+	for _, rt := range routes {
+		t.Run(rt.pattern, func(t *testing.T) {
+			tests := []struct {
+				name   string
+				scopes *string
+				want   int
+			}{
+				{name: "no credentials", scopes: nil, want: http.StatusUnauthorized},
+				{name: "unrelated scope", scopes: ptr("unrelated:scope"), want: http.StatusForbidden},
+				{name: "required scope", scopes: ptr(rt.scope), want: http.StatusTeapot},
+				{name: "admin role", scopes: ptr(auth.RoleAdmin), want: http.StatusTeapot},
+			}
+
+			for _, tt := range tests {
+				req := requestFor(rt.pattern)
+				if tt.scopes != nil {
+					req.Header.Set("X-Test-Scopes", *tt.scopes)
+				}
+				rec := httptest.NewRecorder()
+				mux.ServeHTTP(rec, req)
+
+				want := tt.want
+				if rt.public {
+					want = http.StatusTeapot
+				}
+				if rec.Code != want {
+					t.Errorf("%s: expected %d, got %d", tt.name, want, rec.Code)
+				}
+
+				switch rec.Code {
+				case http.StatusUnauthorized:
+					if !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Bearer") {
+						t.Errorf("%s: 401 must carry a Bearer challenge", tt.name)
+					}
+				case http.StatusForbidden:
+					if !strings.Contains(rec.Header().Get("WWW-Authenticate"), `error="insufficient_scope"`) {
+						t.Errorf("%s: 403 must report insufficient_scope", tt.name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestServer_UnauthenticatedRequestsAreRateLimited(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), map[string]output.RateLimit{
+		RateLimitRead:    {Rate: 1000, Burst: 1000},
+		RateLimitMoney:   {Rate: 1000, Burst: 1000},
+		RateLimitDefault: {Rate: 1000, Burst: 1000},
+		RateLimitIP:      {Rate: 0.001, Burst: 3},
+	}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	mux, _ := newLimitedStubMux(t, limiter)
+
+	serve := func(remoteAddr string, scopes ...string) int {
+		r := httptest.NewRequest(http.MethodGet, "/items", nil)
+		r.RemoteAddr = remoteAddr
+		if scopes != nil {
+			r.Header.Set("X-Test-Scopes", strings.Join(scopes, " "))
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	for i := range 3 {
+		if code := serve("203.0.113.5:1"); code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected 401, got %d", i+1, code)
+		}
+	}
+	// Бюджет адреса исчерпан неудачными попытками: дальше 429 до проверки учетных данных
+	if code := serve("203.0.113.5:1"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after repeated 401, got %d", code)
+	}
+	if code := serve("203.0.113.5:1", auth.ScopeItemsRead); code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 for credentials from the same address, got %d", code)
+	}
+	// Другой адрес расходует свой бюджет
+	if code := serve("198.51.100.7:1", auth.ScopeItemsRead); code != http.StatusTeapot {
+		t.Fatalf("expected other address to pass, got %d", code)
+	}
+}
+
+func TestServer_RequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "generated when missing", incoming: "", keep: false},
+		{name: "accepted from client", incoming: "edge-7f3a:01", keep: true},
+		{name: "replaced when unsafe", incoming: "bad id\n\"injected\"", keep: false},
+		{name: "replaced when too long", incoming: strings.Repeat("a", maxRequestIDLength+1), keep: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			s := &Server{logger: slog.New(logging.NewContextHandler(slog.NewJSONHandler(&buf, nil)))}
+
+			var seen string
+			h := s.withMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = logging.RequestID(r.Context())
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(RequestIDHeader)
+			if got == "" || got != seen {
+				t.Fatalf("response id %q must match context id %q", got, seen)
+			}
+			if (got == tt.incoming) != tt.keep {
+				t.Errorf("incoming id %q: keep=%v, got %q", tt.incoming, tt.keep, got)
+			}
+
+			// Запись loggingMiddleware содержит тот же идентификатор
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to decode access log %q: %v", buf.String(), err)
+			}
+			if record[logging.RequestIDKey] != got {
+				t.Errorf("access log request_id = %v, want %q", record[logging.RequestIDKey], got)
+			}
+		})
+	}
+}
+
+func TestServer_MetricsByRoutePattern(t *testing.T) {
+	mux, _ := newStubMux(t)
+	s := &Server{registry: prometheus.NewRegistry()}
+	s.metrics = newHTTPMetrics(s.registry)
+	h := s.metricsMiddleware(mux)
+
+	for _, path := range []string{"/users/1/balance", "/users/2/balance", "/nope"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Test-Scopes", auth.RoleAdmin)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/users/{id}/balance",status="418"} 2`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/users/{id}/balance",status="418"} 2`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in metrics:\n%s", want, out.String())
+		}
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/akonovalovdev/DDD_example/internal/pkg/logging"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/tracing"
+)
+
+// tracingMiddleware продолжает трассу вызывающего из traceparent или начинает новую.
+// Span называется по методу, пока роутинг не уточнит шаблон маршрута (routeSpanMiddleware).
+func (s *Server) tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := tracing.Extract(r.Context(), r.Header)
+		ctx, span := tracing.Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("request.id", logging.RequestID(ctx)),
+			),
+		)
+		defer span.End()
+
+		// Логи запроса получают trace_id, чтобы из записи можно было перейти к трассе
+		if sc := span.SpanContext(); sc.IsValid() {
+			ctx = logging.WithAttrs(ctx, slog.String("trace_id", sc.TraceID().String()))
+		}
+
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", wrapped.statusCode))
+		if wrapped.statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(wrapped.statusCode))
+		}
+	})
+}
+
+// routeSpanMiddleware оборачивает mux напрямую и после роутинга переименовывает
+// серверный span по шаблону маршрута: "POST /users/{id}/withdraw"
+func routeSpanMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		if r.Pattern == "" {
+			return
+		}
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Pattern)
+		if _, path, found := strings.Cut(r.Pattern, " "); found {
+			span.SetAttributes(attribute.String("http.route", path))
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/akonovalovdev/DDD_example/internal/adapters/http/auth"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/logging"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/tracing"
+)
+
+func TestServer_TracingContinuesIncomingTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	var buf bytes.Buffer
+	mux, _ := newStubMux(t)
+	s := &Server{logger: slog.New(logging.NewContextHandler(slog.NewJSONHandler(&buf, nil)))}
+	h := s.withMiddleware(routeSpanMiddleware(mux))
+
+	req := httptest.NewRequest(http.MethodPost, "/users/42/withdraw", nil)
+	req.Header.Set("X-Test-Scopes", auth.RoleAdmin)
+	req.Header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "POST /users/{id}/withdraw" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("unexpected span %q kind %v", span.Name(), span.SpanKind())
+	}
+	if span.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || span.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("span must continue the incoming trace, got trace %s parent %s", span.SpanContext().TraceID(), span.Parent().SpanID())
+	}
+
+	attrs := attribute.NewSet(span.Attributes()...)
+	route, _ := attrs.Value("http.route")
+	status, _ := attrs.Value("http.response.status_code")
+	if route.AsString() != "/users/{id}/withdraw" || status.AsInt64() != http.StatusTeapot {
+		t.Errorf("unexpected attributes %v", span.Attributes())
+	}
+
+	// Запись loggingMiddleware связана с трассой
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to decode access log %q: %v", buf.String(), err)
+	}
+	if record["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("access log trace_id = %v", record["trace_id"])
+	}
+}
@@ -0,0 +1,221 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// scanBatchSize подсказка Redis для количества ключей за одну итерацию SCAN
+const scanBatchSize = 500
+
+// scanType ограничивает SCAN строковыми ключами: значения кэша — строки, а ключи других
+// типов под тем же префиксом (например, hash bucket'ов rate limit) кэшу не принадлежат.
+// Опция TYPE появилась в Redis 6.0.
+const scanType = "string"
+
+// Cache реализует output.Cache поверх Redis.
+// Все ключи получают общий префикс, поэтому несколько приложений могут делить один Redis,
+// а Clear удаляет только собственные ключи. Ошибки Redis не пробрасываются: интерфейс
+// кеша не возвращает ошибок, поэтому недоступный Redis ведет себя как промах кеша.
+type Cache struct {
+	client *goredis.Client
+	codec  Codec
+	prefix string
+	logger *slog.Logger
+}
+
+// NewCache создает кеш поверх клиента Redis
+func NewCache(client *goredis.Client, codec Codec, keyPrefix string, logger *slog.Logger) *Cache {
+	return &Cache{
+		client: client,
+		codec:  codec,
+		prefix: keyPrefix,
+		logger: logger,
+	}
+}
+
+// Get получает значение из кэша
+func (c *Cache) Get(ctx context.Context, key string) (interface{}, bool) {
+	data, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, false
+	}
+	if err != nil {
+		c.logger.WarnContext(ctx, "redis get failed", "key", key, "error", err)
+		return nil, false
+	}
+
+	value, err := c.codec.Unmarshal(data)
+	if err != nil {
+		// Значение записано несовместимой версией приложения — считаем промахом
+		c.logger.WarnContext(ctx, "failed to decode cached value", "key", key, "error", err)
+		return nil, false
+	}
+
+	return value, true
+}
+
+// GetWithTTL получает значение и оставшееся время жизни ключа (PTTL). Команды выполняются
+// по очереди: если ключ перезаписан между ними, TTL относится к новой версии значения,
+// о которой реплики узнают по инвалидации. Ошибка PTTL дает нулевой, то есть неизвестный, TTL.
+func (c *Cache) GetWithTTL(ctx context.Context, key string) (interface{}, time.Duration, bool) {
+	value, ok := c.Get(ctx, key)
+	if !ok {
+		return nil, 0, false
+	}
+
+	ttl, err := c.client.PTTL(ctx, c.prefix+key).Result()
+	if err != nil {
+		c.logger.WarnContext(ctx, "redis pttl failed", "key", key, "error", err)
+		return value, 0, true
+	}
+
+	// go-redis возвращает служебные ответы PTTL как есть: -1 — без TTL, -2 — ключа нет
+	switch {
+	case ttl == -1:
+		return value, -1, true
+	case ttl <= 0:
+		// Ключ истек или удален сразу после чтения
+		return value, 0, true
+	default:
+		return value, ttl, true
+	}
+}
+
+// Set устанавливает значение в кэш с указанным TTL.
+// TTL округляется до миллисекунд (точность PX); неположительный TTL удаляет ключ,
+// как и в in-memory кэше, где такое значение сразу считается устаревшим.
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	ms := ttl.Milliseconds()
+	if ttl > 0 && ms == 0 {
+		ms = 1
+	}
+	if ms <= 0 {
+		c.Delete(ctx, key)
+		return
+	}
+
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "failed to encode cache value", "key", key, "error", err)
+		return
+	}
+
+	if err := c.client.Set(ctx, c.prefix+key, data, time.Duration(ms)*time.Millisecond).Err(); err != nil {
+		c.logger.WarnContext(ctx, "redis set failed", "key", key, "error", err)
+	}
+}
+
+// Delete удаляет значение из кэша
+func (c *Cache) Delete(ctx context.Context, key string) {
+	if err := c.client.Del(ctx, c.prefix+key).Err(); err != nil {
+		c.logger.WarnContext(ctx, "redis delete failed", "key", key, "error", err)
+	}
+}
+
+// Clear удаляет все строковые ключи с префиксом кэша. Используется SCAN, а не KEYS,
+// чтобы не блокировать Redis на больших базах. Без префикса очищается вся база.
+func (c *Cache) Clear(ctx context.Context) {
+	if c.prefix == "" {
+		if err := c.client.FlushDB(ctx).Err(); err != nil {
+			c.logger.WarnContext(ctx, "redis flushdb failed", "error", err)
+		}
+		return
+	}
+
+	err := c.scan(ctx, func(keys []string) error {
+		return c.client.Del(ctx, keys...).Err()
+	})
+	if err != nil {
+		c.logger.WarnContext(ctx, "redis clear failed", "error", err)
+	}
+}
+
+// Entries возвращает ключи кэша с оставшимся TTL и размером сериализованного значения
+func (c *Cache) Entries(ctx context.Context) ([]output.CacheEntryInfo, error) {
+	var entries []output.CacheEntryInfo
+	err := c.scan(ctx, func(keys []string) error {
+		for _, k := range keys {
+			ttl, err := c.client.PTTL(ctx, k).Result()
+			if err != nil {
+				return err
+			}
+			if ttl == -2 {
+				// Ключ истек между SCAN и PTTL
+				continue
+			}
+
+			size, err := c.client.StrLen(ctx, k).Result()
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, output.CacheEntryInfo{
+				Key:       strings.TrimPrefix(k, c.prefix),
+				ExpiresIn: ttl,
+				SizeBytes: size,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// scan обходит строковые ключи с префиксом кэша пачками SCAN
+func (c *Cache) scan(ctx context.Context, fn func(keys []string) error) error {
+	pattern := escapeGlob(c.prefix) + "*"
+	var cursor uint64
+	for {
+		keys, next, err := c.client.ScanType(ctx, cursor, pattern, scanBatchSize, scanType).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// Ping проверяет доступность Redis
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// Client возвращает клиент Redis кэша, например для шины инвалидаций
+func (c *Cache) Client() *goredis.Client {
+	return c.client
+}
+
+// Close закрывает пул соединений клиента
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
+
+// escapeGlob экранирует спецсимволы glob-шаблона Redis в префиксе
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
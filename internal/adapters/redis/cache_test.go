@@ -0,0 +1,234 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+func newTestCache(t *testing.T, addr string, codec Codec, prefix string) *Cache {
+	t.Helper()
+
+	codec.Register("items", []*item.Item{})
+	codec.Register("sales_history", map[string]*item.SalesHistory{})
+
+	client := newTestClient(t, addr)
+
+	// Уникальный префикс, чтобы тесты не задевали чужие ключи в настоящем Redis
+	prefix = fmt.Sprintf("%stest:%d:", prefix, time.Now().UnixNano())
+
+	c := NewCache(client, codec, prefix, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	t.Cleanup(func() { c.Clear(context.Background()) })
+	return c
+}
+
+func testItems() []*item.Item {
+	price := decimal.RequireFromString("12.34")
+	return []*item.Item{
+		{MarketHashName: "AK-47 | Redline (Field-Tested)", Currency: "USD", TradableMinPrice: &price, Quantity: 3},
+		{MarketHashName: "AWP | Asiimov (Field-Tested)", Currency: "USD"},
+	}
+}
+
+func TestCache_RoundTrip(t *testing.T) {
+	addr := testAddr(t)
+
+	for name, codec := range map[string]Codec{"json": NewJSONCodec(), "gob": NewGobCodec()} {
+		t.Run(name, func(t *testing.T) {
+			c := newTestCache(t, addr, codec, "app:")
+			ctx := context.Background()
+
+			if _, ok := c.Get(ctx, "skinport:items"); ok {
+				t.Fatal("expected miss on empty cache")
+			}
+
+			c.Set(ctx, "skinport:items", testItems(), time.Minute)
+
+			cached, ok := c.Get(ctx, "skinport:items")
+			if !ok {
+				t.Fatal("expected hit after set")
+			}
+			items, ok := cached.([]*item.Item)
+			if !ok {
+				t.Fatalf("expected []*item.Item, got %T", cached)
+			}
+			if len(items) != 2 || !items[0].TradableMinPrice.Equal(decimal.RequireFromString("12.34")) ||
+				items[0].Quantity != 3 || items[1].TradableMinPrice != nil {
+				t.Errorf("unexpected items after round trip: %+v", items)
+			}
+
+			c.Delete(ctx, "skinport:items")
+			if _, ok := c.Get(ctx, "skinport:items"); ok {
+				t.Error("expected miss after delete")
+			}
+		})
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	r := newTestRedis(t)
+	c := newTestCache(t, r.addr, NewJSONCodec(), "app:")
+	ctx := context.Background()
+
+	c.Set(ctx, "short", testItems(), 50*time.Millisecond)
+	ttl, err := c.client.PTTL(ctx, c.prefix+"short").Result()
+	if err != nil {
+		t.Fatalf("pttl failed: %v", err)
+	}
+	if ttl <= 0 || ttl > 50*time.Millisecond {
+		t.Errorf("expected ttl in (0, 50]ms, got %v", ttl)
+	}
+
+	r.wait(100 * time.Millisecond)
+	if _, ok := c.Get(ctx, "short"); ok {
+		t.Error("expected entry to expire")
+	}
+
+	// Неположительный TTL удаляет существующее значение
+	c.Set(ctx, "gone", testItems(), time.Minute)
+	c.Set(ctx, "gone", testItems(), 0)
+	if _, ok := c.Get(ctx, "gone"); ok {
+		t.Error("expected zero ttl to remove entry")
+	}
+}
+
+func TestCache_GetWithTTL(t *testing.T) {
+	c := newTestCache(t, testAddr(t), NewJSONCodec(), "app:")
+	ctx := context.Background()
+
+	c.Set(ctx, "k", testItems(), time.Minute)
+	value, ttl, ok := c.GetWithTTL(ctx, "k")
+	if !ok || value == nil {
+		t.Fatalf("expected hit, got %v, %v", value, ok)
+	}
+	if ttl <= 0 || ttl > time.Minute {
+		t.Errorf("expected ttl in (0, 1m], got %v", ttl)
+	}
+
+	if _, _, ok := c.GetWithTTL(ctx, "missing"); ok {
+		t.Error("expected miss for missing key")
+	}
+}
+
+func TestCache_ClearOnlyOwnPrefix(t *testing.T) {
+	addr := testAddr(t)
+	ctx := context.Background()
+
+	// Спецсимволы glob в префиксе не должны расширять шаблон SCAN
+	own := newTestCache(t, addr, NewJSONCodec(), "a*")
+	other := newTestCache(t, addr, NewJSONCodec(), "ab")
+
+	own.Set(ctx, "k1", testItems(), time.Minute)
+	own.Set(ctx, "k2", testItems(), time.Minute)
+	other.Set(ctx, "k1", testItems(), time.Minute)
+
+	own.Clear(ctx)
+
+	if _, ok := own.Get(ctx, "k1"); ok {
+		t.Error("expected own keys to be cleared")
+	}
+	if _, ok := own.Get(ctx, "k2"); ok {
+		t.Error("expected own keys to be cleared")
+	}
+	if _, ok := other.Get(ctx, "k1"); !ok {
+		t.Error("expected keys with another prefix to survive")
+	}
+}
+
+func TestCache_UnknownTypeIsMiss(t *testing.T) {
+	addr := testAddr(t)
+	ctx := context.Background()
+
+	writer := newTestCache(t, addr, NewJSONCodec(), "app:")
+	writer.codec.Register("insight", item.Insight{})
+	writer.Set(ctx, "v2", item.Insight{}, time.Minute)
+
+	// Читатель без регистрации типа (например, старая реплика) получает промах, а не панику
+	reader := NewCache(writer.client, NewJSONCodec(), writer.prefix, writer.logger)
+	if _, ok := reader.Get(ctx, "v2"); ok {
+		t.Error("expected miss for unregistered type")
+	}
+
+	// Незарегистрированный тип при записи не попадает в кеш
+	writer.Set(ctx, "raw", struct{ A int }{1}, time.Minute)
+	if _, ok := writer.Get(ctx, "raw"); ok {
+		t.Error("expected unregistered value not to be stored")
+	}
+}
+
+func TestCache_UnavailableRedisIsMiss(t *testing.T) {
+	client := NewClient(ClientOptions{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond})
+	defer client.Close()
+
+	c := NewCache(client, NewJSONCodec(), "app:", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	c.Set(ctx, "k", testItems(), time.Minute)
+	if _, ok := c.Get(ctx, "k"); ok {
+		t.Error("expected miss when redis is unavailable")
+	}
+}
+
+func TestCache_Entries(t *testing.T) {
+	c := newTestCache(t, testAddr(t), NewJSONCodec(), "app:")
+	ctx := context.Background()
+
+	c.Set(ctx, "skinport:items", testItems(), time.Minute)
+
+	entries, err := c.Entries(ctx)
+	if err != nil {
+		t.Fatalf("entries failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", entries)
+	}
+
+	e := entries[0]
+	if e.Key != "skinport:items" {
+		t.Errorf("expected key without prefix, got %q", e.Key)
+	}
+	if e.ExpiresIn <= 0 || e.ExpiresIn > time.Minute {
+		t.Errorf("expected remaining ttl within a minute, got %s", e.ExpiresIn)
+	}
+	if e.SizeBytes <= 0 {
+		t.Errorf("expected encoded size, got %d", e.SizeBytes)
+	}
+}
+
+func TestCache_IgnoresRateLimitBuckets(t *testing.T) {
+	c := newTestCache(t, testAddr(t), NewJSONCodec(), "app:")
+	ctx := context.Background()
+
+	// Худший случай: bucket'ы rate limit под префиксом кэша (как до отдельного RATE_LIMIT_REDIS_PREFIX)
+	store := NewRateLimitStore(c.client, c.prefix+"ratelimit:")
+	limit := output.RateLimit{Rate: 0.01, Burst: 1}
+	if res, err := store.Take(ctx, "client", limit); err != nil || !res.Allowed {
+		t.Fatalf("expected first take to pass, got %+v, %v", res, err)
+	}
+
+	c.Set(ctx, "skinport:items", testItems(), time.Minute)
+
+	entries, err := c.Entries(ctx)
+	if err != nil {
+		t.Fatalf("entries must skip non-string keys, got %v", err)
+	}
+	if len(entries) != 1 || entries[0].Key != "skinport:items" {
+		t.Errorf("expected only the cache entry, got %+v", entries)
+	}
+
+	c.Clear(ctx)
+	if _, ok := c.Get(ctx, "skinport:items"); ok {
+		t.Error("expected cache entry to be cleared")
+	}
+	if res, _ := store.Take(ctx, "client", limit); res.Allowed {
+		t.Error("clearing the cache must not reset rate limit budgets")
+	}
+}
@@ -0,0 +1,46 @@
+// Package redis реализует адаптеры поверх Redis на клиенте go-redis
+package redis
+
+import (
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ClientOptions параметры клиента Redis
+type ClientOptions struct {
+	Addr     string
+	Password string
+	DB       int
+
+	// PoolSize максимальное количество одновременно открытых соединений
+	PoolSize int
+	// MaxIdle сколько соединений держать открытыми между запросами
+	MaxIdle int
+
+	DialTimeout time.Duration
+	// IOTimeout дедлайн чтения/записи команды, если контекст не задает более ранний
+	IOTimeout time.Duration
+}
+
+// NewClient создает клиент go-redis с пулом соединений. Соединения открываются лениво.
+func NewClient(opts ClientOptions) *goredis.Client {
+	return goredis.NewClient(&goredis.Options{
+		Addr:         opts.Addr,
+		Password:     opts.Password,
+		DB:           opts.DB,
+		PoolSize:     opts.PoolSize,
+		MaxIdleConns: opts.MaxIdle,
+		DialTimeout:  opts.DialTimeout,
+		ReadTimeout:  opts.IOTimeout,
+		WriteTimeout: opts.IOTimeout,
+		// Дедлайн контекста запроса ограничивает и команду Redis
+		ContextTimeoutEnabled: true,
+		// Кэш и лимитер переживают недоступный Redis сами: повторы команд и соединения
+		// только задержали бы промах
+		MaxRetries:    -1,
+		DialerRetries: 1,
+		// CLIENT SETINFO не нужен приложению и неизвестен Redis до 7.2
+		DisableIdentity: true,
+	})
+}
@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestNewClient_AuthAndDB(t *testing.T) {
+	m := miniredis.RunT(t)
+	m.RequireAuth("secret")
+
+	ctx := context.Background()
+
+	wrong := NewClient(ClientOptions{Addr: m.Addr(), Password: "wrong"})
+	defer wrong.Close()
+	if err := wrong.Ping(ctx).Err(); err == nil {
+		t.Error("expected auth error with wrong password")
+	}
+
+	client := NewClient(ClientOptions{Addr: m.Addr(), Password: "secret", DB: 2, IOTimeout: time.Second})
+	defer client.Close()
+	if err := client.Set(ctx, "k", "v", 0).Err(); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if got, err := m.DB(2).Get("k"); err != nil || got != "v" {
+		t.Errorf("expected value in db 2, got %q, %v", got, err)
+	}
+}
+
+func TestNewClient_UnavailableRedisFailsFast(t *testing.T) {
+	client := NewClient(ClientOptions{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond})
+	defer client.Close()
+
+	// Без повторов ошибка возвращается после одной попытки соединения
+	start := time.Now()
+	if err := client.Ping(context.Background()).Err(); err == nil {
+		t.Fatal("expected error for unavailable redis")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected fast failure without retries, took %s", elapsed)
+	}
+}
@@ -0,0 +1,181 @@
+package redis
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrUnregisteredType возвращается при сериализации значения незарегистрированного типа
+var ErrUnregisteredType = errors.New("redis: type is not registered in codec")
+
+// Codec сериализует значения кэша. Redis хранит только байты, поэтому для восстановления
+// исходного типа (например, []*item.Item) кодек записывает вместе со значением имя типа,
+// а типы заранее регистрируются через Register.
+type Codec interface {
+	// Register связывает имя с типом образца. Имя попадает в Redis и должно быть стабильным
+	// между релизами и одинаковым на всех репликах.
+	Register(name string, sample interface{})
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte) (interface{}, error)
+}
+
+// typeRegistry двусторонний реестр имя ↔ тип
+type typeRegistry struct {
+	mu     sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}
+
+func newTypeRegistry() typeRegistry {
+	return typeRegistry{
+		byName: make(map[string]reflect.Type),
+		byType: make(map[reflect.Type]string),
+	}
+}
+
+func (r *typeRegistry) Register(name string, sample interface{}) {
+	t := reflect.TypeOf(sample)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byName[name] = t
+	r.byType[t] = name
+}
+
+func (r *typeRegistry) nameOf(v interface{}) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name, ok := r.byType[reflect.TypeOf(v)]
+	if !ok {
+		return "", fmt.Errorf("%w: %T", ErrUnregisteredType, v)
+	}
+	return name, nil
+}
+
+func (r *typeRegistry) typeOf(name string) (reflect.Type, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnregisteredType, name)
+	}
+	return t, nil
+}
+
+// JSONCodec хранит значения как JSON: {"type": "<имя>", "value": ...}.
+// Читается любым клиентом Redis, удобен для отладки.
+type JSONCodec struct {
+	types typeRegistry
+}
+
+// NewJSONCodec создает JSON кодек
+func NewJSONCodec() *JSONCodec {
+	return &JSONCodec{types: newTypeRegistry()}
+}
+
+type jsonEnvelope struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Register регистрирует тип значения
+func (c *JSONCodec) Register(name string, sample interface{}) {
+	c.types.Register(name, sample)
+}
+
+// Marshal сериализует значение зарегистрированного типа
+func (c *JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	name, err := c.types.nameOf(v)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonEnvelope{Type: name, Value: value})
+}
+
+// Unmarshal восстанавливает значение исходного типа
+func (c *JSONCodec) Unmarshal(data []byte) (interface{}, error) {
+	var env jsonEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+
+	t, err := c.types.typeOf(env.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	ptr := reflect.New(t)
+	if err := json.Unmarshal(env.Value, ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", env.Type, err)
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// GobCodec хранит значения в формате encoding/gob: имя типа, затем значение.
+// Компактнее JSON, но читается только Go-клиентами.
+type GobCodec struct {
+	types typeRegistry
+}
+
+// NewGobCodec создает gob кодек
+func NewGobCodec() *GobCodec {
+	return &GobCodec{types: newTypeRegistry()}
+}
+
+// Register регистрирует тип значения
+func (c *GobCodec) Register(name string, sample interface{}) {
+	c.types.Register(name, sample)
+}
+
+// Marshal сериализует значение зарегистрированного типа
+func (c *GobCodec) Marshal(v interface{}) ([]byte, error) {
+	name, err := c.types.nameOf(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(name); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal восстанавливает значение исходного типа
+func (c *GobCodec) Unmarshal(data []byte) (interface{}, error) {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+
+	var name string
+	if err := dec.Decode(&name); err != nil {
+		return nil, err
+	}
+
+	t, err := c.types.typeOf(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ptr := reflect.New(t)
+	if err := dec.DecodeValue(ptr); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	return ptr.Elem().Interface(), nil
+}
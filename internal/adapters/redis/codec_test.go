@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+func TestCodecs_SalesHistoryRoundTrip(t *testing.T) {
+	median := decimal.RequireFromString("9.99")
+	history := map[string]*item.SalesHistory{
+		"AK-47 | Redline (Field-Tested)": {
+			MarketHashName: "AK-47 | Redline (Field-Tested)",
+			Currency:       "EUR",
+			Last7Days:      item.SalesStats{Median: &median, Volume: 12},
+		},
+	}
+
+	for name, codec := range map[string]Codec{"json": NewJSONCodec(), "gob": NewGobCodec()} {
+		t.Run(name, func(t *testing.T) {
+			codec.Register("sales_history", map[string]*item.SalesHistory{})
+
+			data, err := codec.Marshal(history)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+
+			decoded, err := codec.Unmarshal(data)
+			if err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+
+			got, ok := decoded.(map[string]*item.SalesHistory)
+			if !ok {
+				t.Fatalf("expected map[string]*item.SalesHistory, got %T", decoded)
+			}
+			h := got["AK-47 | Redline (Field-Tested)"]
+			if h == nil || h.Currency != "EUR" || h.Last7Days.Volume != 12 ||
+				!h.Last7Days.Median.Equal(median) || h.Last24Hours.Median != nil {
+				t.Errorf("unexpected history after round trip: %+v", h)
+			}
+		})
+	}
+}
+
+func TestCodecs_UnregisteredType(t *testing.T) {
+	for name, codec := range map[string]Codec{"json": NewJSONCodec(), "gob": NewGobCodec()} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := codec.Marshal([]*item.Item{}); !errors.Is(err, ErrUnregisteredType) {
+				t.Errorf("expected ErrUnregisteredType, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+const (
+	// subscribePingInterval период PING в режиме подписки: без трафика обрыв
+	// соединения иначе не заметить
+	subscribePingInterval = 15 * time.Second
+
+	minResubscribeBackoff = 500 * time.Millisecond
+	maxResubscribeBackoff = 30 * time.Second
+)
+
+// errPingTimeout подписка не ответила на PING: соединение считается потерянным
+var errPingTimeout = errors.New("redis: subscription ping timeout")
+
+// InvalidationBus рассылает инвалидации кэша через Redis PUBLISH/SUBSCRIBE
+type InvalidationBus struct {
+	client  *goredis.Client
+	channel string
+	logger  *slog.Logger
+
+	pingInterval time.Duration
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+}
+
+// NewInvalidationBus создает шину инвалидаций на канале Redis
+func NewInvalidationBus(client *goredis.Client, channel string, logger *slog.Logger) *InvalidationBus {
+	return &InvalidationBus{
+		client:       client,
+		channel:      channel,
+		logger:       logger,
+		pingInterval: subscribePingInterval,
+		minBackoff:   minResubscribeBackoff,
+		maxBackoff:   maxResubscribeBackoff,
+	}
+}
+
+// Publish рассылает сообщение всем подписчикам канала
+func (b *InvalidationBus) Publish(ctx context.Context, msg output.CacheInvalidation) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+// Subscribe слушает канал до отмены контекста, переподключаясь при обрывах.
+// Подписка держит отдельное соединение вне пула. Переподключение выполняется здесь,
+// а не внутри go-redis: после обрыва обработчик должен получить полный сброс.
+func (b *InvalidationBus) Subscribe(ctx context.Context, handler output.CacheInvalidationHandler) error {
+	backoff := b.minBackoff
+	resubscribed := false
+
+	for {
+		subscribed, err := b.session(ctx, handler, resubscribed)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if subscribed {
+			backoff = b.minBackoff
+			resubscribed = true
+		}
+
+		b.logger.Warn("cache invalidation subscription lost, reconnecting",
+			slog.Any("error", err),
+			slog.Duration("backoff", backoff),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > b.maxBackoff {
+			backoff = b.maxBackoff
+		}
+	}
+}
+
+// session подписывается и читает сообщения до ошибки соединения.
+// Возвращает true, если подписка успела установиться.
+func (b *InvalidationBus) session(ctx context.Context, handler output.CacheInvalidationHandler, resubscribed bool) (bool, error) {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+
+	// Отмена контекста прерывает блокирующее чтение
+	stop := context.AfterFunc(ctx, func() { pubsub.Close() })
+	defer stop()
+
+	reply, err := pubsub.ReceiveTimeout(ctx, b.client.Options().ReadTimeout)
+	if err != nil {
+		return false, err
+	}
+	if sub, ok := reply.(*goredis.Subscription); !ok || sub.Kind != "subscribe" {
+		return false, fmt.Errorf("redis: unexpected subscribe reply %v", reply)
+	}
+
+	b.logger.Info("subscribed to cache invalidations", slog.String("channel", b.channel))
+
+	// Сообщения за время обрыва потеряны — локальные копии нужно сбросить целиком
+	if resubscribed {
+		handler(ctx, output.CacheInvalidation{All: true})
+	}
+
+	// Без трафика обрыв соединения не заметить: в тишине отправляем PING и ждем ответа
+	// не дольше следующего интервала
+	awaitingPong := false
+	for {
+		reply, err := pubsub.ReceiveTimeout(ctx, b.pingInterval)
+		if err != nil {
+			var netErr net.Error
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				return true, err
+			}
+			if awaitingPong {
+				return true, errPingTimeout
+			}
+			if err := pubsub.Ping(ctx); err != nil {
+				return true, err
+			}
+			awaitingPong = true
+			continue
+		}
+		awaitingPong = false
+
+		switch msg := reply.(type) {
+		case *goredis.Message:
+			var inv output.CacheInvalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				b.logger.Warn("invalid cache invalidation message", slog.Any("error", err))
+				continue
+			}
+			handler(ctx, inv)
+		case *goredis.Pong:
+		default:
+			return true, fmt.Errorf("redis: unexpected push message %T", reply)
+		}
+	}
+}
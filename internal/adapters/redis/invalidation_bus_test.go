@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+func TestInvalidationBus_PublishSubscribe(t *testing.T) {
+	srv := miniredis.RunT(t)
+	client := newTestClient(t, srv.Addr())
+
+	bus := NewInvalidationBus(client, "cache:invalidate", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	bus.minBackoff = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan output.CacheInvalidation, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bus.Subscribe(ctx, func(ctx context.Context, msg output.CacheInvalidation) {
+			received <- msg
+		})
+	}()
+
+	waitSubscribed(t, srv, "cache:invalidate")
+
+	if err := bus.Publish(ctx, output.CacheInvalidation{Origin: "a", Key: "skinport:items"}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if msg := receive(t, received); msg.Origin != "a" || msg.Key != "skinport:items" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+
+	// После обрыва подписка восстанавливается и сначала сбрасывает все локальные копии
+	srv.Close()
+	if err := srv.Restart(); err != nil {
+		t.Fatalf("restart failed: %v", err)
+	}
+	if msg := receive(t, received); !msg.All {
+		t.Errorf("expected clear-all after resubscribe, got %+v", msg)
+	}
+
+	waitSubscribed(t, srv, "cache:invalidate")
+	bus.Publish(ctx, output.CacheInvalidation{Origin: "b", Key: "k"})
+	if msg := receive(t, received); msg.Key != "k" {
+		t.Errorf("expected message after resubscribe, got %+v", msg)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected subscribe to return after context cancel")
+	}
+}
+
+func waitSubscribed(t *testing.T, srv *miniredis.Miniredis, channel string) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for srv.PubSubNumSub(channel)[channel] == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber did not connect")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func receive(t *testing.T, ch <-chan output.CacheInvalidation) output.CacheInvalidation {
+	t.Helper()
+
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for invalidation")
+		return output.CacheInvalidation{}
+	}
+}
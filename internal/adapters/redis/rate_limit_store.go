@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/akonovalovdev/DDD_example/internal/pkg/ratelimit"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// rateLimitScript атомарно пополняет token bucket и списывает токен.
+// Время берется из Redis (TIME), чтобы расхождение часов реплик не влияло на бюджет.
+// Ключ живет, пока bucket не наполнится, поэтому неактивные клиенты не занимают память.
+const rateLimitScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+if now > ts then
+  tokens = math.min(burst, tokens + (now - ts) * rate / 1000)
+  ts = now
+end
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ts))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) * 1000 / rate) + 1000)
+return {allowed, tostring(tokens)}
+`
+
+// rateLimitScriptRun исполняется через EVALSHA; скрипт передается целиком (EVAL)
+// только при первом вызове после рестарта Redis
+var rateLimitScriptRun = goredis.NewScript(rateLimitScript)
+
+// RateLimitStore реализует output.RateLimitStore поверх Redis: все реплики делят
+// один бюджет клиента
+type RateLimitStore struct {
+	client *goredis.Client
+	prefix string
+}
+
+// NewRateLimitStore создает хранилище bucket'ов с общим префиксом ключей
+func NewRateLimitStore(client *goredis.Client, keyPrefix string) *RateLimitStore {
+	return &RateLimitStore{
+		client: client,
+		prefix: keyPrefix,
+	}
+}
+
+// Take реализует output.RateLimitStore
+func (s *RateLimitStore) Take(ctx context.Context, key string, limit output.RateLimit) (output.RateLimitResult, error) {
+	if limit.Rate <= 0 || limit.Burst < 1 {
+		return output.RateLimitResult{}, fmt.Errorf("invalid rate limit: rate %v, burst %d", limit.Rate, limit.Burst)
+	}
+
+	reply, err := rateLimitScriptRun.Run(ctx, s.client, []string{s.prefix + key},
+		strconv.FormatFloat(limit.Rate, 'f', -1, 64), limit.Burst).Result()
+	if err != nil {
+		return output.RateLimitResult{}, fmt.Errorf("rate limit script failed: %w", err)
+	}
+
+	allowed, tokens, err := parseRateLimitReply(reply)
+	if err != nil {
+		return output.RateLimitResult{}, err
+	}
+
+	return ratelimit.Result(tokens, allowed, limit), nil
+}
+
+func parseRateLimitReply(reply interface{}) (bool, float64, error) {
+	parts, ok := reply.([]interface{})
+	if !ok || len(parts) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit reply %T", reply)
+	}
+
+	allowed, ok := parts[0].(int64)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected rate limit flag %T", parts[0])
+	}
+
+	raw, ok := parts[1].(string)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected rate limit tokens %T", parts[1])
+	}
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid rate limit tokens %q: %w", raw, err)
+	}
+
+	return allowed == 1, tokens, nil
+}
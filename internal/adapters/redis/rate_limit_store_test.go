@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+func TestRateLimitStore_Take(t *testing.T) {
+	client := newTestClient(t, testAddr(t))
+	store := NewRateLimitStore(client, fmt.Sprintf("test:%d:ratelimit:", time.Now().UnixNano()))
+	ctx := context.Background()
+	// Медленное пополнение, чтобы тест не зависел от скорости выполнения
+	limit := output.RateLimit{Rate: 0.01, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		res, err := store.Take(ctx, "client", limit)
+		if err != nil {
+			t.Fatalf("take %d: %v", i, err)
+		}
+		if !res.Allowed || res.Remaining != 2-i {
+			t.Fatalf("take %d: expected allowed with %d remaining, got %+v", i, 2-i, res)
+		}
+	}
+
+	res, err := store.Take(ctx, "client", limit)
+	if err != nil {
+		t.Fatalf("take over burst: %v", err)
+	}
+	if res.Allowed {
+		t.Fatal("expected request over burst to be rejected")
+	}
+	if res.RetryAfter <= 0 || res.RetryAfter > 100*time.Second {
+		t.Errorf("unexpected retry after: %s", res.RetryAfter)
+	}
+
+	// Второй экземпляр хранилища (другая реплика) видит тот же бюджет
+	other := NewRateLimitStore(client, store.prefix)
+	if res, _ := other.Take(ctx, "client", limit); res.Allowed {
+		t.Error("expected shared budget across store instances")
+	}
+	if res, _ := other.Take(ctx, "another-client", limit); !res.Allowed {
+		t.Error("expected independent budget for another key")
+	}
+}
+
+func TestRateLimitStore_InvalidLimit(t *testing.T) {
+	store := NewRateLimitStore(NewClient(ClientOptions{Addr: "127.0.0.1:1"}), "")
+	if _, err := store.Take(context.Background(), "client", output.RateLimit{Rate: 0, Burst: 1}); err == nil {
+		t.Error("expected error for zero rate")
+	}
+}
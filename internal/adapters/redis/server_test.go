@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// testRedis Redis для тестов. Если задана REDIS_TEST_ADDR, тесты идут против настоящего
+// сервера (например, `docker run -p 6379:6379 redis`), иначе — против miniredis.
+type testRedis struct {
+	addr string
+	mini *miniredis.Miniredis // nil для настоящего Redis
+}
+
+func newTestRedis(t *testing.T) *testRedis {
+	t.Helper()
+
+	if addr := os.Getenv("REDIS_TEST_ADDR"); addr != "" {
+		return &testRedis{addr: addr}
+	}
+
+	m := miniredis.RunT(t)
+	return &testRedis{addr: m.Addr(), mini: m}
+}
+
+// wait ждет d. Время miniredis идет только вперед по команде, поэтому TTL
+// истекают через FastForward.
+func (r *testRedis) wait(d time.Duration) {
+	if r.mini != nil {
+		r.mini.FastForward(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+// testAddr возвращает адрес Redis для тестов
+func testAddr(t *testing.T) string {
+	t.Helper()
+	return newTestRedis(t).addr
+}
+
+// newTestClient создает клиент к addr и закрывает его по завершении теста
+func newTestClient(t *testing.T, addr string) *goredis.Client {
+	t.Helper()
+
+	client := NewClient(ClientOptions{Addr: addr, PoolSize: 4, IOTimeout: time.Second})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
@@ -0,0 +1,93 @@
+// Package file реализует хранилища на локальной файловой системе
+package file
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// catalogueFile формат файла снимка каталога
+type catalogueFile struct {
+	SavedAt time.Time    `json:"saved_at"`
+	Items   []*item.Item `json:"items"`
+}
+
+// CatalogueStore хранит последний каталог в сжатом gzip JSON файле.
+// Запись атомарна: снимок пишется во временный файл рядом и переименовывается,
+// поэтому падение процесса посреди записи не портит предыдущий снимок.
+type CatalogueStore struct {
+	path string
+}
+
+// NewCatalogueStore создает хранилище каталога в указанном файле
+func NewCatalogueStore(path string) *CatalogueStore {
+	return &CatalogueStore{path: path}
+}
+
+// Save заменяет сохраненный каталог
+func (s *CatalogueStore) Save(_ context.Context, items []*item.Item, savedAt time.Time) error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create catalogue directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	// После успешного rename удалять нечего, ошибка Remove игнорируется
+	defer os.Remove(tmp.Name())
+
+	gw := gzip.NewWriter(tmp)
+	if err := json.NewEncoder(gw).Encode(catalogueFile{SavedAt: savedAt.UTC(), Items: items}); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode catalogue: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Load возвращает сохраненный каталог
+func (s *CatalogueStore) Load(_ context.Context) (*item.Catalogue, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, item.ErrCatalogueNotStored
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalogue file: %w", err)
+	}
+	defer gr.Close()
+
+	var stored catalogueFile
+	if err := json.NewDecoder(gr).Decode(&stored); err != nil {
+		return nil, fmt.Errorf("failed to decode catalogue: %w", err)
+	}
+
+	return &item.Catalogue{Items: stored.Items, UpdatedAt: stored.SavedAt}, nil
+}
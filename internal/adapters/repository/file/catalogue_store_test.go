@@ -0,0 +1,71 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+func TestCatalogueStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "catalogue.json.gz")
+	store := NewCatalogueStore(path)
+	ctx := context.Background()
+
+	if _, err := store.Load(ctx); !errors.Is(err, item.ErrCatalogueNotStored) {
+		t.Fatalf("expected ErrCatalogueNotStored, got %v", err)
+	}
+
+	price := decimal.RequireFromString("1234567.8912")
+	savedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	items := []*item.Item{
+		{MarketHashName: "AK-47 | Redline (Field-Tested)", Currency: "USD", TradableMinPrice: &price, Quantity: 3},
+		{MarketHashName: "AWP | Asiimov (Field-Tested)", Currency: "USD"},
+	}
+
+	if err := store.Save(ctx, items, savedAt); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	cat, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if !cat.UpdatedAt.Equal(savedAt) {
+		t.Errorf("expected saved_at %v, got %v", savedAt, cat.UpdatedAt)
+	}
+	if len(cat.Items) != 2 || !cat.Items[0].TradableMinPrice.Equal(price) || cat.Items[1].TradableMinPrice != nil {
+		t.Errorf("unexpected items after round trip: %+v", cat.Items)
+	}
+
+	// Повторное сохранение заменяет снимок и не оставляет временных файлов
+	if err := store.Save(ctx, items[:1], savedAt.Add(time.Hour)); err != nil {
+		t.Fatalf("failed to overwrite: %v", err)
+	}
+	cat, _ = store.Load(ctx)
+	if len(cat.Items) != 1 {
+		t.Errorf("expected overwritten catalogue with 1 item, got %d", len(cat.Items))
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected only the snapshot file in directory, got %d entries", len(entries))
+	}
+}
+
+func TestCatalogueStore_CorruptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalogue.json.gz")
+	if err := os.WriteFile(path, []byte("not gzip"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewCatalogueStore(path).Load(context.Background()); err == nil || errors.Is(err, item.ErrCatalogueNotStored) {
+		t.Errorf("expected decode error for corrupted file, got %v", err)
+	}
+}
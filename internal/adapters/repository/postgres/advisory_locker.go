@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// AdvisoryLocker реализует распределенную блокировку на advisory locks PostgreSQL.
+// Блокировка сессионная: держится на выделенном соединении и снимается сервером
+// автоматически, если экземпляр упал и соединение оборвалось.
+type AdvisoryLocker struct {
+	db *sql.DB
+}
+
+// NewAdvisoryLocker создает новый экземпляр AdvisoryLocker
+func NewAdvisoryLocker(db *sql.DB) *AdvisoryLocker {
+	return &AdvisoryLocker{db: db}
+}
+
+// TryLock пытается захватить блокировку без ожидания (pg_try_advisory_lock)
+func (l *AdvisoryLocker) TryLock(ctx context.Context, name string) (output.Lock, bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	key := advisoryKey(name)
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to acquire advisory lock: %w", err)
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return &advisoryLock{conn: conn, key: key}, true, nil
+}
+
+// advisoryLock захваченная блокировка и соединение, на котором она держится
+type advisoryLock struct {
+	conn *sql.Conn
+	key  int64
+}
+
+// Release снимает блокировку и возвращает соединение в пул
+func (l *advisoryLock) Release(ctx context.Context) error {
+	defer l.conn.Close()
+
+	var released bool
+	if err := l.conn.QueryRowContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key).Scan(&released); err != nil {
+		// Соединение могло остаться с блокировкой — ErrBadConn закрывает его вместо возврата в пул
+		_ = l.conn.Raw(func(any) error { return driver.ErrBadConn })
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
+	if !released {
+		return fmt.Errorf("advisory lock %d was not held", l.key)
+	}
+	return nil
+}
+
+// advisoryKey переводит имя блокировки в 64-битный ключ advisory lock
+func advisoryKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name)) //nolint:errcheck // hash.Hash never returns error
+	return int64(h.Sum64())
+}
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+)
+
+// AlertRepository реализует репозиторий ценовых алертов для PostgreSQL
+type AlertRepository struct {
+	db *sql.DB
+}
+
+// NewAlertRepository создает новый экземпляр AlertRepository
+func NewAlertRepository(db *sql.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// Save сохраняет новый алерт
+func (r *AlertRepository) Save(ctx context.Context, a *alert.Alert) error {
+	query := `
+		INSERT INTO price_alerts (id, user_id, market_hash_name, direction, price_type, threshold, webhook_url, active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		a.ID,
+		a.UserID,
+		a.MarketHashName,
+		string(a.Direction),
+		string(a.PriceType),
+		a.Threshold.String(),
+		a.WebhookURL,
+		a.Active,
+		a.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByUserID возвращает алерты пользователя
+func (r *AlertRepository) GetByUserID(ctx context.Context, userID int64) ([]*alert.Alert, error) {
+	query := `
+		SELECT id, user_id, market_hash_name, direction, price_type, threshold, webhook_url, active, created_at, triggered_at
+		FROM price_alerts
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.query(ctx, query, userID)
+}
+
+// ListActive возвращает все активные алерты
+func (r *AlertRepository) ListActive(ctx context.Context) ([]*alert.Alert, error) {
+	query := `
+		SELECT id, user_id, market_hash_name, direction, price_type, threshold, webhook_url, active, created_at, triggered_at
+		FROM price_alerts
+		WHERE active
+	`
+
+	return r.query(ctx, query)
+}
+
+// MarkTriggered деактивирует алерт, если он еще активен
+func (r *AlertRepository) MarkTriggered(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	query := `UPDATE price_alerts SET active = FALSE, triggered_at = $1 WHERE id = $2 AND active`
+
+	result, err := r.db.ExecContext(ctx, query, at, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// SaveDeadLetter сохраняет недоставленное уведомление
+func (r *AlertRepository) SaveDeadLetter(ctx context.Context, dl *alert.DeadLetter) error {
+	payload, err := json.Marshal(dl.Notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	query := `
+		INSERT INTO alert_dead_letters (id, alert_id, webhook_url, payload, attempts, last_error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		dl.ID,
+		dl.Notification.AlertID,
+		dl.WebhookURL,
+		payload,
+		dl.Attempts,
+		dl.LastError,
+		dl.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *AlertRepository) query(ctx context.Context, query string, args ...interface{}) ([]*alert.Alert, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []*alert.Alert
+
+	for rows.Next() {
+		var a alert.Alert
+		var direction, priceType, threshold string
+		var triggeredAt sql.NullTime
+
+		err := rows.Scan(
+			&a.ID,
+			&a.UserID,
+			&a.MarketHashName,
+			&direction,
+			&priceType,
+			&threshold,
+			&a.WebhookURL,
+			&a.Active,
+			&a.CreatedAt,
+			&triggeredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		a.Direction = alert.Direction(direction)
+		a.PriceType = alert.PriceType(priceType)
+		a.Threshold, _ = decimal.NewFromString(threshold)
+		if triggeredAt.Valid {
+			a.TriggeredAt = &triggeredAt.Time
+		}
+
+		alerts = append(alerts, &a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
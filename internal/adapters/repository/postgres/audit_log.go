@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/audit"
+)
+
+// AuditLog реализует журнал аудита в PostgreSQL
+type AuditLog struct {
+	db *sql.DB
+}
+
+// NewAuditLog создает новый экземпляр AuditLog
+func NewAuditLog(db *sql.DB) *AuditLog {
+	return &AuditLog{db: db}
+}
+
+// Record сохраняет запись журнала
+func (l *AuditLog) Record(ctx context.Context, entry *audit.Entry) error {
+	query := `
+		INSERT INTO audit_log (id, actor, action, target, outcome, error, remote_addr, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := l.db.ExecContext(ctx, query,
+		entry.ID,
+		entry.Actor,
+		string(entry.Action),
+		entry.Target,
+		string(entry.Outcome),
+		entry.Error,
+		entry.RemoteAddr,
+		entry.CreatedAt,
+	)
+	return err
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// CatalogueStore реализует хранилище последнего каталога в PostgreSQL (одна JSONB строка)
+type CatalogueStore struct {
+	db *sql.DB
+}
+
+// NewCatalogueStore создает новый экземпляр CatalogueStore
+func NewCatalogueStore(db *sql.DB) *CatalogueStore {
+	return &CatalogueStore{db: db}
+}
+
+// Save заменяет сохраненный каталог
+func (s *CatalogueStore) Save(ctx context.Context, items []*item.Item, savedAt time.Time) error {
+	payload, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to encode catalogue: %w", err)
+	}
+
+	query := `
+		INSERT INTO catalogue_snapshot (id, items, item_count, saved_at)
+		VALUES (1, $1, $2, $3)
+		ON CONFLICT (id) DO UPDATE
+		SET items = EXCLUDED.items, item_count = EXCLUDED.item_count, saved_at = EXCLUDED.saved_at
+	`
+
+	_, err = s.db.ExecContext(ctx, query, payload, len(items), savedAt)
+	return err
+}
+
+// Load возвращает сохраненный каталог
+func (s *CatalogueStore) Load(ctx context.Context) (*item.Catalogue, error) {
+	query := `SELECT items, saved_at FROM catalogue_snapshot WHERE id = 1`
+
+	var (
+		payload []byte
+		savedAt time.Time
+	)
+	if err := s.db.QueryRowContext(ctx, query).Scan(&payload, &savedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, item.ErrCatalogueNotStored
+		}
+		return nil, err
+	}
+
+	var items []*item.Item
+	if err := json.Unmarshal(payload, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode catalogue: %w", err)
+	}
+
+	return &item.Catalogue{Items: items, UpdatedAt: savedAt.UTC()}, nil
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// PriceSnapshotRepository реализует репозиторий истории цен для PostgreSQL
+type PriceSnapshotRepository struct {
+	db        *sql.DB
+	batchSize int
+}
+
+// NewPriceSnapshotRepository создает новый экземпляр PriceSnapshotRepository
+func NewPriceSnapshotRepository(db *sql.DB, batchSize int) *PriceSnapshotRepository {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	return &PriceSnapshotRepository{db: db, batchSize: batchSize}
+}
+
+// SaveSnapshots сохраняет срезы цен пачками через COPY в одной транзакции
+func (r *PriceSnapshotRepository) SaveSnapshots(ctx context.Context, snapshots []*item.PriceSnapshot) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	for start := 0; start < len(snapshots); start += r.batchSize {
+		end := min(start+r.batchSize, len(snapshots))
+		if err = r.copyBatch(ctx, tx, snapshots[start:end]); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PriceSnapshotRepository) copyBatch(ctx context.Context, tx *sql.Tx, batch []*item.PriceSnapshot) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(
+		"price_snapshots",
+		"taken_at",
+		"market_hash_name",
+		"tradable_min_price",
+		"non_tradable_min_price",
+		"mean_price",
+		"max_price",
+		"quantity",
+	))
+	if err != nil {
+		return fmt.Errorf("failed to prepare copy: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, s := range batch {
+		_, err := stmt.ExecContext(
+			ctx,
+			s.TakenAt,
+			s.MarketHashName,
+			nullDecimal(s.TradableMinPrice),
+			nullDecimal(s.NonTradableMinPrice),
+			nullDecimal(s.MeanPrice),
+			nullDecimal(s.MaxPrice),
+			s.Quantity,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to copy snapshot: %w", err)
+		}
+	}
+
+	// Пустой Exec сбрасывает буфер COPY на сервер
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush copy: %w", err)
+	}
+
+	return nil
+}
+
+// GetCandles возвращает OHLC агрегаты цен предмета за период [from, to) с шагом interval
+func (r *PriceSnapshotRepository) GetCandles(
+	ctx context.Context,
+	marketHashName string,
+	from, to time.Time,
+	interval time.Duration,
+) ([]*item.PriceCandle, error) {
+	query := `
+		SELECT
+			date_bin(make_interval(secs => $4), taken_at, TIMESTAMPTZ 'epoch') AS bucket,
+			(array_agg(tradable_min_price ORDER BY taken_at) FILTER (WHERE tradable_min_price IS NOT NULL))[1],
+			MAX(tradable_min_price),
+			MIN(tradable_min_price),
+			(array_agg(tradable_min_price ORDER BY taken_at DESC) FILTER (WHERE tradable_min_price IS NOT NULL))[1],
+			MIN(non_tradable_min_price),
+			ROUND(AVG(mean_price), 4),
+			MAX(max_price),
+			(array_agg(quantity ORDER BY taken_at DESC))[1],
+			COUNT(*)
+		FROM price_snapshots
+		WHERE market_hash_name = $1 AND taken_at >= $2 AND taken_at < $3
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, marketHashName, from, to, interval.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candles []*item.PriceCandle
+
+	for rows.Next() {
+		var c item.PriceCandle
+		var open, high, low, closePrice, nonTradableLow, avgMean, maxPrice sql.NullString
+
+		err := rows.Scan(
+			&c.Time,
+			&open,
+			&high,
+			&low,
+			&closePrice,
+			&nonTradableLow,
+			&avgMean,
+			&maxPrice,
+			&c.Quantity,
+			&c.Samples,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Open = parseNullDecimal(open)
+		c.High = parseNullDecimal(high)
+		c.Low = parseNullDecimal(low)
+		c.Close = parseNullDecimal(closePrice)
+		c.NonTradableLow = parseNullDecimal(nonTradableLow)
+		c.AvgMeanPrice = parseNullDecimal(avgMean)
+		c.MaxPrice = parseNullDecimal(maxPrice)
+
+		candles = append(candles, &c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return candles, nil
+}
+
+// DeleteOlderThan удаляет срезы старше указанного момента
+func (r *PriceSnapshotRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM price_snapshots WHERE taken_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func nullDecimal(d *decimal.Decimal) interface{} {
+	if d == nil {
+		return nil
+	}
+	return d.String()
+}
+
+func parseNullDecimal(s sql.NullString) *decimal.Decimal {
+	if !s.Valid {
+		return nil
+	}
+	d, err := decimal.NewFromString(s.String)
+	if err != nil {
+		return nil
+	}
+	return &d
+}
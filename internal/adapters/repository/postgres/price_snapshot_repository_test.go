@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// testDB подключается к PostgreSQL из POSTGRES_TEST_DSN и создает таблицы миграции
+// migration во временной схеме. Без переменной тест пропускается.
+// Пул ограничен одним соединением, чтобы search_path действовал на все запросы теста.
+func testDB(t *testing.T, migration string) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA %s; SET search_path TO %s", schema, schema)); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = db.ExecContext(context.Background(), fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+	})
+
+	if _, err := db.ExecContext(ctx, gooseUp(t, migration)); err != nil {
+		t.Fatalf("apply migration %s: %v", migration, err)
+	}
+
+	return db
+}
+
+// gooseUp возвращает секцию Up файла миграции goose
+func gooseUp(t *testing.T, migration string) string {
+	t.Helper()
+
+	data, err := os.ReadFile("../../../../migrations/" + migration)
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+
+	up, _, _ := strings.Cut(string(data), "-- +goose Down")
+	return up
+}
+
+func price(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
+func countSnapshots(t *testing.T, db *sql.DB) int {
+	t.Helper()
+
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM price_snapshots").Scan(&n); err != nil {
+		t.Fatalf("count snapshots: %v", err)
+	}
+	return n
+}
+
+func TestPriceSnapshotRepository_SaveSnapshotsInBatches(t *testing.T) {
+	db := testDB(t, "004_create_price_snapshots_table.sql")
+	repo := NewPriceSnapshotRepository(db, 2)
+	ctx := context.Background()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var snapshots []*item.PriceSnapshot
+	for i := range 5 {
+		snapshots = append(snapshots, &item.PriceSnapshot{
+			TakenAt:          start.Add(time.Duration(i) * time.Minute),
+			MarketHashName:   "AK-47 | Redline",
+			TradableMinPrice: price("10.50"),
+			Quantity:         i,
+		})
+	}
+	// Срез без цен сохраняется с NULL
+	snapshots[4].TradableMinPrice = nil
+
+	// 5 срезов при размере пачки 2 — три COPY, включая неполную последнюю пачку
+	if err := repo.SaveSnapshots(ctx, snapshots); err != nil {
+		t.Fatalf("save snapshots: %v", err)
+	}
+	if n := countSnapshots(t, db); n != 5 {
+		t.Fatalf("expected 5 snapshots, got %d", n)
+	}
+
+	var nulls int
+	if err := db.QueryRow("SELECT COUNT(*) FROM price_snapshots WHERE tradable_min_price IS NULL").Scan(&nulls); err != nil {
+		t.Fatalf("count nulls: %v", err)
+	}
+	if nulls != 1 {
+		t.Errorf("expected 1 snapshot without price, got %d", nulls)
+	}
+
+	// Дубликат ключа в последней пачке откатывает и уже скопированные пачки
+	batch := []*item.PriceSnapshot{
+		{TakenAt: start.Add(time.Hour), MarketHashName: "AWP | Asiimov"},
+		{TakenAt: start.Add(time.Hour + time.Minute), MarketHashName: "AWP | Asiimov"},
+		{TakenAt: start, MarketHashName: "AK-47 | Redline"},
+	}
+	if err := repo.SaveSnapshots(ctx, batch); err == nil {
+		t.Fatal("expected duplicate snapshot to fail")
+	}
+	if n := countSnapshots(t, db); n != 5 {
+		t.Errorf("expected failed save to roll back all batches, got %d snapshots", n)
+	}
+}
+
+func TestPriceSnapshotRepository_GetCandles(t *testing.T) {
+	db := testDB(t, "004_create_price_snapshots_table.sql")
+	repo := NewPriceSnapshotRepository(db, 1000)
+	ctx := context.Background()
+
+	const name = "AK-47 | Redline"
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return from.Add(d) }
+
+	// Срезы записаны не по порядку времени: open и close должны определяться по taken_at
+	snapshots := []*item.PriceSnapshot{
+		{TakenAt: at(20 * time.Minute), MarketHashName: name, TradableMinPrice: price("12"), MeanPrice: price("11"), Quantity: 2},
+		{TakenAt: at(time.Hour - time.Second), MarketHashName: name, TradableMinPrice: price("9"), MeanPrice: price("10"), Quantity: 3},
+		{TakenAt: at(0), MarketHashName: name, TradableMinPrice: price("10"), MeanPrice: price("12"), Quantity: 1},
+		// Начало второго часа относится ко второй свече
+		{TakenAt: at(time.Hour), MarketHashName: name, TradableMinPrice: price("20"), MeanPrice: price("20"), Quantity: 5},
+		// Последний срез без цены: close берется из последнего среза с ценой, quantity — из последнего
+		{TakenAt: at(90 * time.Minute), MarketHashName: name, MeanPrice: price("21"), Quantity: 7},
+		// Границы периода: from включается, to — нет
+		{TakenAt: at(-time.Second), MarketHashName: name, TradableMinPrice: price("1"), Quantity: 100},
+		{TakenAt: at(2 * time.Hour), MarketHashName: name, TradableMinPrice: price("1"), Quantity: 100},
+		// Другой предмет не попадает в свечи
+		{TakenAt: at(10 * time.Minute), MarketHashName: "AWP | Asiimov", TradableMinPrice: price("1"), Quantity: 100},
+	}
+	if err := repo.SaveSnapshots(ctx, snapshots); err != nil {
+		t.Fatalf("save snapshots: %v", err)
+	}
+
+	candles, err := repo.GetCandles(ctx, name, from, at(2*time.Hour), time.Hour)
+	if err != nil {
+		t.Fatalf("get candles: %v", err)
+	}
+	if len(candles) != 2 {
+		t.Fatalf("expected 2 candles, got %d", len(candles))
+	}
+
+	tests := []struct {
+		time                   time.Time
+		open, high, low, close string
+		avgMean                string
+		quantity, samples      int
+	}{
+		{time: at(0), open: "10", high: "12", low: "9", close: "9", avgMean: "11", quantity: 3, samples: 3},
+		{time: at(time.Hour), open: "20", high: "20", low: "20", close: "20", avgMean: "20.5", quantity: 7, samples: 2},
+	}
+
+	for i, tt := range tests {
+		c := candles[i]
+		if !c.Time.Equal(tt.time) {
+			t.Errorf("candle %d: expected time %v, got %v", i, tt.time, c.Time)
+		}
+		for _, f := range []struct {
+			field string
+			got   *decimal.Decimal
+			want  string
+		}{
+			{"open", c.Open, tt.open},
+			{"high", c.High, tt.high},
+			{"low", c.Low, tt.low},
+			{"close", c.Close, tt.close},
+			{"avg mean", c.AvgMeanPrice, tt.avgMean},
+		} {
+			if f.got == nil || !f.got.Equal(decimal.RequireFromString(f.want)) {
+				t.Errorf("candle %d: expected %s %s, got %v", i, f.field, f.want, f.got)
+			}
+		}
+		if c.Quantity != tt.quantity {
+			t.Errorf("candle %d: expected quantity %d, got %d", i, tt.quantity, c.Quantity)
+		}
+		if c.Samples != tt.samples {
+			t.Errorf("candle %d: expected %d samples, got %d", i, tt.samples, c.Samples)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/akonovalovdev/DDD_example/internal/pkg/tracing"
+)
+
+// startQuerySpan открывает клиентский span запроса к PostgreSQL. Текст запроса
+// попадает в трассу без значений параметров.
+func startQuerySpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", query),
+		),
+	)
+}
+
+// endQuerySpan завершает span запроса, отмечая ошибку
+func endQuerySpan(span trace.Span, err error) {
+	tracing.RecordError(span, err)
+	span.End()
+}
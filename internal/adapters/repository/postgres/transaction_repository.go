@@ -20,13 +20,16 @@ func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 }
 
 // Save сохраняет транзакцию
-func (r *TransactionRepository) Save(ctx context.Context, tx *sql.Tx, t *transaction.Transaction) error {
+func (r *TransactionRepository) Save(ctx context.Context, tx *sql.Tx, t *transaction.Transaction) (err error) {
 	query := `
 		INSERT INTO transactions (id, user_id, amount, balance_before, balance_after, description, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err := tx.ExecContext(
+	ctx, span := startQuerySpan(ctx, "TransactionRepository.Save", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	_, err = tx.ExecContext(
 		ctx,
 		query,
 		t.ID,
@@ -42,7 +45,7 @@ func (r *TransactionRepository) Save(ctx context.Context, tx *sql.Tx, t *transac
 }
 
 // GetByUserID возвращает список транзакций пользователя
-func (r *TransactionRepository) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*transaction.Transaction, error) {
+func (r *TransactionRepository) GetByUserID(ctx context.Context, userID int64, limit, offset int) (_ []*transaction.Transaction, err error) {
 	query := `
 		SELECT id, user_id, amount, balance_before, balance_after, description, created_at
 		FROM transactions
@@ -51,6 +54,9 @@ func (r *TransactionRepository) GetByUserID(ctx context.Context, userID int64, l
 		LIMIT $2 OFFSET $3
 	`
 
+	ctx, span := startQuerySpan(ctx, "TransactionRepository.GetByUserID", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, err
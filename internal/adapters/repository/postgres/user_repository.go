@@ -21,13 +21,16 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 }
 
 // GetByID возвращает пользователя по ID
-func (r *UserRepository) GetByID(ctx context.Context, id int64) (*user.User, error) {
-	query := `SELECT id, balance FROM users WHERE id = $1`
+func (r *UserRepository) GetByID(ctx context.Context, id int64) (_ *user.User, err error) {
+	query := `SELECT id, balance, frozen FROM users WHERE id = $1`
+
+	ctx, span := startQuerySpan(ctx, "UserRepository.GetByID", query)
+	defer func() { endQuerySpan(span, err) }()
 
 	var u user.User
 	var balance string
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&u.ID, &balance)
+	err = r.db.QueryRowContext(ctx, query, id).Scan(&u.ID, &balance, &u.Frozen)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, user.ErrUserNotFound
@@ -40,13 +43,16 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*user.User, err
 }
 
 // GetByIDForUpdate возвращает пользователя по ID с блокировкой для обновления
-func (r *UserRepository) GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*user.User, error) {
-	query := `SELECT id, balance FROM users WHERE id = $1 FOR UPDATE`
+func (r *UserRepository) GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (_ *user.User, err error) {
+	query := `SELECT id, balance, frozen FROM users WHERE id = $1 FOR UPDATE`
+
+	ctx, span := startQuerySpan(ctx, "UserRepository.GetByIDForUpdate", query)
+	defer func() { endQuerySpan(span, err) }()
 
 	var u user.User
 	var balance string
 
-	err := tx.QueryRowContext(ctx, query, id).Scan(&u.ID, &balance)
+	err = tx.QueryRowContext(ctx, query, id).Scan(&u.ID, &balance, &u.Frozen)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, user.ErrUserNotFound
@@ -59,9 +65,12 @@ func (r *UserRepository) GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id in
 }
 
 // UpdateBalance обновляет баланс пользователя
-func (r *UserRepository) UpdateBalance(ctx context.Context, tx *sql.Tx, id int64, balance decimal.Decimal) error {
+func (r *UserRepository) UpdateBalance(ctx context.Context, tx *sql.Tx, id int64, balance decimal.Decimal) (err error) {
 	query := `UPDATE users SET balance = $1 WHERE id = $2`
 
+	ctx, span := startQuerySpan(ctx, "UserRepository.UpdateBalance", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	result, err := tx.ExecContext(ctx, query, balance.String(), id)
 	if err != nil {
 		return err
@@ -79,8 +88,35 @@ func (r *UserRepository) UpdateBalance(ctx context.Context, tx *sql.Tx, id int64
 	return nil
 }
 
+// SetFrozen замораживает или размораживает счет пользователя
+func (r *UserRepository) SetFrozen(ctx context.Context, id int64, frozen bool) (err error) {
+	query := `UPDATE users SET frozen = $1, updated_at = NOW() WHERE id = $2`
+
+	ctx, span := startQuerySpan(ctx, "UserRepository.SetFrozen", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	result, err := r.db.ExecContext(ctx, query, frozen, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
+
 // BeginTx начинает транзакцию
-func (r *UserRepository) BeginTx(ctx context.Context) (*sql.Tx, error) {
+func (r *UserRepository) BeginTx(ctx context.Context) (_ *sql.Tx, err error) {
+	_, span := startQuerySpan(ctx, "UserRepository.BeginTx", "BEGIN ISOLATION LEVEL SERIALIZABLE")
+	defer func() { endQuerySpan(span, err) }()
+
 	return r.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelSerializable,
 	})
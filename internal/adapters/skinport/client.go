@@ -2,203 +2,201 @@ package skinport
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
-	"github.com/andybalholm/brotli"
-	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/breaker"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/tracing"
 )
 
 // SkinportItem представляет предмет из API Skinport
 type SkinportItem struct {
-MarketHashName string   `json:"market_hash_name"`
-Currency       string   `json:"currency"`
-SuggestedPrice *float64 `json:"suggested_price"`
-ItemPage       string   `json:"item_page"`
-MarketPage     string   `json:"market_page"`
-MinPrice       *float64 `json:"min_price"`
-MaxPrice       *float64 `json:"max_price"`
-MeanPrice      *float64 `json:"mean_price"`
-Quantity       int      `json:"quantity"`
-CreatedAt      int64    `json:"created_at"`
-UpdatedAt      int64    `json:"updated_at"`
+	MarketHashName string `json:"market_hash_name"`
+	Currency       string `json:"currency"`
+	SuggestedPrice *Price `json:"suggested_price"`
+	ItemPage       string `json:"item_page"`
+	MarketPage     string `json:"market_page"`
+	MinPrice       *Price `json:"min_price"`
+	MaxPrice       *Price `json:"max_price"`
+	MeanPrice      *Price `json:"mean_price"`
+	Quantity       int    `json:"quantity"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
 }
 
+// RequestObserver получает результат каждого запроса к API: endpoint — путь без
+// параметров запроса, err — ошибка запроса, статуса или разбора ответа
+type RequestObserver func(endpoint string, duration time.Duration, err error)
+
 // Client реализует клиент для Skinport API
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	observer   RequestObserver
+	breaker    *breaker.Breaker
 }
 
 // NewClient создает новый клиент Skinport API
 func NewClient(baseURL string, timeout time.Duration) *Client {
+	return NewClientWithTransport(baseURL, timeout, nil)
+}
+
+// NewClientWithTransport создает клиент Skinport API с собственным HTTP транспортом,
+// например RecordingTransport для записи фикстур. transport == nil — транспорт по умолчанию.
+func NewClientWithTransport(baseURL string, timeout time.Duration, transport http.RoundTripper) *Client {
 	return &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: transport,
 		},
 	}
 }
 
+// SetObserver задает наблюдателя запросов, например для метрик. Вызывается при инициализации.
+func (c *Client) SetObserver(observer RequestObserver) {
+	c.observer = observer
+}
+
+// SetBreaker включает автомат защиты: после серии отказов Skinport запросы не отправляются
+// до истечения паузы и сразу завершаются ошибкой. Вызывается при инициализации.
+func (c *Client) SetBreaker(b *breaker.Breaker) {
+	c.breaker = b
+}
+
+// guarded выполняет обращение к API через автомат защиты. Загрузка каталога из двух
+// запросов считается одним обращением.
+func (c *Client) guarded(ctx context.Context, call func() error) error {
+	if c.breaker == nil {
+		return call()
+	}
+	if err := c.breaker.Allow(); err != nil {
+		return fmt.Errorf("skinport api unavailable: %w", err)
+	}
+
+	err := call()
+	if err != nil && ctx.Err() != nil {
+		// Запрос отменил вызывающий — это не отказ Skinport
+		c.breaker.Cancel()
+		return err
+	}
+	c.breaker.Record(err)
+	return err
+}
+
 // FetchItems получает список предметов из Skinport API
-func (c *Client) FetchItems(ctx context.Context) ([]*item.Item, error) {
-	// Делаем два запроса параллельно: tradable и non-tradable
-	tradableCh := make(chan fetchResult)
-	nonTradableCh := make(chan fetchResult)
-
-	go func() {
-		items, err := c.fetchItems(ctx, true)
-		tradableCh <- fetchResult{items: items, err: err}
+func (c *Client) FetchItems(ctx context.Context) (_ []*item.Item, err error) {
+	ctx, span := tracing.Start(ctx, "skinport.FetchItems")
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
 	}()
 
-	go func() {
-		items, err := c.fetchItems(ctx, false)
-		nonTradableCh <- fetchResult{items: items, err: err}
-	}()
+	// Делаем два запроса параллельно: tradable и non-tradable.
+	// Оба ответа читаются потоково прямо в общий индекс, без промежуточных копий каталога.
+	index := newItemIndex()
 
-	tradableResult := <-tradableCh
-	nonTradableResult := <-nonTradableCh
+	err = c.guarded(ctx, func() error {
+		g, gctx := errgroup.WithContext(ctx)
 
-	if tradableResult.err != nil {
-		return nil, fmt.Errorf("failed to fetch tradable items: %w", tradableResult.err)
-	}
-	if nonTradableResult.err != nil {
-		return nil, fmt.Errorf("failed to fetch non-tradable items: %w", nonTradableResult.err)
+		g.Go(func() error {
+			if err := c.fetchItems(gctx, true, index); err != nil {
+				return fmt.Errorf("failed to fetch tradable items: %w", err)
+			}
+			return nil
+		})
+
+		g.Go(func() error {
+			if err := c.fetchItems(gctx, false, index); err != nil {
+				return fmt.Errorf("failed to fetch non-tradable items: %w", err)
+			}
+			return nil
+		})
+
+		return g.Wait()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", item.ErrFetchFailed, err)
 	}
 
-	// Объединяем результаты
-	return mergeItems(tradableResult.items, nonTradableResult.items), nil
+	items := index.list()
+	span.SetAttributes(attribute.Int("items.count", len(items)))
+	return items, nil
 }
 
-type fetchResult struct {
-	items map[string]*SkinportItem
-	err   error
+func (c *Client) fetchItems(ctx context.Context, tradable bool, index *itemIndex) error {
+	path := fmt.Sprintf("/items?app_id=730&currency=USD&tradable=%t", tradable)
+
+	return c.get(ctx, path, func(body io.Reader) error {
+		return decodeItems(body, tradable, index)
+	})
 }
 
-func (c *Client) fetchItems(ctx context.Context, tradable bool) (map[string]*SkinportItem, error) {
-	url := fmt.Sprintf("%s/items?app_id=730&currency=USD&tradable=%t", c.baseURL, tradable)
+// get выполняет GET запрос к API и передает распакованное тело ответа в decode
+func (c *Client) get(ctx context.Context, path string, decode func(body io.Reader) error) error {
+	if c.observer == nil {
+		return c.do(ctx, path, decode)
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	start := time.Now()
+	err := c.do(ctx, path, decode)
+	endpoint, _, _ := strings.Cut(path, "?")
+	c.observer(endpoint, time.Since(start), err)
+	return err
+}
+
+func (c *Client) do(ctx context.Context, path string, decode func(body io.Reader) error) (err error) {
+	endpoint, _, _ := strings.Cut(path, "?")
+	ctx, span := tracing.Start(ctx, "GET "+endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", http.MethodGet),
+			attribute.String("url.full", c.baseURL+path),
+		),
+	)
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Skinport API требует поддержку Brotli компрессии
-	req.Header.Set("Accept-Encoding", "br")
+	// Skinport API требует поддержку Brotli компрессии, gzip и deflate принимаем на случай прокси
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	tracing.Inject(ctx, req.Header)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	// Декомпрессия Brotli если сервер вернул сжатый ответ
-	var reader io.Reader = resp.Body
-	if resp.Header.Get("Content-Encoding") == "br" {
-		reader = brotli.NewReader(resp.Body)
-	}
-
-	var items []SkinportItem
-	if err := json.NewDecoder(reader).Decode(&items); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
-	// Преобразуем в map для быстрого поиска
-	result := make(map[string]*SkinportItem, len(items))
-	for i := range items {
-		result[items[i].MarketHashName] = &items[i]
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	return result, nil
-}
-
-func mergeItems(tradable, nonTradable map[string]*SkinportItem) []*item.Item {
-	// Собираем все уникальные имена предметов
-	allNames := make(map[string]struct{})
-	for name := range tradable {
-		allNames[name] = struct{}{}
-	}
-	for name := range nonTradable {
-		allNames[name] = struct{}{}
+	body, err := decompressBody(resp.Body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return err
 	}
+	defer body.Close()
 
-	result := make([]*item.Item, 0, len(allNames))
-
-	for name := range allNames {
-		var tradablePrice, nonTradablePrice *decimal.Decimal
-		var suggestedPrice, maxPrice, meanPrice *decimal.Decimal
-		var currency, itemPage, marketPage string
-		var quantity int
-		var createdAt, updatedAt int64
-
-		// Берём данные из tradable
-		if t, ok := tradable[name]; ok {
-			currency = t.Currency
-			itemPage = t.ItemPage
-			marketPage = t.MarketPage
-			quantity = t.Quantity
-			createdAt = t.CreatedAt
-			updatedAt = t.UpdatedAt
-
-			if t.MinPrice != nil {
-				p := decimal.NewFromFloat(*t.MinPrice)
-				tradablePrice = &p
-			}
-			if t.SuggestedPrice != nil {
-				p := decimal.NewFromFloat(*t.SuggestedPrice)
-				suggestedPrice = &p
-			}
-			if t.MaxPrice != nil {
-				p := decimal.NewFromFloat(*t.MaxPrice)
-				maxPrice = &p
-			}
-			if t.MeanPrice != nil {
-				p := decimal.NewFromFloat(*t.MeanPrice)
-				meanPrice = &p
-			}
-		}
-
-		// Берём non-tradable цену
-		if nt, ok := nonTradable[name]; ok {
-			if nt.MinPrice != nil {
-				p := decimal.NewFromFloat(*nt.MinPrice)
-				nonTradablePrice = &p
-			}
-			// Заполняем остальные поля если не были заполнены из tradable
-			if currency == "" {
-				currency = nt.Currency
-				itemPage = nt.ItemPage
-				marketPage = nt.MarketPage
-				quantity = nt.Quantity
-				createdAt = nt.CreatedAt
-				updatedAt = nt.UpdatedAt
-			}
-		}
-
-		result = append(result, &item.Item{
-			MarketHashName:      name,
-			Currency:            currency,
-			SuggestedPrice:      suggestedPrice,
-			ItemPage:            itemPage,
-			MarketPage:          marketPage,
-			TradableMinPrice:    tradablePrice,
-			NonTradableMinPrice: nonTradablePrice,
-			MaxPrice:            maxPrice,
-			MeanPrice:           meanPrice,
-			Quantity:            quantity,
-			CreatedAt:           createdAt,
-			UpdatedAt:           updatedAt,
-		})
+	if err := decode(body); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return result
+	return nil
 }
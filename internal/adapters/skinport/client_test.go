@@ -0,0 +1,388 @@
+package skinport
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/breaker"
+)
+
+const (
+	tradableFixture = `[
+		{"market_hash_name": "AK-47 | Redline (Field-Tested)", "currency": "USD", "suggested_price": 15.23,
+		 "item_page": "https://skinport.com/item/ak", "market_page": "https://skinport.com/market/ak",
+		 "min_price": 12.5, "max_price": 25, "mean_price": 18.75, "quantity": 150,
+		 "created_at": 1609459200, "updated_at": 1609459300},
+		{"market_hash_name": "AWP | Asiimov (Field-Tested)", "currency": "USD", "min_price": 0.1, "quantity": 1}
+	]`
+	nonTradableFixture = `[
+		{"market_hash_name": "AK-47 | Redline (Field-Tested)", "currency": "USD", "min_price": 10.2, "quantity": 7},
+		{"market_hash_name": "M4A4 | Howl (Factory New)", "currency": "EUR", "min_price": 1234567.89, "quantity": 2}
+	]`
+)
+
+func compress(t testing.TB, encoding string, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "br":
+		w = brotli.NewWriter(&buf)
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		if err != nil {
+			t.Fatalf("failed to create flate writer: %v", err)
+		}
+		w = fw
+	default:
+		return data
+	}
+
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close compressor: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newSkinportServer(t *testing.T, encoding string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := nonTradableFixture
+		if r.URL.Query().Get("tradable") == "true" {
+			body = tradableFixture
+		}
+
+		if encoding != "" {
+			header := encoding
+			if encoding == "raw-deflate" {
+				header = "deflate"
+			}
+			w.Header().Set("Content-Encoding", header)
+		}
+		w.Write(compress(t, encoding, []byte(body))) //nolint:errcheck // test server
+	}))
+}
+
+func TestClient_FetchItems_Encodings(t *testing.T) {
+	for _, encoding := range []string{"", "br", "gzip", "deflate", "raw-deflate"} {
+		t.Run("encoding="+encoding, func(t *testing.T) {
+			server := newSkinportServer(t, encoding)
+			defer server.Close()
+
+			client := NewClient(server.URL, 5*time.Second)
+
+			items, err := client.FetchItems(context.Background())
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			byName := make(map[string]*item.Item, len(items))
+			for _, it := range items {
+				byName[it.MarketHashName] = it
+			}
+
+			if len(byName) != 3 {
+				t.Fatalf("expected 3 merged items, got %d", len(byName))
+			}
+
+			// Порядок не зависит от обхода map: одинаковая выдача дает одинаковый каталог
+			if !slices.IsSortedFunc(items, func(a, b *item.Item) int { return strings.Compare(a.MarketHashName, b.MarketHashName) }) {
+				t.Errorf("expected items sorted by market hash name, got %v", marketHashNames(items))
+			}
+
+			ak := byName["AK-47 | Redline (Field-Tested)"]
+			if ak.TradableMinPrice.String() != "12.5" || ak.NonTradableMinPrice.String() != "10.2" {
+				t.Errorf("unexpected AK prices: tradable %v, non-tradable %v", ak.TradableMinPrice, ak.NonTradableMinPrice)
+			}
+			if ak.Quantity != 150 || ak.SuggestedPrice.String() != "15.23" {
+				t.Errorf("expected tradable metadata to win, got quantity %d, suggested %v", ak.Quantity, ak.SuggestedPrice)
+			}
+
+			howl := byName["M4A4 | Howl (Factory New)"]
+			if howl.TradableMinPrice != nil {
+				t.Errorf("expected nil tradable price, got %s", howl.TradableMinPrice.String())
+			}
+			if howl.Currency != "EUR" || howl.NonTradableMinPrice.String() != "1234567.89" {
+				t.Errorf("unexpected non-tradable only item: %+v", howl)
+			}
+		})
+	}
+}
+
+func TestClient_FetchItems_UnsupportedEncoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "zstd")
+		w.Write([]byte("[]")) //nolint:errcheck // test server
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+
+	if _, err := client.FetchItems(context.Background()); err == nil {
+		t.Fatal("expected error for unsupported encoding, got nil")
+	}
+}
+
+func TestClient_FetchItems_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+
+	var mu sync.Mutex
+	observed := make(map[string]error)
+	client.SetObserver(func(endpoint string, _ time.Duration, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		observed[endpoint] = err
+	})
+
+	if _, err := client.FetchItems(context.Background()); err == nil {
+		t.Fatal("expected error for non-200 status, got nil")
+	}
+
+	// Оба параллельных запроса (tradable и non-tradable) наблюдаются как /items, без параметров
+	mu.Lock()
+	defer mu.Unlock()
+	if len(observed) != 1 {
+		t.Fatalf("expected observations for /items only, got %v", observed)
+	}
+	if err := observed["/items"]; err == nil {
+		t.Error("expected observed error for /items")
+	}
+}
+
+func TestClient_BreakerStopsRequests(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+	cb := breaker.NewBreaker(2, time.Hour)
+	client.SetBreaker(cb)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.FetchItems(context.Background()); err == nil {
+			t.Fatal("expected error for 502")
+		}
+	}
+	if cb.State() != breaker.StateOpen {
+		t.Fatalf("expected open breaker after 2 failed loads, got %s", cb.State())
+	}
+
+	mu.Lock()
+	sent := requests
+	mu.Unlock()
+
+	_, err := client.FetchItems(context.Background())
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("expected ErrOpen, got %v", err)
+	}
+	if _, err := client.FetchSalesHistory(context.Background()); !errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("expected ErrOpen for sales history, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != sent {
+		t.Errorf("open breaker must not send requests, got %d more", requests-sent)
+	}
+}
+
+func TestDecodeItems_NotAnArray(t *testing.T) {
+	err := decodeItems(strings.NewReader(`{"errors": []}`), true, newItemIndex())
+	if err == nil {
+		t.Fatal("expected error for non-array payload, got nil")
+	}
+}
+
+// generateCatalogue генерирует JSON каталога размером с реальный ответ Skinport
+func generateCatalogue(b *testing.B, n int) []byte {
+	b.Helper()
+
+	items := make([]map[string]interface{}, n)
+	for i := range items {
+		items[i] = map[string]interface{}{
+			"market_hash_name": fmt.Sprintf("Sticker | Item #%d (Holo)", i),
+			"currency":         "USD",
+			"suggested_price":  json.Number(fmt.Sprintf("%d.%02d", i%5000, i%100)),
+			"item_page":        fmt.Sprintf("https://skinport.com/item/sticker-item-%d-holo", i),
+			"market_page":      fmt.Sprintf("https://skinport.com/market?item=Sticker%%20Item%%20%d", i),
+			"min_price":        json.Number(fmt.Sprintf("%d.%02d", i%4000, i%97)),
+			"max_price":        json.Number(fmt.Sprintf("%d.%02d", i%9000, i%89)),
+			"mean_price":       json.Number(fmt.Sprintf("%d.%02d", i%6000, i%83)),
+			"quantity":         i % 300,
+			"created_at":       1535988253,
+			"updated_at":       1568073728,
+		}
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		b.Fatalf("failed to marshal catalogue: %v", err)
+	}
+	return data
+}
+
+// decodeItemsBuffered воспроизводит прежний путь: весь массив в слайс, затем копия в map
+func decodeItemsBuffered(r io.Reader) (map[string]*SkinportItem, error) {
+	var items []SkinportItem
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*SkinportItem, len(items))
+	for i := range items {
+		result[items[i].MarketHashName] = &items[i]
+	}
+	return result, nil
+}
+
+// peakHeap приблизительно измеряет пиковый размер живой кучи во время декодирования.
+// GC переводится в агрессивный режим, чтобы объем кучи был близок к живым данным,
+// а фоновая горутина периодически снимает показания runtime/metrics.
+func peakHeap(decode func() interface{}) uint64 {
+	const heapMetric = "/memory/classes/heap/objects:bytes"
+
+	defer debug.SetGCPercent(debug.SetGCPercent(1))
+
+	sample := []metrics.Sample{{Name: heapMetric}}
+	read := func() uint64 {
+		metrics.Read(sample)
+		return sample[0].Value.Uint64()
+	}
+
+	runtime.GC()
+	baseline := read()
+
+	var peak uint64
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(100 * time.Microsecond)
+		defer ticker.Stop()
+		for {
+			if v := read(); v > peak {
+				peak = v
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	result := decode()
+	close(done)
+	<-stopped
+	runtime.KeepAlive(result)
+
+	if peak < baseline {
+		return 0
+	}
+	return peak - baseline
+}
+
+const benchCatalogueSize = 20000
+
+func BenchmarkDecodeCatalogue_Buffered(b *testing.B) {
+	tradable := compress(b, "br", generateCatalogue(b, benchCatalogueSize))
+	nonTradable := compress(b, "br", generateCatalogue(b, benchCatalogueSize))
+
+	decode := func() interface{} {
+		t, err := decodeItemsBuffered(brotli.NewReader(bytes.NewReader(tradable)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		nt, err := decodeItemsBuffered(brotli.NewReader(bytes.NewReader(nonTradable)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		return mergeItems(t, nt)
+	}
+
+	peak := peakHeap(decode)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		decode()
+	}
+
+	b.ReportMetric(float64(peak), "peak-heap-B")
+}
+
+func BenchmarkDecodeCatalogue_Streaming(b *testing.B) {
+	tradable := compress(b, "br", generateCatalogue(b, benchCatalogueSize))
+	nonTradable := compress(b, "br", generateCatalogue(b, benchCatalogueSize))
+
+	decode := func() interface{} {
+		index := newItemIndex()
+		if err := decodeItems(brotli.NewReader(bytes.NewReader(tradable)), true, index); err != nil {
+			b.Fatal(err)
+		}
+		if err := decodeItems(brotli.NewReader(bytes.NewReader(nonTradable)), false, index); err != nil {
+			b.Fatal(err)
+		}
+		return index.list()
+	}
+
+	peak := peakHeap(decode)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		decode()
+	}
+
+	b.ReportMetric(float64(peak), "peak-heap-B")
+}
+
+func marketHashNames(items []*item.Item) []string {
+	names := make([]string, len(items))
+	for i, it := range items {
+		names[i] = it.MarketHashName
+	}
+	return names
+}
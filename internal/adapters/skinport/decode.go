@@ -0,0 +1,172 @@
+package skinport
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// acceptEncoding список поддерживаемых алгоритмов сжатия ответа
+const acceptEncoding = "br, gzip, deflate"
+
+// decompressBody оборачивает тело ответа в декомпрессор согласно Content-Encoding
+func decompressBody(body io.Reader, contentEncoding string) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "", "identity":
+		return io.NopCloser(body), nil
+	case "br":
+		return io.NopCloser(brotli.NewReader(body)), nil
+	case "gzip", "x-gzip":
+		r, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return r, nil
+	case "deflate":
+		return newDeflateReader(body)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", contentEncoding)
+	}
+}
+
+// newDeflateReader читает deflate в zlib обертке (как требует RFC 9110),
+// но также принимает "сырой" deflate, который отдают некоторые серверы
+func newDeflateReader(body io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(body)
+
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deflate header: %w", err)
+	}
+
+	if isZlibHeader(header) {
+		r, err := zlib.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zlib reader: %w", err)
+		}
+		return r, nil
+	}
+
+	return flate.NewReader(br), nil
+}
+
+// isZlibHeader проверяет заголовок zlib потока (RFC 1950)
+func isZlibHeader(h []byte) bool {
+	cmf, flg := h[0], h[1]
+	return cmf&0x0f == 8 && (uint16(cmf)<<8|uint16(flg))%31 == 0
+}
+
+// decodeItems потоково читает JSON массив предметов и добавляет каждый в индекс.
+// В памяти одновременно находится только один SkinportItem, а не весь ответ.
+func decodeItems(r io.Reader, tradable bool, index *itemIndex) error {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected JSON array, got %v", tok)
+	}
+
+	for dec.More() {
+		// Новая переменная на каждой итерации: Decode не обнуляет отсутствующие поля
+		var si SkinportItem
+		if err := dec.Decode(&si); err != nil {
+			return err
+		}
+		index.add(&si, tradable)
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// itemIndex собирает объединенный каталог по мере чтения ответов Skinport
+type itemIndex struct {
+	mu    sync.Mutex
+	items map[string]*item.Item
+}
+
+func newItemIndex() *itemIndex {
+	return &itemIndex{items: make(map[string]*item.Item)}
+}
+
+// add объединяет предмет с уже накопленными данными.
+// Данные из tradable выдачи приоритетнее: они перезаписывают общие поля,
+// non-tradable выдача заполняет их только если предмет еще не встречался.
+func (idx *itemIndex) add(si *SkinportItem, tradable bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	it, ok := idx.items[si.MarketHashName]
+	if !ok {
+		it = &item.Item{MarketHashName: si.MarketHashName}
+		idx.items[si.MarketHashName] = it
+	}
+
+	if tradable {
+		it.TradableMinPrice = si.MinPrice.toDecimal()
+		it.SuggestedPrice = si.SuggestedPrice.toDecimal()
+		it.MaxPrice = si.MaxPrice.toDecimal()
+		it.MeanPrice = si.MeanPrice.toDecimal()
+		fillItemInfo(it, si)
+		return
+	}
+
+	it.NonTradableMinPrice = si.MinPrice.toDecimal()
+	if it.Currency == "" {
+		fillItemInfo(it, si)
+	}
+}
+
+// list возвращает накопленные предметы, упорядоченные по MarketHashName: одинаковая
+// выдача дает одинаковый каталог, а с ним — тот же ETag
+func (idx *itemIndex) list() []*item.Item {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	result := make([]*item.Item, 0, len(idx.items))
+	for _, it := range idx.items {
+		result = append(result, it)
+	}
+	slices.SortFunc(result, func(a, b *item.Item) int {
+		return strings.Compare(a.MarketHashName, b.MarketHashName)
+	})
+	return result
+}
+
+func fillItemInfo(it *item.Item, si *SkinportItem) {
+	it.Currency = si.Currency
+	it.ItemPage = si.ItemPage
+	it.MarketPage = si.MarketPage
+	it.Quantity = si.Quantity
+	it.CreatedAt = si.CreatedAt
+	it.UpdatedAt = si.UpdatedAt
+}
+
+// mergeItems объединяет уже декодированные tradable и non-tradable выдачи
+func mergeItems(tradable, nonTradable map[string]*SkinportItem) []*item.Item {
+	index := newItemIndex()
+	for _, si := range tradable {
+		index.add(si, true)
+	}
+	for _, si := range nonTradable {
+		index.add(si, false)
+	}
+	return index.list()
+}
@@ -0,0 +1,241 @@
+package skinport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// Имена файлов фикстур (без расширения)
+const (
+	fixtureItemsTradable    = "items_tradable"
+	fixtureItemsNonTradable = "items_non_tradable"
+	fixtureSalesHistory     = "sales_history"
+)
+
+// fixtureExtensions расширения файлов фикстур и соответствующие им Content-Encoding
+var fixtureExtensions = []struct {
+	ext      string
+	encoding string
+}{
+	{".json", ""},
+	{".json.br", "br"},
+	{".json.gz", "gzip"},
+	{".json.deflate", "deflate"},
+}
+
+// ErrFixtureNotFound возвращается когда в каталоге нет файла фикстуры
+var ErrFixtureNotFound = errors.New("fixture not found")
+
+// FixtureFetcher отдает предметы и историю продаж из записанных ответов Skinport.
+// Позволяет запускать сервис и тесты без доступа к сети.
+type FixtureFetcher struct {
+	dir string
+}
+
+// NewFixtureFetcher создает источник данных, читающий фикстуры из каталога dir
+func NewFixtureFetcher(dir string) *FixtureFetcher {
+	return &FixtureFetcher{dir: dir}
+}
+
+// FetchItems читает каталог из фикстур tradable и non-tradable предметов
+func (f *FixtureFetcher) FetchItems(_ context.Context) ([]*item.Item, error) {
+	index := newItemIndex()
+
+	if err := f.read(fixtureItemsTradable, func(r io.Reader) error {
+		return decodeItems(r, true, index)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read tradable items fixture: %w", err)
+	}
+
+	if err := f.read(fixtureItemsNonTradable, func(r io.Reader) error {
+		return decodeItems(r, false, index)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read non-tradable items fixture: %w", err)
+	}
+
+	return index.list(), nil
+}
+
+// FetchSalesHistory читает историю продаж из фикстуры
+func (f *FixtureFetcher) FetchSalesHistory(_ context.Context) ([]*item.SalesHistory, error) {
+	var result []*item.SalesHistory
+
+	err := f.read(fixtureSalesHistory, func(r io.Reader) error {
+		var err error
+		result, err = decodeSalesHistory(r)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sales history fixture: %w", err)
+	}
+
+	return result, nil
+}
+
+// read находит файл фикстуры с любым поддерживаемым расширением и передает распакованное содержимое в decode
+func (f *FixtureFetcher) read(name string, decode func(io.Reader) error) error {
+	for _, candidate := range fixtureExtensions {
+		file, err := os.Open(filepath.Join(f.dir, name+candidate.ext))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		body, err := decompressBody(file, candidate.encoding)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+
+		return decode(body)
+	}
+
+	return fmt.Errorf("%w: %s in %s", ErrFixtureNotFound, name, f.dir)
+}
+
+// fixtureName определяет имя фикстуры по запросу к API; пустая строка — запрос не записывается
+func fixtureName(r *http.Request) string {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/items"):
+		if r.URL.Query().Get("tradable") == "true" {
+			return fixtureItemsTradable
+		}
+		return fixtureItemsNonTradable
+	case strings.HasSuffix(r.URL.Path, "/sales/history"):
+		return fixtureSalesHistory
+	default:
+		return ""
+	}
+}
+
+// RecordingTransport сохраняет успешные ответы Skinport в каталог фикстур как есть (в исходном сжатии),
+// чтобы FixtureFetcher мог потом воспроизвести их без сети
+type RecordingTransport struct {
+	dir  string
+	base http.RoundTripper
+}
+
+// NewRecordingTransport создает транспорт, записывающий ответы в dir. base == nil — http.DefaultTransport.
+func NewRecordingTransport(dir string, base http.RoundTripper) *RecordingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RecordingTransport{dir: dir, base: base}
+}
+
+// RoundTrip выполняет запрос и подменяет тело ответа на записывающее
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	name := fixtureName(req)
+	if name == "" {
+		return resp, nil
+	}
+
+	ext, ok := extensionFor(resp.Header.Get("Content-Encoding"))
+	if !ok {
+		return resp, nil
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fixtures directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(t.dir, name+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fixture file: %w", err)
+	}
+
+	resp.Body = &recordingBody{
+		body: resp.Body,
+		tmp:  tmp,
+		dir:  t.dir,
+		name: name,
+		ext:  ext,
+	}
+	return resp, nil
+}
+
+func extensionFor(contentEncoding string) (string, bool) {
+	encoding := strings.ToLower(strings.TrimSpace(contentEncoding))
+	if encoding == "identity" {
+		encoding = ""
+	}
+	for _, candidate := range fixtureExtensions {
+		if candidate.encoding == encoding {
+			return candidate.ext, true
+		}
+	}
+	return "", false
+}
+
+// recordingBody дублирует прочитанное тело во временный файл.
+// Фикстура заменяется только если тело получено полностью — оборванный ответ не портит запись.
+type recordingBody struct {
+	body     io.ReadCloser
+	tmp      *os.File
+	dir      string
+	name     string
+	ext      string
+	complete bool
+	failed   bool
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 && !b.failed {
+		if _, werr := b.tmp.Write(p[:n]); werr != nil {
+			b.failed = true
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		b.complete = true
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	// Декодер может остановиться раньше конца потока (хвост сжатых данных, пробелы) — дочитываем его
+	if !b.complete && !b.failed {
+		if _, err := io.Copy(b.tmp, b.body); err == nil {
+			b.complete = true
+		}
+	}
+
+	err := b.body.Close()
+
+	tmpName := b.tmp.Name()
+	closeErr := b.tmp.Close()
+	if !b.complete || b.failed || closeErr != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	// Убираем фикстуры с другим сжатием, чтобы FixtureFetcher не прочитал устаревшую
+	for _, candidate := range fixtureExtensions {
+		if candidate.ext != b.ext {
+			os.Remove(filepath.Join(b.dir, b.name+candidate.ext))
+		}
+	}
+
+	if renameErr := os.Rename(tmpName, filepath.Join(b.dir, b.name+b.ext)); renameErr != nil {
+		os.Remove(tmpName)
+		return renameErr
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package skinport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+func writeFixture(t *testing.T, dir, name, encoding, body string) {
+	t.Helper()
+
+	ext, ok := extensionFor(encoding)
+	if !ok {
+		t.Fatalf("unsupported encoding %q", encoding)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+ext), compress(t, encoding, []byte(body)), 0o600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+}
+
+func TestFixtureFetcher_ShippedFixtures(t *testing.T) {
+	fetcher := NewFixtureFetcher(filepath.Join("..", "..", "..", "fixtures", "skinport"))
+
+	items, err := fetcher.FetchItems(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(items) != 5 {
+		t.Errorf("expected 5 items in shipped fixtures, got %d", len(items))
+	}
+
+	histories, err := fetcher.FetchSalesHistory(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(histories) != 2 {
+		t.Errorf("expected 2 sales histories in shipped fixtures, got %d", len(histories))
+	}
+}
+
+func TestFixtureFetcher_CompressedFixtures(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, fixtureItemsTradable, "br", tradableFixture)
+	writeFixture(t, dir, fixtureItemsNonTradable, "gzip", nonTradableFixture)
+
+	items, err := NewFixtureFetcher(dir).FetchItems(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	byName := make(map[string]*item.Item, len(items))
+	for _, it := range items {
+		byName[it.MarketHashName] = it
+	}
+	ak := byName["AK-47 | Redline (Field-Tested)"]
+	if len(byName) != 3 || ak == nil || ak.TradableMinPrice.String() != "12.5" || ak.NonTradableMinPrice.String() != "10.2" {
+		t.Errorf("unexpected merged catalogue: %+v", byName)
+	}
+}
+
+func TestFixtureFetcher_MissingFixture(t *testing.T) {
+	fetcher := NewFixtureFetcher(t.TempDir())
+
+	if _, err := fetcher.FetchItems(context.Background()); !errors.Is(err, ErrFixtureNotFound) {
+		t.Errorf("expected ErrFixtureNotFound, got %v", err)
+	}
+	if _, err := fetcher.FetchSalesHistory(context.Background()); !errors.Is(err, ErrFixtureNotFound) {
+		t.Errorf("expected ErrFixtureNotFound, got %v", err)
+	}
+}
+
+func TestRecordingTransport_RecordsAndReplays(t *testing.T) {
+	server := newSkinportServer(t, "br")
+	defer server.Close()
+
+	dir := t.TempDir()
+	// Устаревшая несжатая фикстура должна быть заменена записанной
+	writeFixture(t, dir, fixtureItemsTradable, "", `[]`)
+
+	client := NewClientWithTransport(server.URL, 5*time.Second, NewRecordingTransport(dir, nil))
+	live, err := client.FetchItems(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, name := range []string{fixtureItemsTradable, fixtureItemsNonTradable} {
+		if _, err := os.Stat(filepath.Join(dir, name+".json.br")); err != nil {
+			t.Errorf("expected %s to be recorded with brotli: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, fixtureItemsTradable+".json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected stale fixture to be removed, got %v", err)
+	}
+
+	replayed, err := NewFixtureFetcher(dir).FetchItems(context.Background())
+	if err != nil {
+		t.Fatalf("expected replay to succeed, got %v", err)
+	}
+	if len(replayed) != len(live) {
+		t.Errorf("expected %d replayed items, got %d", len(live), len(replayed))
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("expected only recorded fixtures in directory, got %d entries", len(entries))
+	}
+}
+
+func TestRecordingTransport_SkipsFailedResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClientWithTransport(server.URL, 5*time.Second, NewRecordingTransport(dir, nil))
+
+	if _, err := client.FetchItems(context.Background()); err == nil {
+		t.Fatal("expected error for non-200 status")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected nothing recorded, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,80 @@
+package skinport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// maxPriceScale максимально допустимое количество знаков после запятой
+	maxPriceScale = 4
+)
+
+var (
+	// maxPriceValue верхняя граница цены, все что выше считаем мусором в ответе API
+	maxPriceValue = decimal.New(1, 12)
+
+	// ErrInvalidPrice возвращается когда цена в ответе Skinport некорректна
+	ErrInvalidPrice = errors.New("invalid price")
+)
+
+// Price представляет цену из API Skinport.
+// Декодируется напрямую из JSON числа в decimal, минуя float64,
+// поэтому значения вроде 0.1 или 1234567.89 сохраняются без потерь.
+type Price struct {
+	decimal.Decimal
+}
+
+// UnmarshalJSON декодирует цену из JSON числа и валидирует её
+func (p *Price) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	// null для полей-указателей обрабатывает encoding/json, для значения оставляем ноль
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	// Skinport отдает цены числами — строки и прочие типы считаем ошибкой
+	if !json.Valid(data) || len(data) == 0 || data[0] == '"' {
+		return fmt.Errorf("%w: %s is not a JSON number", ErrInvalidPrice, data)
+	}
+
+	d, err := decimal.NewFromString(string(data))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidPrice, data, err)
+	}
+
+	if err := validatePrice(d); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidPrice, data, err)
+	}
+
+	p.Decimal = d
+	return nil
+}
+
+// validatePrice проверяет что цена неотрицательна и имеет разумную точность
+func validatePrice(d decimal.Decimal) error {
+	if d.IsNegative() {
+		return errors.New("must not be negative")
+	}
+	if d.GreaterThanOrEqual(maxPriceValue) {
+		return fmt.Errorf("must be less than %s", maxPriceValue.String())
+	}
+	if !d.Equal(d.Truncate(maxPriceScale)) {
+		return fmt.Errorf("must have at most %d decimal places", maxPriceScale)
+	}
+	return nil
+}
+
+// toDecimal возвращает указатель на decimal или nil если цена отсутствует
+func (p *Price) toDecimal() *decimal.Decimal {
+	if p == nil {
+		return nil
+	}
+	d := p.Decimal
+	return &d
+}
@@ -0,0 +1,168 @@
+package skinport
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestPrice_UnmarshalJSON_RoundTrip(t *testing.T) {
+	// Значения, которые плохо переживают float64
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"0.1", "0.1"},
+		{"0.2", "0.2"},
+		{"0.3", "0.3"},
+		{"1.005", "1.005"},
+		{"2.675", "2.675"},
+		{"12.50", "12.5"},
+		{"1234567.89", "1234567.89"},
+		{"9999999.99", "9999999.99"},
+		{"123456789012.34", "123456789012.34"},
+		{"999999999999.9999", "999999999999.9999"},
+		{"0.0001", "0.0001"},
+		{"0", "0"},
+		{"1e2", "100"},
+		{"1.5E1", "15"},
+		{"0.10000", "0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			var p Price
+			if err := json.Unmarshal([]byte(tt.raw), &p); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if p.String() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, p.String())
+			}
+
+			// Повторное кодирование и декодирование не должно менять значение
+			encoded, err := json.Marshal(p.Decimal)
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			var back Price
+			if err := json.Unmarshal(encoded, &back.Decimal); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !back.Equal(p.Decimal) {
+				t.Errorf("round trip changed value: %s -> %s", p.String(), back.String())
+			}
+		})
+	}
+}
+
+func TestPrice_UnmarshalJSON_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"negative", "-0.01"},
+		{"too precise", "0.00001"},
+		{"too large", "1000000000000"},
+		{"string", `"12.50"`},
+		{"bool", "true"},
+		{"object", `{"value": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Price
+			err := json.Unmarshal([]byte(tt.raw), &p)
+			if err == nil {
+				t.Fatalf("expected error for %s, got value %s", tt.raw, p.String())
+			}
+			if !errors.Is(err, ErrInvalidPrice) {
+				t.Errorf("expected ErrInvalidPrice, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSkinportItem_DecodePrices(t *testing.T) {
+	raw := `{
+		"market_hash_name": "AK-47 | Redline (Field-Tested)",
+		"currency": "USD",
+		"suggested_price": 1234567.89,
+		"min_price": 0.1,
+		"max_price": null,
+		"quantity": 3
+	}`
+
+	var si SkinportItem
+	if err := json.Unmarshal([]byte(raw), &si); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if si.SuggestedPrice == nil || si.SuggestedPrice.String() != "1234567.89" {
+		t.Errorf("expected suggested price 1234567.89, got %v", si.SuggestedPrice)
+	}
+	if si.MinPrice == nil || si.MinPrice.String() != "0.1" {
+		t.Errorf("expected min price 0.1, got %v", si.MinPrice)
+	}
+	if si.MaxPrice != nil {
+		t.Errorf("expected nil max price, got %s", si.MaxPrice.String())
+	}
+	if si.MeanPrice != nil {
+		t.Errorf("expected nil mean price, got %s", si.MeanPrice.String())
+	}
+}
+
+func TestSkinportItem_DecodeRejectsInvalidPrice(t *testing.T) {
+	raw := `[{"market_hash_name": "AWP | Asiimov", "min_price": -5}]`
+
+	var items []SkinportItem
+	err := json.Unmarshal([]byte(raw), &items)
+	if !errors.Is(err, ErrInvalidPrice) {
+		t.Errorf("expected ErrInvalidPrice, got %v", err)
+	}
+}
+
+func TestMergeItems_KeepsExactPrices(t *testing.T) {
+	tradable := map[string]*SkinportItem{
+		"AWP | Asiimov": {
+			MarketHashName: "AWP | Asiimov",
+			Currency:       "USD",
+			MinPrice:       mustPrice(t, "1234567.89"),
+			MeanPrice:      mustPrice(t, "0.3"),
+		},
+	}
+	nonTradable := map[string]*SkinportItem{
+		"AWP | Asiimov": {
+			MarketHashName: "AWP | Asiimov",
+			MinPrice:       mustPrice(t, "0.1"),
+		},
+	}
+
+	items := mergeItems(tradable, nonTradable)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+
+	it := items[0]
+	if it.TradableMinPrice.String() != "1234567.89" {
+		t.Errorf("expected tradable min 1234567.89, got %s", it.TradableMinPrice.String())
+	}
+	if it.NonTradableMinPrice.String() != "0.1" {
+		t.Errorf("expected non-tradable min 0.1, got %s", it.NonTradableMinPrice.String())
+	}
+	if it.MeanPrice.String() != "0.3" {
+		t.Errorf("expected mean 0.3, got %s", it.MeanPrice.String())
+	}
+}
+
+func mustPrice(t *testing.T, raw string) *Price {
+	t.Helper()
+
+	var p Price
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		t.Fatalf("failed to parse price %s: %v", raw, err)
+	}
+	return &p
+}
@@ -0,0 +1,336 @@
+package skinport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// Пакеты Engine.IO v4 (текстовые фреймы)
+const (
+	engineOpen  = '0'
+	engineClose = '1'
+	enginePing  = '2'
+	enginePong  = '3'
+	engineNoop  = '6'
+)
+
+// Пакеты Socket.IO v5 (бинарные фреймы, msgpack parser)
+const (
+	socketConnect      = 0
+	socketDisconnect   = 1
+	socketEvent        = 2
+	socketConnectError = 4
+)
+
+const (
+	saleFeedEvent     = "saleFeed"
+	saleFeedJoinEvent = "saleFeedJoin"
+	socketNamespace   = "/"
+
+	// socketIOPath путь рукопожатия Socket.IO поверх WebSocket транспорта
+	socketIOPath = "/socket.io/?EIO=4&transport=websocket"
+
+	defaultMinReconnectBackoff = time.Second
+	defaultMaxReconnectBackoff = time.Minute
+	handshakeTimeout           = 10 * time.Second
+)
+
+// ErrFeedDisconnected возвращается когда сервер завершил сессию ленты продаж
+var ErrFeedDisconnected = errors.New("sale feed disconnected")
+
+// SaleFeed реализует подписку на ленту продаж Skinport (Socket.IO с msgpack парсером)
+type SaleFeed struct {
+	url      string
+	currency string
+	locale   string
+	appID    int
+	logger   *slog.Logger
+
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// NewSaleFeed создает подписку на ленту продаж. baseURL задается без пути, например wss://skinport.com
+func NewSaleFeed(baseURL, currency, locale string, logger *slog.Logger) *SaleFeed {
+	return &SaleFeed{
+		url:        strings.TrimRight(baseURL, "/") + socketIOPath,
+		currency:   currency,
+		locale:     locale,
+		appID:      730,
+		logger:     logger,
+		minBackoff: defaultMinReconnectBackoff,
+		maxBackoff: defaultMaxReconnectBackoff,
+	}
+}
+
+// Subscribe подключается к ленте и передает события в handler до отмены контекста.
+// При обрыве соединения переподключается с экспоненциальной задержкой.
+func (f *SaleFeed) Subscribe(ctx context.Context, handler output.SaleEventHandler) error {
+	backoff := f.minBackoff
+
+	for {
+		joined, err := f.session(ctx, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Успешная сессия сбрасывает задержку: обрыв после долгой работы — не повод ждать минуту
+		if joined {
+			backoff = f.minBackoff
+		}
+
+		f.logger.Warn("sale feed connection lost, reconnecting",
+			slog.Any("error", err),
+			slog.Duration("backoff", backoff),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > f.maxBackoff {
+			backoff = f.maxBackoff
+		}
+	}
+}
+
+// session обслуживает одно соединение. joined сообщает, удалось ли подписаться на ленту.
+func (f *SaleFeed) session(ctx context.Context, handler output.SaleEventHandler) (joined bool, err error) {
+	dialCtx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+
+	header := http.Header{}
+	header.Set("Origin", "https://skinport.com")
+
+	conn, _, err := websocket.DefaultDialer.DialContext(dialCtx, f.url, header)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	// Закрываем соединение при отмене контекста, чтобы прервать блокирующее чтение
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	_ = conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	heartbeat, err := f.open(conn)
+	if err != nil {
+		return false, err
+	}
+
+	if err := writePacket(conn, &socketPacket{Type: socketConnect, Nsp: socketNamespace}); err != nil {
+		return false, err
+	}
+
+	for {
+		// Сервер присылает ping каждые pingInterval; если его нет дольше heartbeat — соединение мертво
+		_ = conn.SetReadDeadline(time.Now().Add(heartbeat))
+
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return joined, err
+		}
+
+		if messageType == websocket.TextMessage {
+			if err := f.handleEnginePacket(conn, data); err != nil {
+				return joined, err
+			}
+			continue
+		}
+
+		packet, err := decodePacket(data)
+		if err != nil {
+			f.logger.Warn("failed to decode sale feed packet", slog.Any("error", err))
+			continue
+		}
+
+		switch packet.Type {
+		case socketConnect:
+			join := &socketPacket{
+				Type: socketEvent,
+				Nsp:  socketNamespace,
+				Data: []interface{}{saleFeedJoinEvent, map[string]interface{}{
+					"currency": f.currency,
+					"locale":   f.locale,
+					"appid":    f.appID,
+				}},
+			}
+			if err := writePacket(conn, join); err != nil {
+				return joined, err
+			}
+			joined = true
+			f.logger.Info("subscribed to sale feed", slog.String("currency", f.currency))
+		case socketEvent:
+			events, err := parseSaleFeedEvent(packet.Data, time.Now().UTC())
+			if err != nil {
+				f.logger.Warn("failed to parse sale feed event", slog.Any("error", err))
+				continue
+			}
+			if len(events) > 0 {
+				handler(ctx, events)
+			}
+		case socketConnectError:
+			return joined, fmt.Errorf("sale feed connect error: %v", packet.Data)
+		case socketDisconnect:
+			return joined, ErrFeedDisconnected
+		}
+	}
+}
+
+// open читает пакет OPEN и возвращает допустимую паузу между ping сервера
+func (f *SaleFeed) open(conn *websocket.Conn) (time.Duration, error) {
+	messageType, data, err := conn.ReadMessage()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read open packet: %w", err)
+	}
+	if messageType != websocket.TextMessage || len(data) == 0 || data[0] != engineOpen {
+		return 0, fmt.Errorf("unexpected open packet: %q", data)
+	}
+
+	var handshake struct {
+		PingInterval int64 `json:"pingInterval"`
+		PingTimeout  int64 `json:"pingTimeout"`
+	}
+	if err := json.Unmarshal(data[1:], &handshake); err != nil {
+		return 0, fmt.Errorf("failed to decode open packet: %w", err)
+	}
+
+	heartbeat := time.Duration(handshake.PingInterval+handshake.PingTimeout) * time.Millisecond
+	if heartbeat <= 0 {
+		heartbeat = handshakeTimeout
+	}
+	return heartbeat, nil
+}
+
+func (f *SaleFeed) handleEnginePacket(conn *websocket.Conn, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	switch data[0] {
+	case enginePing:
+		return conn.WriteMessage(websocket.TextMessage, []byte{enginePong})
+	case engineClose:
+		return ErrFeedDisconnected
+	case engineNoop, enginePong:
+		return nil
+	default:
+		f.logger.Debug("ignoring engine.io packet", slog.String("packet", string(data)))
+		return nil
+	}
+}
+
+// socketPacket пакет Socket.IO в представлении msgpack парсера
+type socketPacket struct {
+	Type int         `msgpack:"type"`
+	Nsp  string      `msgpack:"nsp"`
+	Data interface{} `msgpack:"data,omitempty"`
+}
+
+func decodePacket(data []byte) (*socketPacket, error) {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	// Числа в data приводятся к int64, uint64 или float64 независимо от ширины кодировки
+	dec.UseLooseInterfaceDecoding(true)
+
+	var packet struct {
+		Type *int        `msgpack:"type"`
+		Nsp  string      `msgpack:"nsp"`
+		Data interface{} `msgpack:"data"`
+	}
+	if err := dec.Decode(&packet); err != nil {
+		return nil, err
+	}
+	if packet.Type == nil {
+		return nil, fmt.Errorf("packet type is missing")
+	}
+	return &socketPacket{Type: *packet.Type, Nsp: packet.Nsp, Data: packet.Data}, nil
+}
+
+func writePacket(conn *websocket.Conn, packet *socketPacket) error {
+	data, err := msgpack.Marshal(packet)
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.BinaryMessage, data)
+}
+
+// skinportSaleFeed полезная нагрузка события saleFeed
+type skinportSaleFeed struct {
+	EventType string         `json:"eventType"`
+	Sales     []skinportSale `json:"sales"`
+}
+
+// skinportSale продажа из ленты; цены приходят в центах
+type skinportSale struct {
+	SaleID         int64    `json:"saleId"`
+	MarketHashName string   `json:"marketHashName"`
+	Currency       string   `json:"currency"`
+	SalePrice      int64    `json:"salePrice"`
+	SuggestedPrice *int64   `json:"suggestedPrice"`
+	Lock           *string  `json:"lock"`
+	Wear           *float64 `json:"wear"`
+}
+
+// parseSaleFeedEvent нормализует событие ["saleFeed", {...}] в доменные события.
+// Некорректные продажи пропускаются, чтобы одна запись не роняла всю пачку.
+func parseSaleFeedEvent(data interface{}, receivedAt time.Time) ([]*item.SaleEvent, error) {
+	args, ok := data.([]interface{})
+	if !ok || len(args) < 2 {
+		return nil, fmt.Errorf("unexpected event payload %T", data)
+	}
+	if name, _ := args[0].(string); name != saleFeedEvent {
+		return nil, nil
+	}
+
+	// Динамическое значение msgpack проще всего привести к структуре через JSON
+	raw, err := json.Marshal(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var feed skinportSaleFeed
+	if err := json.Unmarshal(raw, &feed); err != nil {
+		return nil, err
+	}
+
+	events := make([]*item.SaleEvent, 0, len(feed.Sales))
+	for _, sale := range feed.Sales {
+		ev, err := item.NewSaleEvent(
+			item.SaleEventType(feed.EventType),
+			sale.SaleID,
+			sale.MarketHashName,
+			sale.Currency,
+			decimal.New(sale.SalePrice, -2),
+			sale.Lock == nil || *sale.Lock == "",
+			receivedAt,
+		)
+		if err != nil {
+			continue
+		}
+		if sale.SuggestedPrice != nil {
+			suggested := decimal.New(*sale.SuggestedPrice, -2)
+			ev.SuggestedPrice = &suggested
+		}
+		ev.Wear = sale.Wear
+		events = append(events, ev)
+	}
+
+	return events, nil
+}
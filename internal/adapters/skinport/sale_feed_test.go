@@ -0,0 +1,270 @@
+package skinport
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// testUpgrader принимает любой Origin: клиент представляется как https://skinport.com
+var testUpgrader = websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+
+// fakeSaleFeedServer имитирует Socket.IO сервер Skinport с msgpack парсером
+type fakeSaleFeedServer struct {
+	t        *testing.T
+	sessions [][]map[string]interface{} // полезная нагрузка saleFeed по сессиям
+
+	mu    sync.Mutex
+	joins []map[string]interface{}
+	pongs int
+	count int
+}
+
+func (s *fakeSaleFeedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/socket.io/" || r.URL.Query().Get("EIO") != "4" {
+		http.NotFound(w, r)
+		return
+	}
+
+	conn, err := testUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	s.mu.Lock()
+	session := s.count
+	s.count++
+	s.mu.Unlock()
+
+	open := `0{"sid":"abc","upgrades":[],"pingInterval":25000,"pingTimeout":20000,"maxPayload":1000000}`
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(open)); err != nil {
+		return
+	}
+
+	connect := s.readPacket(conn)
+	if connect == nil || connect.Type != socketConnect || connect.Nsp != "/" {
+		s.t.Errorf("expected connect packet, got %+v", connect)
+		return
+	}
+	s.writePacket(conn, &socketPacket{Type: socketConnect, Nsp: "/", Data: map[string]interface{}{"sid": "xyz"}})
+
+	join := s.readPacket(conn)
+	if join == nil {
+		s.t.Error("expected saleFeedJoin, got nothing")
+		return
+	}
+	args, _ := join.Data.([]interface{})
+	if len(args) != 2 || args[0] != saleFeedJoinEvent {
+		s.t.Errorf("expected saleFeedJoin, got %+v", join)
+		return
+	}
+	params, _ := args[1].(map[string]interface{})
+
+	// Проверяем heartbeat: клиент обязан ответить pong на ping
+	if err := conn.WriteMessage(websocket.TextMessage, []byte{enginePing}); err != nil {
+		return
+	}
+	if messageType, data, err := conn.ReadMessage(); err == nil && messageType == websocket.TextMessage && string(data) == "3" {
+		s.mu.Lock()
+		s.pongs++
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	s.joins = append(s.joins, params)
+	s.mu.Unlock()
+
+	if session < len(s.sessions) {
+		for _, payload := range s.sessions[session] {
+			s.writePacket(conn, &socketPacket{Type: socketEvent, Nsp: "/", Data: []interface{}{saleFeedEvent, payload}})
+		}
+	}
+
+	// Первая сессия обрывается сервером, последующие держатся до закрытия клиентом
+	if session == 0 && len(s.sessions) > 1 {
+		conn.WriteMessage(websocket.TextMessage, []byte{engineClose}) //nolint:errcheck // test server
+		return
+	}
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeSaleFeedServer) readPacket(conn *websocket.Conn) *socketPacket {
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return nil
+	}
+	packet, err := decodePacket(data)
+	if err != nil {
+		s.t.Errorf("client sent invalid packet: %v", err)
+		return nil
+	}
+	return packet
+}
+
+func (s *fakeSaleFeedServer) writePacket(conn *websocket.Conn, packet *socketPacket) {
+	data, err := msgpack.Marshal(packet)
+	if err != nil {
+		s.t.Fatalf("failed to marshal packet: %v", err)
+	}
+	conn.WriteMessage(websocket.BinaryMessage, data) //nolint:errcheck // test server
+}
+
+func newTestSaleFeed(url string) *SaleFeed {
+	feed := NewSaleFeed("ws"+strings.TrimPrefix(url, "http"), "USD", "en", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	feed.minBackoff = 10 * time.Millisecond
+	feed.maxBackoff = 10 * time.Millisecond
+	return feed
+}
+
+func listedPayload(saleID int64, name string, cents int64, lock interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"eventType": "listed",
+		"sales": []interface{}{map[string]interface{}{
+			"saleId":         saleID,
+			"marketHashName": name,
+			"currency":       "USD",
+			"salePrice":      cents,
+			"suggestedPrice": 2000,
+			"lock":           lock,
+			"wear":           0.15,
+			"stickers":       []interface{}{},
+		}},
+	}
+}
+
+func TestSaleFeed_Subscribe(t *testing.T) {
+	fake := &fakeSaleFeedServer{t: t, sessions: [][]map[string]interface{}{{
+		listedPayload(1, "AK-47 | Redline (Field-Tested)", 1250, nil),
+		{"eventType": "sold", "sales": []interface{}{
+			map[string]interface{}{"saleId": 2, "marketHashName": "AWP | Asiimov (Field-Tested)", "currency": "USD", "salePrice": 9999, "lock": "2024-01-01T00:00:00.000Z"},
+			map[string]interface{}{"saleId": 3, "marketHashName": "", "currency": "USD", "salePrice": 1},
+		}},
+	}}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan []*item.SaleEvent, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- newTestSaleFeed(server.URL).Subscribe(ctx, func(_ context.Context, events []*item.SaleEvent) {
+			received <- events
+		})
+	}()
+
+	first := <-received
+	if len(first) != 1 {
+		t.Fatalf("expected 1 listed event, got %d", len(first))
+	}
+	ev := first[0]
+	if ev.Type != item.SaleEventListed || ev.SaleID != 1 || ev.Price.String() != "12.5" || !ev.Tradable {
+		t.Errorf("unexpected listed event: %+v", ev)
+	}
+	if ev.SuggestedPrice == nil || ev.SuggestedPrice.String() != "20" || ev.Wear == nil || *ev.Wear != 0.15 {
+		t.Errorf("unexpected optional fields: suggested %v, wear %v", ev.SuggestedPrice, ev.Wear)
+	}
+
+	second := <-received
+	if len(second) != 1 {
+		t.Fatalf("expected invalid sale to be skipped, got %d events", len(second))
+	}
+	if second[0].Type != item.SaleEventSold || second[0].Tradable || second[0].Price.String() != "99.99" {
+		t.Errorf("unexpected sold event: %+v", second[0])
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected nil error after cancel, got %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.pongs != 1 {
+		t.Errorf("expected client to answer ping, got %d pongs", fake.pongs)
+	}
+	if len(fake.joins) != 1 || fake.joins[0]["currency"] != "USD" || fake.joins[0]["appid"] != uint64(730) {
+		t.Errorf("unexpected join params: %v", fake.joins)
+	}
+}
+
+func TestSaleFeed_Reconnects(t *testing.T) {
+	fake := &fakeSaleFeedServer{t: t, sessions: [][]map[string]interface{}{
+		{listedPayload(1, "AK-47 | Redline (Field-Tested)", 100, nil)},
+		{listedPayload(2, "AK-47 | Redline (Field-Tested)", 200, nil)},
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan *item.SaleEvent, 10)
+	go newTestSaleFeed(server.URL).Subscribe(ctx, func(_ context.Context, events []*item.SaleEvent) { //nolint:errcheck // stopped by cancel
+		for _, ev := range events {
+			received <- ev
+		}
+	})
+
+	for _, expected := range []int64{1, 2} {
+		select {
+		case ev := <-received:
+			if ev.SaleID != expected {
+				t.Errorf("expected sale %d, got %d", expected, ev.SaleID)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for sale %d", expected)
+		}
+	}
+}
+
+func TestParseSaleFeedEvent_IgnoresOtherEvents(t *testing.T) {
+	events, err := parseSaleFeedEvent([]interface{}{"maintenance", map[string]interface{}{}}, time.Now())
+	if err != nil || events != nil {
+		t.Errorf("expected other events to be ignored, got %v, %v", events, err)
+	}
+
+	if _, err := parseSaleFeedEvent("garbage", time.Now()); err == nil {
+		t.Error("expected error for malformed payload")
+	}
+}
+
+func TestDecodePacket(t *testing.T) {
+	// Socket.IO сервер кодирует неотрицательные числа как uint
+	data, err := msgpack.Marshal(map[string]interface{}{"type": uint8(socketEvent), "nsp": "/", "data": []interface{}{saleFeedEvent}})
+	if err != nil {
+		t.Fatalf("failed to marshal packet: %v", err)
+	}
+	packet, err := decodePacket(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if packet.Type != socketEvent || packet.Nsp != "/" {
+		t.Errorf("unexpected packet: %+v", packet)
+	}
+
+	data, err = msgpack.Marshal(map[string]interface{}{"nsp": "/"})
+	if err != nil {
+		t.Fatalf("failed to marshal packet: %v", err)
+	}
+	if _, err := decodePacket(data); err == nil {
+		t.Error("expected error for packet without type")
+	}
+}
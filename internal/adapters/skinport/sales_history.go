@@ -0,0 +1,100 @@
+package skinport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// SkinportSalesStats представляет статистику продаж за период из API Skinport
+type SkinportSalesStats struct {
+	Min    *Price `json:"min"`
+	Max    *Price `json:"max"`
+	Avg    *Price `json:"avg"`
+	Median *Price `json:"median"`
+	Volume int    `json:"volume"`
+}
+
+// SkinportSalesHistory представляет историю продаж предмета из API Skinport
+type SkinportSalesHistory struct {
+	MarketHashName string             `json:"market_hash_name"`
+	Currency       string             `json:"currency"`
+	ItemPage       string             `json:"item_page"`
+	MarketPage     string             `json:"market_page"`
+	Last24Hours    SkinportSalesStats `json:"last_24_hours"`
+	Last7Days      SkinportSalesStats `json:"last_7_days"`
+	Last30Days     SkinportSalesStats `json:"last_30_days"`
+	Last90Days     SkinportSalesStats `json:"last_90_days"`
+}
+
+// FetchSalesHistory получает историю продаж предметов из Skinport API
+func (c *Client) FetchSalesHistory(ctx context.Context) ([]*item.SalesHistory, error) {
+	var result []*item.SalesHistory
+
+	err := c.guarded(ctx, func() error {
+		return c.get(ctx, "/sales/history?app_id=730&currency=USD", func(body io.Reader) error {
+			var err error
+			result, err = decodeSalesHistory(body)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: sales history: %w", item.ErrFetchFailed, err)
+	}
+
+	return result, nil
+}
+
+// decodeSalesHistory потоково читает JSON массив истории продаж
+func decodeSalesHistory(r io.Reader) ([]*item.SalesHistory, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("expected JSON array, got %v", tok)
+	}
+
+	var result []*item.SalesHistory
+	for dec.More() {
+		var sh SkinportSalesHistory
+		if err := dec.Decode(&sh); err != nil {
+			return nil, err
+		}
+		result = append(result, sh.toDomain())
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sh *SkinportSalesHistory) toDomain() *item.SalesHistory {
+	return &item.SalesHistory{
+		MarketHashName: sh.MarketHashName,
+		Currency:       sh.Currency,
+		ItemPage:       sh.ItemPage,
+		MarketPage:     sh.MarketPage,
+		Last24Hours:    sh.Last24Hours.toDomain(),
+		Last7Days:      sh.Last7Days.toDomain(),
+		Last30Days:     sh.Last30Days.toDomain(),
+		Last90Days:     sh.Last90Days.toDomain(),
+	}
+}
+
+func (s *SkinportSalesStats) toDomain() item.SalesStats {
+	return item.SalesStats{
+		Min:    s.Min.toDecimal(),
+		Max:    s.Max.toDecimal(),
+		Avg:    s.Avg.toDecimal(),
+		Median: s.Median.toDecimal(),
+		Volume: s.Volume,
+	}
+}
@@ -0,0 +1,69 @@
+package skinport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const salesHistoryFixture = `[
+	{
+		"market_hash_name": "AK-47 | Redline (Field-Tested)",
+		"version": null,
+		"currency": "USD",
+		"item_page": "https://skinport.com/item/ak",
+		"market_page": "https://skinport.com/market/ak",
+		"last_24_hours": {"min": 12.1, "max": 14, "avg": 12.85, "median": 12.8, "volume": 42},
+		"last_7_days": {"min": 11.9, "max": 15.2, "avg": 12.95, "median": 12.9, "volume": 310},
+		"last_30_days": {"min": 11.5, "max": 16, "avg": 13.1, "median": 13, "volume": 1204},
+		"last_90_days": {"min": 10.8, "max": 17.3, "avg": 13.4, "median": 13.2, "volume": 3950}
+	},
+	{
+		"market_hash_name": "Sticker | Rare (Holo)",
+		"currency": "USD",
+		"last_24_hours": {"min": null, "max": null, "avg": null, "median": null, "volume": 0},
+		"last_7_days": {"min": null, "max": null, "avg": null, "median": null, "volume": 0},
+		"last_30_days": {"min": 0.1, "max": 0.1, "avg": 0.1, "median": 0.1, "volume": 1},
+		"last_90_days": {"min": 0.1, "max": 0.3, "avg": 0.2, "median": 0.2, "volume": 2}
+	}
+]`
+
+func TestClient_FetchSalesHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sales/history" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Encoding", "br")
+		w.Write(compress(t, "br", []byte(salesHistoryFixture))) //nolint:errcheck // test server
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+
+	histories, err := client.FetchSalesHistory(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(histories) != 2 {
+		t.Fatalf("expected 2 histories, got %d", len(histories))
+	}
+
+	ak := histories[0]
+	if ak.Last24Hours.Volume != 42 || ak.Last24Hours.Median.String() != "12.8" {
+		t.Errorf("unexpected last 24 hours: %+v", ak.Last24Hours)
+	}
+	if ak.Last90Days.Max.String() != "17.3" {
+		t.Errorf("expected 90 days max 17.3, got %s", ak.Last90Days.Max.String())
+	}
+
+	sticker := histories[1]
+	if sticker.Last24Hours.Min != nil || sticker.Last24Hours.Volume != 0 {
+		t.Errorf("expected empty last 24 hours, got %+v", sticker.Last24Hours)
+	}
+	if sticker.Last30Days.Avg.String() != "0.1" {
+		t.Errorf("expected 30 days avg 0.1, got %s", sticker.Last30Days.Avg.String())
+	}
+}
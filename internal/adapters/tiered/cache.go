@@ -0,0 +1,154 @@
+// Package tiered реализует двухуровневый кэш: локальная копия в процессе (L1)
+// поверх общего для реплик хранилища (L2) с рассылкой инвалидаций
+package tiered
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// defaultLocalTTL время жизни копии в L1, если не задано
+const defaultLocalTTL = 30 * time.Second
+
+// Cache декоратор output.Cache из двух уровней.
+//
+// Чтение сначала идет в L1, при промахе — в L2, и найденное значение кладется в L1.
+// Запись идет в оба уровня и рассылает инвалидацию: остальные реплики удаляют ключ
+// из своего L1 и при следующем чтении берут новую версию из L2, а не из источника.
+// Время жизни в L1 ограничено localTTL — это верхняя граница устаревания копии,
+// если инвалидация потерялась, — и оставшимся временем жизни ключа в L2: копия не
+// переживает оригинал.
+type Cache struct {
+	local    output.Cache
+	shared   output.Cache
+	bus      output.CacheInvalidationBus
+	origin   string
+	localTTL time.Duration
+	logger   *slog.Logger
+}
+
+// NewCache создает двухуровневый кэш. bus может быть nil — тогда копии в L1
+// живут до истечения localTTL.
+func NewCache(
+	local output.Cache,
+	shared output.Cache,
+	bus output.CacheInvalidationBus,
+	localTTL time.Duration,
+	logger *slog.Logger,
+) *Cache {
+	if localTTL <= 0 {
+		localTTL = defaultLocalTTL
+	}
+
+	return &Cache{
+		local:    local,
+		shared:   shared,
+		bus:      bus,
+		origin:   uuid.NewString(),
+		localTTL: localTTL,
+		logger:   logger,
+	}
+}
+
+// Run подписывается на инвалидации других реплик. Блокируется до отмены контекста.
+func (c *Cache) Run(ctx context.Context) error {
+	if c.bus == nil {
+		<-ctx.Done()
+		return nil
+	}
+	return c.bus.Subscribe(ctx, c.handleInvalidation)
+}
+
+// Get получает значение из кэша
+func (c *Cache) Get(ctx context.Context, key string) (interface{}, bool) {
+	if value, ok := c.local.Get(ctx, key); ok {
+		return value, true
+	}
+
+	reader, ok := c.shared.(output.CacheTTLReader)
+	if !ok {
+		value, ok := c.shared.Get(ctx, key)
+		if ok {
+			c.local.Set(ctx, key, value, c.localTTL)
+		}
+		return value, ok
+	}
+
+	value, remaining, ok := reader.GetWithTTL(ctx, key)
+	if !ok {
+		return nil, false
+	}
+
+	ttl := c.localTTL
+	if remaining >= 0 {
+		ttl = min(remaining, c.localTTL)
+	}
+	// Неизвестный или истекший TTL: значение отдаем, но копию не храним
+	if ttl > 0 {
+		c.local.Set(ctx, key, value, ttl)
+	}
+	return value, true
+}
+
+// Set устанавливает значение в кэш с указанным TTL
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	c.shared.Set(ctx, key, value, ttl)
+	c.local.Set(ctx, key, value, min(ttl, c.localTTL))
+	c.publish(ctx, output.CacheInvalidation{Key: key})
+}
+
+// Delete удаляет значение из кэша
+func (c *Cache) Delete(ctx context.Context, key string) {
+	c.shared.Delete(ctx, key)
+	c.local.Delete(ctx, key)
+	c.publish(ctx, output.CacheInvalidation{Key: key})
+}
+
+// Clear очищает весь кэш
+func (c *Cache) Clear(ctx context.Context) {
+	c.shared.Clear(ctx)
+	c.local.Clear(ctx)
+	c.publish(ctx, output.CacheInvalidation{All: true})
+}
+
+// Entries возвращает содержимое общего уровня — он хранит все ключи кластера.
+// Если общий уровень не поддерживает просмотр, возвращается локальный.
+func (c *Cache) Entries(ctx context.Context) ([]output.CacheEntryInfo, error) {
+	if inspector, ok := c.shared.(output.CacheInspector); ok {
+		return inspector.Entries(ctx)
+	}
+	if inspector, ok := c.local.(output.CacheInspector); ok {
+		return inspector.Entries(ctx)
+	}
+	return nil, nil
+}
+
+func (c *Cache) publish(ctx context.Context, msg output.CacheInvalidation) {
+	if c.bus == nil {
+		return
+	}
+
+	msg.Origin = c.origin
+	if err := c.bus.Publish(ctx, msg); err != nil {
+		// Другие реплики увидят изменение не позже истечения localTTL
+		c.logger.WarnContext(ctx, "failed to publish cache invalidation", slog.String("key", msg.Key), slog.Any("error", err))
+	}
+}
+
+func (c *Cache) handleInvalidation(ctx context.Context, msg output.CacheInvalidation) {
+	// Собственные изменения уже применены к L1
+	if msg.Origin == c.origin {
+		return
+	}
+
+	if msg.All {
+		c.local.Clear(ctx)
+		return
+	}
+	c.local.Delete(ctx, msg.Key)
+}
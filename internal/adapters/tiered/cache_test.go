@@ -0,0 +1,173 @@
+package tiered
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/pkg/cache"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// replica локальный кэш реплики поверх общего L2
+type replica struct {
+	local *cache.InMemoryCache
+	cache *Cache
+}
+
+func newReplica(t *testing.T, shared *cache.InMemoryCache, bus output.CacheInvalidationBus) *replica {
+	t.Helper()
+
+	local := cache.NewInMemoryCache(time.Minute)
+	t.Cleanup(local.Close)
+
+	return &replica{
+		local: local,
+		cache: NewCache(local, shared, bus, time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil))),
+	}
+}
+
+// subscribe запускает подписку реплик и ждет, пока все они зарегистрируются в шине
+func subscribe(t *testing.T, bus *LocalBus, replicas ...*replica) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	for _, r := range replicas {
+		go r.cache.Run(ctx)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		bus.mu.RLock()
+		n := len(bus.handlers)
+		bus.mu.RUnlock()
+		if n == len(replicas) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d subscribers, got %d", len(replicas), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCache_ReadThroughSharedTier(t *testing.T) {
+	shared := cache.NewInMemoryCache(time.Minute)
+	defer shared.Close()
+	bus := NewLocalBus()
+
+	a := newReplica(t, shared, bus)
+	b := newReplica(t, shared, bus)
+	subscribe(t, bus, a, b)
+
+	ctx := context.Background()
+
+	// Реплика A обновила каталог из источника — B берет его из L2 без своего запроса
+	a.cache.Set(ctx, "catalogue", "v1", time.Minute)
+
+	value, ok := b.cache.Get(ctx, "catalogue")
+	if !ok || value != "v1" {
+		t.Fatalf("expected v1 from shared tier, got %v, %v", value, ok)
+	}
+	if _, ok := b.local.Get(ctx, "catalogue"); !ok {
+		t.Error("expected value read from shared tier to be kept locally")
+	}
+}
+
+func TestCache_InvalidationReplacesLocalCopy(t *testing.T) {
+	shared := cache.NewInMemoryCache(time.Minute)
+	defer shared.Close()
+	bus := NewLocalBus()
+
+	a := newReplica(t, shared, bus)
+	b := newReplica(t, shared, bus)
+	subscribe(t, bus, a, b)
+
+	ctx := context.Background()
+
+	a.cache.Set(ctx, "catalogue", "v1", time.Minute)
+	b.cache.Get(ctx, "catalogue")
+
+	a.cache.Set(ctx, "catalogue", "v2", time.Minute)
+
+	if value, _ := b.cache.Get(ctx, "catalogue"); value != "v2" {
+		t.Errorf("expected replica B to pick up v2 after invalidation, got %v", value)
+	}
+	// Собственная запись не сбрасывает локальную копию отправителя
+	if value, ok := a.local.Get(ctx, "catalogue"); !ok || value != "v2" {
+		t.Errorf("expected writer to keep its local copy, got %v, %v", value, ok)
+	}
+
+	a.cache.Clear(ctx)
+	if _, ok := b.cache.Get(ctx, "catalogue"); ok {
+		t.Error("expected clear to propagate to other replicas")
+	}
+}
+
+func TestCache_WithoutBusLocalCopyIsStale(t *testing.T) {
+	shared := cache.NewInMemoryCache(time.Minute)
+	defer shared.Close()
+
+	a := newReplica(t, shared, nil)
+	b := newReplica(t, shared, nil)
+
+	ctx := context.Background()
+
+	a.cache.Set(ctx, "catalogue", "v1", time.Minute)
+	b.cache.Get(ctx, "catalogue")
+	a.cache.Set(ctx, "catalogue", "v2", time.Minute)
+
+	// Без рассылки B видит свою копию до истечения localTTL
+	if value, _ := b.cache.Get(ctx, "catalogue"); value != "v1" {
+		t.Errorf("expected stale local copy without invalidation bus, got %v", value)
+	}
+}
+
+func TestCache_LocalTTLIsCapped(t *testing.T) {
+	shared := cache.NewInMemoryCache(time.Minute)
+	defer shared.Close()
+	local := cache.NewInMemoryCache(time.Minute)
+	defer local.Close()
+
+	c := NewCache(local, shared, nil, 20*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	c.Set(ctx, "k", "v", time.Minute)
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := local.Get(ctx, "k"); ok {
+		t.Error("expected local copy to expire after local ttl")
+	}
+	if value, ok := c.Get(ctx, "k"); !ok || value != "v" {
+		t.Errorf("expected value from shared tier after local expiry, got %v, %v", value, ok)
+	}
+}
+
+func TestCache_LocalCopyDoesNotOutliveShared(t *testing.T) {
+	shared := cache.NewInMemoryCache(time.Minute)
+	defer shared.Close()
+	local := cache.NewInMemoryCache(time.Minute)
+	defer local.Close()
+
+	c := NewCache(local, shared, nil, time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	// Значение записала другая реплика с коротким TTL
+	shared.Set(ctx, "k", "v", 30*time.Millisecond)
+
+	if value, ok := c.Get(ctx, "k"); !ok || value != "v" {
+		t.Fatalf("expected value from shared tier, got %v, %v", value, ok)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if _, ok := local.Get(ctx, "k"); ok {
+		t.Error("expected local copy to expire together with shared entry")
+	}
+	if _, ok := c.Get(ctx, "k"); ok {
+		t.Error("expected miss after shared entry expired")
+	}
+}
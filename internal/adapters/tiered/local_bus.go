@@ -0,0 +1,53 @@
+package tiered
+
+import (
+	"context"
+	"sync"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// LocalBus рассылка инвалидаций внутри одного процесса. Заменяет общий брокер
+// в тестах и при запуске нескольких кэшей в одном процессе.
+type LocalBus struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]output.CacheInvalidationHandler
+}
+
+// NewLocalBus создает локальную шину инвалидаций
+func NewLocalBus() *LocalBus {
+	return &LocalBus{handlers: make(map[int]output.CacheInvalidationHandler)}
+}
+
+// Publish синхронно доставляет сообщение всем подписчикам
+func (b *LocalBus) Publish(ctx context.Context, msg output.CacheInvalidation) error {
+	b.mu.RLock()
+	handlers := make([]output.CacheInvalidationHandler, 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, msg)
+	}
+	return nil
+}
+
+// Subscribe регистрирует обработчик до отмены контекста
+func (b *LocalBus) Subscribe(ctx context.Context, handler output.CacheInvalidationHandler) error {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	delete(b.handlers, id)
+	b.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+)
+
+const (
+	// HeaderID идентификатор уведомления, одинаковый для всех попыток доставки
+	HeaderID = "X-Webhook-Id"
+	// HeaderTimestamp момент отправки в секундах Unix, входит в подпись
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature подпись HMAC-SHA256 в формате sha256=<hex>
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// errForbiddenAddress возвращается при попытке соединиться с адресом внутренней сети
+var errForbiddenAddress = errors.New("webhook address is not allowed")
+
+// Notifier реализует доставку уведомлений об алертах подписанными HTTP вебхуками
+type Notifier struct {
+	secret     []byte
+	httpClient *http.Client
+}
+
+// NewNotifier создает новый Notifier.
+// Соединения разрешены только с публичными адресами (alert.IsPublicAddress). Проверяется
+// IP, к которому идет подключение после разрешения имени, поэтому DNS rebinding не обходит
+// запрет. Редиректы не выполняются: иначе публичный адрес мог бы перенаправить во внутреннюю сеть
+func NewNotifier(secret string, timeout time.Duration) *Notifier {
+	return newNotifier(secret, timeout, alert.IsPublicAddress)
+}
+
+func newNotifier(secret string, timeout time.Duration, allow func(netip.Addr) bool) *Notifier {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", errForbiddenAddress, address)
+			}
+			if !allow(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", errForbiddenAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Прокси из окружения подключался бы вместо получателя и обходил проверку адреса
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Notifier{
+		secret: []byte(secret),
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Notify выполняет одну попытку доставки уведомления
+func (n *Notifier) Notify(ctx context.Context, webhookURL string, notification *alert.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: failed to create request: %v", alert.ErrDeliveryRejected, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, notification.ID.String())
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(n.secret, timestamp, body))
+
+	resp, err := n.httpClient.Do(req)
+	if errors.Is(err, errForbiddenAddress) {
+		return fmt.Errorf("%w: %v", alert.ErrDeliveryRejected, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Дочитываем тело чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	// Редирект не выполняется и при повторе будет тем же
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		return fmt.Errorf("%w: redirect status code %d", alert.ErrDeliveryRejected, resp.StatusCode)
+	}
+
+	// 4xx кроме таймаута и rate limit — получатель не примет уведомление и при повторе
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: status code %d", alert.ErrDeliveryRejected, resp.StatusCode)
+	}
+
+	return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+}
+
+// Sign вычисляет подпись тела вебхука: HMAC-SHA256 от "<timestamp>.<body>"
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp)) //nolint:errcheck // hash.Hash never returns error
+	mac.Write([]byte("."))       //nolint:errcheck // hash.Hash never returns error
+	mac.Write(body)              //nolint:errcheck // hash.Hash never returns error
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись вебхука на стороне получателя
+func Verify(secret []byte, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
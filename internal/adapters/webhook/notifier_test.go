@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+)
+
+func newNotification() *alert.Notification {
+	return &alert.Notification{
+		ID:             uuid.New(),
+		AlertID:        uuid.New(),
+		UserID:         1,
+		MarketHashName: "AK-47 | Redline (Field-Tested)",
+		Direction:      alert.DirectionBelow,
+		PriceType:      alert.PriceTypeTradable,
+		Threshold:      decimal.NewFromFloat(13),
+		Price:          decimal.NewFromFloat(12.5),
+		TriggeredAt:    time.Now().UTC(),
+	}
+}
+
+// newTestNotifier создает Notifier, которому разрешен loopback адрес httptest сервера
+func newTestNotifier(secret string) *Notifier {
+	return newNotifier(secret, time.Second, func(netip.Addr) bool { return true })
+}
+
+func TestNotifier_Notify_SignedDelivery(t *testing.T) {
+	secret := []byte("test-secret")
+	n := newNotification()
+
+	received := make(chan *alert.Notification, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read body: %v", err)
+		}
+
+		if !Verify(secret, r.Header.Get(HeaderTimestamp), body, r.Header.Get(HeaderSignature)) {
+			t.Error("expected valid signature")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get(HeaderID) != n.ID.String() {
+			t.Errorf("expected webhook id %s, got %s", n.ID, r.Header.Get(HeaderID))
+		}
+
+		var got alert.Notification
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("failed to decode notification: %v", err)
+		}
+		received <- &got
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	notifier := newTestNotifier(string(secret))
+
+	if err := notifier.Notify(context.Background(), receiver.URL, n); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := <-received
+	if got.AlertID != n.AlertID || !got.Price.Equal(n.Price) {
+		t.Errorf("unexpected notification: %+v", got)
+	}
+}
+
+func TestNotifier_Notify_StatusMapping(t *testing.T) {
+	tests := []struct {
+		status       int
+		wantErr      bool
+		wantRejected bool
+	}{
+		{http.StatusOK, false, false},
+		{http.StatusAccepted, false, false},
+		{http.StatusFound, true, true},
+		{http.StatusBadRequest, true, true},
+		{http.StatusGone, true, true},
+		{http.StatusRequestTimeout, true, false},
+		{http.StatusTooManyRequests, true, false},
+		{http.StatusInternalServerError, true, false},
+		{http.StatusServiceUnavailable, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			err := newTestNotifier("secret").Notify(context.Background(), receiver.URL, newNotification())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if errors.Is(err, alert.ErrDeliveryRejected) != tt.wantRejected {
+				t.Errorf("expected rejected=%v, got %v", tt.wantRejected, err)
+			}
+		})
+	}
+}
+
+func TestNotifier_Notify_RejectsInternalAddresses(t *testing.T) {
+	var hits atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	port := receiver.URL[strings.LastIndex(receiver.URL, ":"):]
+	targets := []string{
+		receiver.URL,
+		"http://localhost" + port,
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/hook",
+		"http://[::1]" + port,
+	}
+
+	notifier := NewNotifier("secret", time.Second)
+	for _, target := range targets {
+		t.Run(target, func(t *testing.T) {
+			err := notifier.Notify(context.Background(), target, newNotification())
+			if !errors.Is(err, alert.ErrDeliveryRejected) {
+				t.Fatalf("expected delivery to be rejected, got %v", err)
+			}
+		})
+	}
+
+	if n := hits.Load(); n != 0 {
+		t.Errorf("expected no requests to reach the receiver, got %d", n)
+	}
+}
+
+func TestNotifier_Notify_DoesNotFollowRedirects(t *testing.T) {
+	var hits atomic.Int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer internal.Close()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+	}))
+	defer receiver.Close()
+
+	err := newTestNotifier("secret").Notify(context.Background(), receiver.URL, newNotification())
+	if !errors.Is(err, alert.ErrDeliveryRejected) {
+		t.Fatalf("expected redirect to be rejected, got %v", err)
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("expected redirect not to be followed, got %d requests", n)
+	}
+}
+
+func TestVerify_RejectsTamperedBody(t *testing.T) {
+	secret := []byte("secret")
+	signature := Sign(secret, "1700000000", []byte(`{"price":"12.5"}`))
+
+	if Verify(secret, "1700000000", []byte(`{"price":"1.5"}`), signature) {
+		t.Error("expected tampered body to fail verification")
+	}
+	if Verify(secret, "1700000001", []byte(`{"price":"12.5"}`), signature) {
+		t.Error("expected different timestamp to fail verification")
+	}
+	if Verify([]byte("other"), "1700000000", []byte(`{"price":"12.5"}`), signature) {
+		t.Error("expected different secret to fail verification")
+	}
+}
@@ -0,0 +1,178 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// maxConcurrentDeliveries ограничивает количество одновременных доставок вебхуков
+const maxConcurrentDeliveries = 8
+
+// AlertServiceImpl реализует сервис ценовых алертов
+type AlertServiceImpl struct {
+	alertRepo      output.AlertRepository
+	userRepo       output.UserRepository
+	notifier       output.AlertNotifier
+	maxAttempts    int
+	initialBackoff time.Duration
+	logger         *slog.Logger
+}
+
+// NewAlertService создает новый экземпляр AlertService
+func NewAlertService(
+	alertRepo output.AlertRepository,
+	userRepo output.UserRepository,
+	notifier output.AlertNotifier,
+	maxAttempts int,
+	initialBackoff time.Duration,
+	logger *slog.Logger,
+) *AlertServiceImpl {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &AlertServiceImpl{
+		alertRepo:      alertRepo,
+		userRepo:       userRepo,
+		notifier:       notifier,
+		maxAttempts:    maxAttempts,
+		initialBackoff: initialBackoff,
+		logger:         logger,
+	}
+}
+
+// CreateAlert регистрирует ценовой алерт пользователя
+func (s *AlertServiceImpl) CreateAlert(
+	ctx context.Context,
+	userID int64,
+	req input.CreateAlertRequest,
+) (*alert.Alert, error) {
+	// Проверяем что пользователь существует
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	a, err := alert.NewAlert(userID, req.MarketHashName, req.Direction, req.PriceType, req.Threshold, req.WebhookURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.alertRepo.Save(ctx, a); err != nil {
+		return nil, fmt.Errorf("failed to save alert: %w", err)
+	}
+
+	return a, nil
+}
+
+// ListAlerts возвращает алерты пользователя
+func (s *AlertServiceImpl) ListAlerts(ctx context.Context, userID int64) ([]*alert.Alert, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.alertRepo.GetByUserID(ctx, userID)
+}
+
+// Evaluate проверяет активные алерты по свежему каталогу и рассылает уведомления.
+// Подходит как RefreshHook для ItemServiceImpl.
+func (s *AlertServiceImpl) Evaluate(ctx context.Context, items []*item.Item) {
+	alerts, err := s.alertRepo.ListActive(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list active alerts", slog.Any("error", err))
+		return
+	}
+	if len(alerts) == 0 {
+		return
+	}
+
+	byName := make(map[string]*item.Item, len(items))
+	for _, it := range items {
+		byName[it.MarketHashName] = it
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentDeliveries)
+
+	for _, a := range alerts {
+		price, triggered := a.Evaluate(byName[a.MarketHashName])
+		if !triggered {
+			continue
+		}
+
+		// Сначала деактивируем алерт, чтобы он не сработал повторно
+		now := time.Now().UTC()
+		marked, err := s.alertRepo.MarkTriggered(ctx, a.ID, now)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to mark alert triggered", slog.String("alert_id", a.ID.String()), slog.Any("error", err))
+			continue
+		}
+		if !marked {
+			continue
+		}
+		a.Trigger(now)
+
+		n := alert.NewNotification(a, price, now)
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(webhookURL string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.deliver(ctx, webhookURL, n)
+		}(a.WebhookURL)
+	}
+
+	wg.Wait()
+}
+
+// deliver доставляет уведомление с экспоненциальными повторами.
+// Если все попытки исчерпаны, уведомление сохраняется в dead-letter таблицу.
+func (s *AlertServiceImpl) deliver(ctx context.Context, webhookURL string, n *alert.Notification) {
+	backoff := s.initialBackoff
+	attempts := 0
+
+	var lastErr error
+
+retry:
+	for attempts < s.maxAttempts {
+		attempts++
+
+		lastErr = s.notifier.Notify(ctx, webhookURL, n)
+		if lastErr == nil {
+			s.logger.InfoContext(ctx, "alert notification delivered",
+				slog.String("alert_id", n.AlertID.String()),
+				slog.Int("attempts", attempts),
+			)
+			return
+		}
+
+		if errors.Is(lastErr, alert.ErrDeliveryRejected) || attempts == s.maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			lastErr = ctx.Err()
+			break retry
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	s.logger.WarnContext(ctx, "alert notification moved to dead letter",
+		slog.String("alert_id", n.AlertID.String()),
+		slog.Int("attempts", attempts),
+		slog.Any("error", lastErr),
+	)
+
+	if err := s.alertRepo.SaveDeadLetter(ctx, alert.NewDeadLetter(n, webhookURL, attempts, lastErr)); err != nil {
+		s.logger.ErrorContext(ctx, "failed to save dead letter", slog.String("alert_id", n.AlertID.String()), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,222 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/domain/user"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+type MockAlertRepository struct {
+	mu          sync.Mutex
+	alerts      []*alert.Alert
+	deadLetters []*alert.DeadLetter
+}
+
+func (m *MockAlertRepository) Save(_ context.Context, a *alert.Alert) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.alerts = append(m.alerts, a)
+	return nil
+}
+
+func (m *MockAlertRepository) GetByUserID(_ context.Context, userID int64) ([]*alert.Alert, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []*alert.Alert
+	for _, a := range m.alerts {
+		if a.UserID == userID {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockAlertRepository) ListActive(_ context.Context) ([]*alert.Alert, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []*alert.Alert
+	for _, a := range m.alerts {
+		if a.Active {
+			copied := *a
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockAlertRepository) MarkTriggered(_ context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, a := range m.alerts {
+		if a.ID == id && a.Active {
+			a.Trigger(at)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockAlertRepository) SaveDeadLetter(_ context.Context, dl *alert.DeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deadLetters = append(m.deadLetters, dl)
+	return nil
+}
+
+type MockAlertNotifier struct {
+	mu        sync.Mutex
+	errs      []error // ошибки по порядку попыток, дальше — успех
+	attempts  int
+	delivered []*alert.Notification
+}
+
+func (m *MockAlertNotifier) Notify(_ context.Context, _ string, n *alert.Notification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.attempts++
+	if m.attempts <= len(m.errs) {
+		return m.errs[m.attempts-1]
+	}
+	m.delivered = append(m.delivered, n)
+	return nil
+}
+
+func newAlertTestService(repo *MockAlertRepository, notifier *MockAlertNotifier) *AlertServiceImpl {
+	userRepo := &MockUserRepository{user: user.NewUser(1, decimal.NewFromFloat(100))}
+	return NewAlertService(repo, userRepo, notifier, 3, time.Millisecond, newTestLogger())
+}
+
+func createTestAlert(t *testing.T, service *AlertServiceImpl, direction alert.Direction, threshold float64) *alert.Alert {
+	t.Helper()
+
+	a, err := service.CreateAlert(context.Background(), 1, input.CreateAlertRequest{
+		MarketHashName: "AK-47",
+		Direction:      direction,
+		PriceType:      alert.PriceTypeTradable,
+		Threshold:      decimal.NewFromFloat(threshold),
+		WebhookURL:     "https://example.com/hook",
+	})
+	if err != nil {
+		t.Fatalf("failed to create alert: %v", err)
+	}
+	return a
+}
+
+func catalogueWithPrice(price float64) []*item.Item {
+	p := decimal.NewFromFloat(price)
+	return []*item.Item{{MarketHashName: "AK-47", TradableMinPrice: &p}}
+}
+
+func TestAlertService_CreateAlert_UserNotFound(t *testing.T) {
+	userRepo := &MockUserRepository{getUserErr: user.ErrUserNotFound}
+	service := NewAlertService(&MockAlertRepository{}, userRepo, &MockAlertNotifier{}, 3, time.Millisecond, newTestLogger())
+
+	_, err := service.CreateAlert(context.Background(), 999, input.CreateAlertRequest{
+		MarketHashName: "AK-47",
+		Direction:      alert.DirectionBelow,
+		PriceType:      alert.PriceTypeTradable,
+		Threshold:      decimal.NewFromFloat(10),
+		WebhookURL:     "https://example.com/hook",
+	})
+	if !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestAlertService_Evaluate_DeliversOnce(t *testing.T) {
+	repo := &MockAlertRepository{}
+	notifier := &MockAlertNotifier{}
+	service := newAlertTestService(repo, notifier)
+
+	below := createTestAlert(t, service, alert.DirectionBelow, 13)
+	createTestAlert(t, service, alert.DirectionAbove, 13)
+
+	service.Evaluate(context.Background(), catalogueWithPrice(12.5))
+
+	if len(notifier.delivered) != 1 {
+		t.Fatalf("expected 1 delivered notification, got %d", len(notifier.delivered))
+	}
+	if notifier.delivered[0].AlertID != below.ID || !notifier.delivered[0].Price.Equal(decimal.NewFromFloat(12.5)) {
+		t.Errorf("unexpected notification: %+v", notifier.delivered[0])
+	}
+
+	// Сработавший алерт не должен срабатывать повторно
+	service.Evaluate(context.Background(), catalogueWithPrice(12))
+
+	if len(notifier.delivered) != 1 {
+		t.Errorf("expected alert to fire once, got %d deliveries", len(notifier.delivered))
+	}
+}
+
+func TestAlertService_Evaluate_RetriesTransientErrors(t *testing.T) {
+	repo := &MockAlertRepository{}
+	notifier := &MockAlertNotifier{errs: []error{errors.New("timeout"), errors.New("503")}}
+	service := newAlertTestService(repo, notifier)
+
+	createTestAlert(t, service, alert.DirectionBelow, 13)
+
+	service.Evaluate(context.Background(), catalogueWithPrice(12.5))
+
+	if notifier.attempts != 3 || len(notifier.delivered) != 1 {
+		t.Errorf("expected delivery on 3rd attempt, got %d attempts, %d delivered", notifier.attempts, len(notifier.delivered))
+	}
+	if len(repo.deadLetters) != 0 {
+		t.Errorf("expected no dead letters, got %d", len(repo.deadLetters))
+	}
+}
+
+func TestAlertService_Evaluate_DeadLettersAfterMaxAttempts(t *testing.T) {
+	repo := &MockAlertRepository{}
+	failure := errors.New("connection refused")
+	notifier := &MockAlertNotifier{errs: []error{failure, failure, failure, failure}}
+	service := newAlertTestService(repo, notifier)
+
+	a := createTestAlert(t, service, alert.DirectionBelow, 13)
+
+	service.Evaluate(context.Background(), catalogueWithPrice(12.5))
+
+	if notifier.attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", notifier.attempts)
+	}
+	if len(repo.deadLetters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(repo.deadLetters))
+	}
+
+	dl := repo.deadLetters[0]
+	if dl.Notification.AlertID != a.ID || dl.Attempts != 3 || dl.LastError != failure.Error() {
+		t.Errorf("unexpected dead letter: %+v", dl)
+	}
+}
+
+func TestAlertService_Evaluate_RejectedIsNotRetried(t *testing.T) {
+	repo := &MockAlertRepository{}
+	notifier := &MockAlertNotifier{errs: []error{alert.ErrDeliveryRejected}}
+	service := newAlertTestService(repo, notifier)
+
+	createTestAlert(t, service, alert.DirectionBelow, 13)
+
+	service.Evaluate(context.Background(), catalogueWithPrice(12.5))
+
+	if notifier.attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", notifier.attempts)
+	}
+	if len(repo.deadLetters) != 1 {
+		t.Errorf("expected 1 dead letter, got %d", len(repo.deadLetters))
+	}
+}
@@ -2,19 +2,47 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/akonovalovdev/DDD_example/internal/domain/transaction"
+	"github.com/akonovalovdev/DDD_example/internal/domain/user"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/tracing"
 	"github.com/akonovalovdev/DDD_example/internal/ports/input"
 	"github.com/akonovalovdev/DDD_example/internal/ports/output"
 )
 
+// Исходы списания, по которым ведутся счетчики
+const (
+	WithdrawSuccess             = "success"
+	WithdrawInsufficientBalance = "insufficient_balance"
+	WithdrawUserNotFound        = "user_not_found"
+	WithdrawInvalidAmount       = "invalid_amount"
+	WithdrawAccountFrozen       = "account_frozen"
+	WithdrawError               = "error"
+)
+
+var withdrawOutcomes = []string{
+	WithdrawSuccess,
+	WithdrawInsufficientBalance,
+	WithdrawUserNotFound,
+	WithdrawInvalidAmount,
+	WithdrawAccountFrozen,
+	WithdrawError,
+}
+
 // BalanceServiceImpl реализует сервис для работы с балансом
 type BalanceServiceImpl struct {
 	userRepo        output.UserRepository
 	transactionRepo output.TransactionRepository
+
+	// withdrawals счетчики списаний по исходу; набор ключей неизменен после создания
+	withdrawals map[string]*atomic.Uint64
 }
 
 // NewBalanceService создает новый экземпляр BalanceService
@@ -22,9 +50,42 @@ func NewBalanceService(
 	userRepo output.UserRepository,
 	transactionRepo output.TransactionRepository,
 ) *BalanceServiceImpl {
+	withdrawals := make(map[string]*atomic.Uint64, len(withdrawOutcomes))
+	for _, outcome := range withdrawOutcomes {
+		withdrawals[outcome] = new(atomic.Uint64)
+	}
+
 	return &BalanceServiceImpl{
 		userRepo:        userRepo,
 		transactionRepo: transactionRepo,
+		withdrawals:     withdrawals,
+	}
+}
+
+// WithdrawStats возвращает количество списаний по исходу
+func (s *BalanceServiceImpl) WithdrawStats() map[string]uint64 {
+	stats := make(map[string]uint64, len(s.withdrawals))
+	for outcome, n := range s.withdrawals {
+		stats[outcome] = n.Load()
+	}
+	return stats
+}
+
+// withdrawOutcome классифицирует результат списания
+func withdrawOutcome(err error) string {
+	switch {
+	case err == nil:
+		return WithdrawSuccess
+	case errors.Is(err, user.ErrInsufficientBalance):
+		return WithdrawInsufficientBalance
+	case errors.Is(err, user.ErrUserNotFound):
+		return WithdrawUserNotFound
+	case errors.Is(err, user.ErrInvalidAmount):
+		return WithdrawInvalidAmount
+	case errors.Is(err, user.ErrAccountFrozen):
+		return WithdrawAccountFrozen
+	default:
+		return WithdrawError
 	}
 }
 
@@ -34,6 +95,61 @@ func (s *BalanceServiceImpl) WithdrawBalance(
 	userID int64,
 	amount decimal.Decimal,
 ) (*input.WithdrawResult, error) {
+	ctx, span := tracing.Start(ctx, "BalanceServiceImpl.WithdrawBalance", trace.WithAttributes(
+		attribute.Int64("user.id", userID),
+		attribute.String("amount", amount.String()),
+	))
+	defer span.End()
+
+	txRecord, err := s.changeBalance(ctx, userID, amount, (*user.User).Withdraw, transaction.NewWithdrawTransaction)
+	outcome := withdrawOutcome(err)
+	s.withdrawals[outcome].Add(1)
+	span.SetAttributes(attribute.String("withdraw.result", outcome))
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	return &input.WithdrawResult{
+		Transaction:   txRecord,
+		BalanceBefore: txRecord.BalanceBefore,
+		BalanceAfter:  txRecord.BalanceAfter,
+	}, nil
+}
+
+// DepositBalance зачисляет средства на баланс пользователя
+func (s *BalanceServiceImpl) DepositBalance(
+	ctx context.Context,
+	userID int64,
+	amount decimal.Decimal,
+) (*input.DepositResult, error) {
+	ctx, span := tracing.Start(ctx, "BalanceServiceImpl.DepositBalance", trace.WithAttributes(
+		attribute.Int64("user.id", userID),
+		attribute.String("amount", amount.String()),
+	))
+	defer span.End()
+
+	txRecord, err := s.changeBalance(ctx, userID, amount, (*user.User).Deposit, transaction.NewDepositTransaction)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	return &input.DepositResult{
+		Transaction:   txRecord,
+		BalanceBefore: txRecord.BalanceBefore,
+		BalanceAfter:  txRecord.BalanceAfter,
+	}, nil
+}
+
+// changeBalance применяет операцию к балансу в одной транзакции БД с записью истории
+func (s *BalanceServiceImpl) changeBalance(
+	ctx context.Context,
+	userID int64,
+	amount decimal.Decimal,
+	apply func(u *user.User, amount decimal.Decimal) (decimal.Decimal, error),
+	newRecord func(userID int64, amount, balanceBefore, balanceAfter decimal.Decimal) *transaction.Transaction,
+) (*transaction.Transaction, error) {
 	// 1. Начинаем транзакцию БД
 	tx, err := s.userRepo.BeginTx(ctx)
 	if err != nil {
@@ -46,24 +162,19 @@ func (s *BalanceServiceImpl) WithdrawBalance(
 	}()
 
 	// 2. Получаем пользователя с блокировкой (SELECT ... FOR UPDATE)
-	user, err := s.userRepo.GetByIDForUpdate(ctx, tx, userID)
+	u, err := s.userRepo.GetByIDForUpdate(ctx, tx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// 3. Выполняем domain логику — списание
-	balanceBefore, err := user.Withdraw(amount)
+	// 3. Выполняем domain логику — списание или зачисление
+	balanceBefore, err := apply(u, amount)
 	if err != nil {
 		return nil, err
 	}
 
 	// 4. Создаем запись истории транзакции
-	txRecord := transaction.NewWithdrawTransaction(
-		userID,
-		amount,
-		balanceBefore,
-		user.Balance,
-	)
+	txRecord := newRecord(userID, amount, balanceBefore, u.Balance)
 
 	// 5. Сохраняем транзакцию в историю
 	if err = s.transactionRepo.Save(ctx, tx, txRecord); err != nil {
@@ -71,7 +182,7 @@ func (s *BalanceServiceImpl) WithdrawBalance(
 	}
 
 	// 6. Обновляем баланс пользователя
-	if err = s.userRepo.UpdateBalance(ctx, tx, userID, user.Balance); err != nil {
+	if err = s.userRepo.UpdateBalance(ctx, tx, userID, u.Balance); err != nil {
 		return nil, fmt.Errorf("failed to update balance: %w", err)
 	}
 
@@ -80,11 +191,7 @@ func (s *BalanceServiceImpl) WithdrawBalance(
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return &input.WithdrawResult{
-		Transaction:   txRecord,
-		BalanceBefore: balanceBefore,
-		BalanceAfter:  user.Balance,
-	}, nil
+	return txRecord, nil
 }
 
 // GetBalance возвращает текущий баланс пользователя
@@ -95,3 +202,13 @@ func (s *BalanceServiceImpl) GetBalance(ctx context.Context, userID int64) (deci
 	}
 	return user.Balance, nil
 }
+
+// FreezeAccount замораживает счет пользователя
+func (s *BalanceServiceImpl) FreezeAccount(ctx context.Context, userID int64) error {
+	return s.userRepo.SetFrozen(ctx, userID, true)
+}
+
+// UnfreezeAccount размораживает счет пользователя
+func (s *BalanceServiceImpl) UnfreezeAccount(ctx context.Context, userID int64) error {
+	return s.userRepo.SetFrozen(ctx, userID, false)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/shopspring/decimal"
@@ -37,6 +38,14 @@ func (m *MockUserRepository) UpdateBalance(_ context.Context, _ *sql.Tx, _ int64
 	return m.updateErr
 }
 
+func (m *MockUserRepository) SetFrozen(_ context.Context, _ int64, frozen bool) error {
+	if m.getUserErr != nil {
+		return m.getUserErr
+	}
+	m.user.Frozen = frozen
+	return nil
+}
+
 func (m *MockUserRepository) BeginTx(_ context.Context) (*sql.Tx, error) {
 	if m.beginTxErr != nil {
 		return nil, m.beginTxErr
@@ -111,4 +120,60 @@ func TestBalanceService_WithdrawBalance_BeginTxError(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
+	if stats := service.WithdrawStats(); stats[WithdrawError] != 1 || stats[WithdrawSuccess] != 0 {
+		t.Errorf("expected one failed withdraw, got %v", stats)
+	}
+}
+
+func TestWithdrawOutcome(t *testing.T) {
+	tests := map[error]string{
+		nil: WithdrawSuccess,
+		fmt.Errorf("wrap: %w", user.ErrUserNotFound): WithdrawUserNotFound,
+		user.ErrInsufficientBalance:                  WithdrawInsufficientBalance,
+		user.ErrInvalidAmount:                        WithdrawInvalidAmount,
+		user.ErrAccountFrozen:                        WithdrawAccountFrozen,
+		errors.New("connection reset"):               WithdrawError,
+	}
+	for err, want := range tests {
+		if got := withdrawOutcome(err); got != want {
+			t.Errorf("withdrawOutcome(%v) = %q, want %q", err, got, want)
+		}
+	}
+}
+
+func TestBalanceService_FreezeAccount(t *testing.T) {
+	userRepo := &MockUserRepository{user: user.NewUser(1, decimal.NewFromFloat(100.00))}
+	service := NewBalanceService(userRepo, &MockTransactionRepository{})
+
+	if err := service.FreezeAccount(context.Background(), 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !userRepo.user.Frozen {
+		t.Error("expected account to be frozen")
+	}
+
+	if err := service.UnfreezeAccount(context.Background(), 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if userRepo.user.Frozen {
+		t.Error("expected account to be unfrozen")
+	}
+}
+
+func TestBalanceService_FreezeAccount_UserNotFound(t *testing.T) {
+	userRepo := &MockUserRepository{getUserErr: user.ErrUserNotFound}
+	service := NewBalanceService(userRepo, &MockTransactionRepository{})
+
+	if err := service.FreezeAccount(context.Background(), 1); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestBalanceService_DepositBalance_BeginTxError(t *testing.T) {
+	userRepo := &MockUserRepository{beginTxErr: errors.New("connection refused")}
+	service := NewBalanceService(userRepo, &MockTransactionRepository{})
+
+	if _, err := service.DepositBalance(context.Background(), 1, decimal.NewFromFloat(10.00)); err == nil {
+		t.Error("expected error, got nil")
+	}
 }
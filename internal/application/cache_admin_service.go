@@ -0,0 +1,109 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/audit"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// CatalogueRefresher принудительно обновляет каталог (реализует ItemServiceImpl)
+type CatalogueRefresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// CacheAdminServiceImpl реализует администрирование кэша с записью в журнал аудита
+type CacheAdminServiceImpl struct {
+	cache     output.Cache
+	catalogue CatalogueRefresher
+	auditLog  output.AuditLog
+	logger    *slog.Logger
+}
+
+// NewCacheAdminService создает новый экземпляр CacheAdminService
+func NewCacheAdminService(
+	cache output.Cache,
+	catalogue CatalogueRefresher,
+	auditLog output.AuditLog,
+	logger *slog.Logger,
+) *CacheAdminServiceImpl {
+	return &CacheAdminServiceImpl{
+		cache:     cache,
+		catalogue: catalogue,
+		auditLog:  auditLog,
+		logger:    logger,
+	}
+}
+
+// ListEntries возвращает ключи кэша, отсортированные по имени
+func (s *CacheAdminServiceImpl) ListEntries(ctx context.Context, actor input.AdminActor) ([]input.CacheEntry, error) {
+	inspector, ok := s.cache.(output.CacheInspector)
+	if !ok {
+		s.record(ctx, actor, audit.ActionCacheInspect, "", input.ErrCacheInspectionUnsupported)
+		return nil, input.ErrCacheInspectionUnsupported
+	}
+
+	infos, err := inspector.Entries(ctx)
+	s.record(ctx, actor, audit.ActionCacheInspect, "", err)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]input.CacheEntry, 0, len(infos))
+	for _, info := range infos {
+		expiresIn := float64(-1)
+		if info.ExpiresIn >= 0 {
+			expiresIn = math.Round(info.ExpiresIn.Seconds()*1000) / 1000
+		}
+		entries = append(entries, input.CacheEntry{
+			Key:              info.Key,
+			ExpiresInSeconds: expiresIn,
+			SizeBytes:        info.SizeBytes,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	return entries, nil
+}
+
+// RefreshCatalogue принудительно обновляет каталог из Skinport
+func (s *CacheAdminServiceImpl) RefreshCatalogue(ctx context.Context, actor input.AdminActor) error {
+	err := s.catalogue.Refresh(ctx)
+	s.record(ctx, actor, audit.ActionCacheRefresh, itemsCacheKey, err)
+	return err
+}
+
+// DeleteKey удаляет ключ из кэша
+func (s *CacheAdminServiceImpl) DeleteKey(ctx context.Context, actor input.AdminActor, key string) error {
+	s.cache.Delete(ctx, key)
+	s.record(ctx, actor, audit.ActionCacheDelete, key, nil)
+	return nil
+}
+
+// Clear очищает кэш целиком
+func (s *CacheAdminServiceImpl) Clear(ctx context.Context, actor input.AdminActor) error {
+	s.cache.Clear(ctx)
+	s.record(ctx, actor, audit.ActionCacheClear, "", nil)
+	return nil
+}
+
+// record пишет действие в журнал аудита. Действие к этому моменту уже выполнено,
+// поэтому ошибка записи не отменяет его, а только логируется.
+func (s *CacheAdminServiceImpl) record(ctx context.Context, actor input.AdminActor, action audit.Action, target string, actionErr error) {
+	entry := audit.NewEntry(actor.Name, action, target, actor.RemoteAddr, actionErr)
+
+	// Запись не должна теряться, если клиент оборвал запрос
+	if err := s.auditLog.Record(context.WithoutCancel(ctx), entry); err != nil {
+		s.logger.ErrorContext(ctx, "failed to write audit log",
+			slog.String("action", string(action)),
+			slog.String("actor", actor.Name),
+			slog.String("target", target),
+			slog.Any("error", err),
+		)
+	}
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/audit"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+type mockAuditLog struct {
+	entries []*audit.Entry
+	err     error
+}
+
+func (m *mockAuditLog) Record(_ context.Context, entry *audit.Entry) error {
+	m.entries = append(m.entries, entry)
+	return m.err
+}
+
+type mockRefresher struct {
+	err   error
+	calls int
+}
+
+func (m *mockRefresher) Refresh(context.Context) error {
+	m.calls++
+	return m.err
+}
+
+// inspectableCache MockCache с поддержкой просмотра содержимого
+type inspectableCache struct {
+	*MockCache
+}
+
+func (c inspectableCache) Entries(context.Context) ([]output.CacheEntryInfo, error) {
+	return []output.CacheEntryInfo{
+		{Key: "skinport:sales_history", ExpiresIn: 1500 * time.Millisecond, SizeBytes: 10},
+		{Key: "skinport:items", ExpiresIn: -1, SizeBytes: 20},
+	}, nil
+}
+
+var testActor = input.AdminActor{Name: "admin", RemoteAddr: "10.0.0.1:5000"}
+
+func TestCacheAdminService_ListEntries(t *testing.T) {
+	auditLog := &mockAuditLog{}
+	service := NewCacheAdminService(inspectableCache{NewMockCache()}, &mockRefresher{}, auditLog, newTestLogger())
+
+	entries, err := service.ListEntries(context.Background(), testActor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Key != "skinport:items" || entries[1].Key != "skinport:sales_history" {
+		t.Fatalf("expected entries sorted by key, got %+v", entries)
+	}
+	if entries[0].ExpiresInSeconds != -1 || entries[1].ExpiresInSeconds != 1.5 {
+		t.Errorf("unexpected ttl: %+v", entries)
+	}
+	if len(auditLog.entries) != 1 || auditLog.entries[0].Action != audit.ActionCacheInspect {
+		t.Errorf("expected inspect to be audited, got %+v", auditLog.entries)
+	}
+}
+
+func TestCacheAdminService_ListEntries_Unsupported(t *testing.T) {
+	auditLog := &mockAuditLog{}
+	service := NewCacheAdminService(NewMockCache(), &mockRefresher{}, auditLog, newTestLogger())
+
+	_, err := service.ListEntries(context.Background(), testActor)
+	if !errors.Is(err, input.ErrCacheInspectionUnsupported) {
+		t.Fatalf("expected ErrCacheInspectionUnsupported, got %v", err)
+	}
+	if len(auditLog.entries) != 1 || auditLog.entries[0].Outcome != audit.OutcomeFailure {
+		t.Errorf("expected failed inspect to be audited, got %+v", auditLog.entries)
+	}
+}
+
+func TestCacheAdminService_MutationsAreAudited(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMockCache()
+	cache.Set(ctx, "skinport:items", "items", time.Minute)
+	cache.Set(ctx, "skinport:sales_history", "history", time.Minute)
+
+	refresher := &mockRefresher{err: errors.New("skinport unavailable")}
+	// Ошибка записи аудита не должна ломать само действие
+	auditLog := &mockAuditLog{err: errors.New("db is down")}
+	service := NewCacheAdminService(cache, refresher, auditLog, newTestLogger())
+
+	if err := service.RefreshCatalogue(ctx, testActor); err == nil {
+		t.Error("expected refresh error to be returned")
+	}
+	if err := service.DeleteKey(ctx, testActor, "skinport:items"); err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+	if _, ok := cache.Get(ctx, "skinport:items"); ok {
+		t.Error("expected key to be deleted")
+	}
+	if err := service.Clear(ctx, testActor); err != nil {
+		t.Fatalf("unexpected clear error: %v", err)
+	}
+	if _, ok := cache.Get(ctx, "skinport:sales_history"); ok {
+		t.Error("expected cache to be cleared")
+	}
+
+	want := []struct {
+		action  audit.Action
+		target  string
+		outcome audit.Outcome
+	}{
+		{audit.ActionCacheRefresh, "skinport:items", audit.OutcomeFailure},
+		{audit.ActionCacheDelete, "skinport:items", audit.OutcomeSuccess},
+		{audit.ActionCacheClear, "", audit.OutcomeSuccess},
+	}
+	if len(auditLog.entries) != len(want) {
+		t.Fatalf("expected %d audit entries, got %d", len(want), len(auditLog.entries))
+	}
+	for i, w := range want {
+		got := auditLog.entries[i]
+		if got.Action != w.action || got.Target != w.target || got.Outcome != w.outcome {
+			t.Errorf("entry %d: expected %s/%q/%s, got %s/%q/%s", i, w.action, w.target, w.outcome, got.Action, got.Target, got.Outcome)
+		}
+		if got.Actor != testActor.Name || got.RemoteAddr != testActor.RemoteAddr {
+			t.Errorf("entry %d: unexpected actor %q from %q", i, got.Actor, got.RemoteAddr)
+		}
+	}
+}
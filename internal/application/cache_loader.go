@@ -0,0 +1,203 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/akonovalovdev/DDD_example/internal/pkg/tracing"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// LoadFunc загружает значение из источника при промахе кэша
+type LoadFunc[V any] func(ctx context.Context) (V, error)
+
+// StoreFunc кладет загруженное значение в кэш
+type StoreFunc[K comparable, V any] func(ctx context.Context, key K, value V)
+
+// StaleFunc возвращает устаревшее значение, которое можно отдать, пока загрузку
+// выполняет другой экземпляр
+type StaleFunc[K comparable, V any] func(ctx context.Context, key K) (V, bool)
+
+// lockPollInterval как часто экземпляр, ожидающий чужую загрузку, проверяет кэш
+const lockPollInterval = 200 * time.Millisecond
+
+// CacheLoader реализует cache-aside с защитой от thundering herd: параллельные промахи
+// по одному ключу выполняют одну загрузку, остальные ждут ее результат.
+// С распределенной блокировкой то же верно и между репликами, делящими общий кэш.
+type CacheLoader[K comparable, V any] struct {
+	cache   output.TypedCache[K, V]
+	store   StoreFunc[K, V]
+	sfGroup singleflight.Group
+	loads   atomic.Uint64
+	shared  atomic.Uint64
+
+	locker       output.Locker
+	lockWait     time.Duration
+	pollInterval time.Duration
+	stale        StaleFunc[K, V]
+}
+
+// NewCacheLoader создает загрузчик, сохраняющий значения в кэш с заданным TTL
+func NewCacheLoader[K comparable, V any](cache output.TypedCache[K, V], ttl time.Duration) *CacheLoader[K, V] {
+	return NewCacheLoaderWithStore(cache, func(ctx context.Context, key K, value V) {
+		cache.Set(ctx, key, value, ttl)
+	})
+}
+
+// NewCacheLoaderWithStore создает загрузчик с собственной записью в кэш — например,
+// когда запись должна быть атомарной вместе с другим состоянием сервиса
+func NewCacheLoaderWithStore[K comparable, V any](cache output.TypedCache[K, V], store StoreFunc[K, V]) *CacheLoader[K, V] {
+	return &CacheLoader[K, V]{cache: cache, store: store, pollInterval: lockPollInterval}
+}
+
+// SetLocker включает распределенную блокировку загрузки: источник опрашивает только
+// экземпляр, захвативший блокировку, остальные ждут появления значения в общем кэше.
+// Если за wait значение не появилось (лидер завис), экземпляр загружает его сам.
+// Вызывается при инициализации, до первого обращения.
+func (l *CacheLoader[K, V]) SetLocker(locker output.Locker, wait time.Duration) {
+	l.locker = locker
+	l.lockWait = wait
+}
+
+// SetStale задает источник устаревшего значения: пока загрузку выполняет другой
+// экземпляр, оно отдается сразу, без ожидания
+func (l *CacheLoader[K, V]) SetStale(stale StaleFunc[K, V]) {
+	l.stale = stale
+}
+
+// GetOrLoad возвращает значение из кэша, а при промахе загружает и сохраняет его
+func (l *CacheLoader[K, V]) GetOrLoad(ctx context.Context, key K, load LoadFunc[V]) (V, error) {
+	// 1. Проверяем кэш
+	if value, ok := l.cache.Get(ctx, key); ok {
+		return value, nil
+	}
+
+	// 2. Singleflight — дедупликация параллельных запросов
+	return l.do(ctx, key, func(ctx context.Context) (V, error) {
+		// Повторная проверка кеша (мог заполниться пока ждали)
+		if value, ok := l.cache.Get(ctx, key); ok {
+			return value, nil
+		}
+		return l.loadExclusive(ctx, key, load, false)
+	})
+}
+
+// Reload загружает значение в обход кэша и заменяет им закешированное.
+// Параллельные GetOrLoad по тому же ключу присоединяются к этой загрузке.
+func (l *CacheLoader[K, V]) Reload(ctx context.Context, key K, load LoadFunc[V]) (V, error) {
+	return l.do(ctx, key, func(ctx context.Context) (V, error) {
+		return l.loadExclusive(ctx, key, load, true)
+	})
+}
+
+// loadExclusive загружает значение под распределенной блокировкой, если она настроена.
+// force — принудительное обновление: если блокировку держит другой экземпляр, он уже
+// обновляет значение, и достаточно вернуть текущее из кэша.
+func (l *CacheLoader[K, V]) loadExclusive(ctx context.Context, key K, load LoadFunc[V], force bool) (V, error) {
+	if l.locker == nil {
+		return l.loadAndStore(ctx, key, load)
+	}
+
+	name := fmt.Sprintf("cache-load:%v", key)
+	deadline := time.Now().Add(l.lockWait)
+	waited := false
+
+	for {
+		lock, acquired, err := l.locker.TryLock(ctx, name)
+		if err != nil {
+			// Блокировка недоступна — свежие данные важнее экономии запросов к источнику
+			return l.loadAndStore(ctx, key, load)
+		}
+
+		if acquired {
+			defer lock.Release(context.WithoutCancel(ctx)) //nolint:errcheck // session lock is dropped with the connection anyway
+
+			// Пока ждали, предыдущий лидер мог заполнить кэш
+			if waited {
+				if value, ok := l.cache.Get(ctx, key); ok {
+					return value, nil
+				}
+			}
+			return l.loadAndStore(ctx, key, load)
+		}
+
+		if force {
+			if value, ok := l.cache.Get(ctx, key); ok {
+				return value, nil
+			}
+		}
+		if l.stale != nil {
+			if value, ok := l.stale(ctx, key); ok {
+				return value, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return l.loadAndStore(ctx, key, load)
+		}
+
+		select {
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		case <-time.After(l.pollInterval):
+		}
+		waited = true
+
+		if value, ok := l.cache.Get(ctx, key); ok {
+			return value, nil
+		}
+	}
+}
+
+func (l *CacheLoader[K, V]) loadAndStore(ctx context.Context, key K, load LoadFunc[V]) (V, error) {
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	l.store(ctx, key, value)
+	return value, nil
+}
+
+// LoaderStats счетчики загрузчика
+type LoaderStats struct {
+	// Loads сколько раз загрузка выполнялась
+	Loads uint64
+	// Shared сколько вызовов получили результат чужой загрузки вместо своей
+	Shared uint64
+}
+
+// Stats возвращает счетчики загрузок и вызовов, присоединившихся к чужой загрузке
+func (l *CacheLoader[K, V]) Stats() LoaderStats {
+	return LoaderStats{Loads: l.loads.Load(), Shared: l.shared.Load()}
+}
+
+// do выполняет fn через singleflight. Загрузку выполняет первый вызов со своим контекстом,
+// поэтому в трассе она видна только под его span'ом; остальные отмечены singleflight.shared.
+func (l *CacheLoader[K, V]) do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (V, error) {
+	ctx, span := tracing.Start(ctx, "CacheLoader.singleflight", trace.WithAttributes(
+		attribute.String("cache.key", fmt.Sprint(key)),
+	))
+	defer span.End()
+
+	ran := false
+	result, err, _ := l.sfGroup.Do(fmt.Sprint(key), func() (interface{}, error) {
+		ran = true
+		l.loads.Add(1)
+		return fn(ctx)
+	})
+	if !ran {
+		l.shared.Add(1)
+	}
+	span.SetAttributes(attribute.Bool("singleflight.shared", !ran))
+	tracing.RecordError(span, err)
+	// Проверка без паники: результат может быть nil, если V — интерфейс
+	value, _ := result.(V)
+	return value, err
+}
@@ -0,0 +1,259 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/cache"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+func TestCacheLoader_GetOrLoad_DeduplicatesConcurrentMisses(t *testing.T) {
+	memory := cache.NewMemory(cache.MemoryOptions[string, int]{})
+	defer memory.Close()
+	loader := NewCacheLoader[string, int](memory, time.Minute)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = loader.GetOrLoad(context.Background(), "answer", load)
+		}(i)
+	}
+
+	// Даем горутинам встать в ожидание одной загрузки
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected 1 load, got %d", n)
+	}
+	if stats := loader.Stats(); stats != (LoaderStats{Loads: 1, Shared: 9}) {
+		t.Errorf("expected 1 load shared by 9 callers, got %+v", stats)
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("result %d: expected 42, got %d", i, v)
+		}
+	}
+
+	// Значение закешировано — повторная загрузка не нужна
+	if v, _ := loader.GetOrLoad(context.Background(), "answer", load); v != 42 || calls.Load() != 1 {
+		t.Errorf("expected cached value without load, got %d after %d loads", v, calls.Load())
+	}
+}
+
+func TestCacheLoader_ErrorIsNotCached(t *testing.T) {
+	memory := cache.NewMemory(cache.MemoryOptions[string, string]{})
+	defer memory.Close()
+	loader := NewCacheLoader[string, string](memory, time.Minute)
+
+	errBoom := errors.New("boom")
+	if _, err := loader.GetOrLoad(context.Background(), "k", func(ctx context.Context) (string, error) {
+		return "", errBoom
+	}); !errors.Is(err, errBoom) {
+		t.Fatalf("expected load error, got %v", err)
+	}
+
+	v, err := loader.GetOrLoad(context.Background(), "k", func(ctx context.Context) (string, error) {
+		return "ok", nil
+	})
+	if err != nil || v != "ok" {
+		t.Errorf("expected retry after error to load value, got %q, %v", v, err)
+	}
+}
+
+func TestCacheLoader_Reload(t *testing.T) {
+	memory := cache.NewMemory(cache.MemoryOptions[string, string]{})
+	defer memory.Close()
+	loader := NewCacheLoader[string, string](memory, time.Minute)
+	ctx := context.Background()
+
+	memory.Set(ctx, "k", "stale", time.Minute)
+
+	v, err := loader.Reload(ctx, "k", func(ctx context.Context) (string, error) { return "fresh", nil })
+	if err != nil || v != "fresh" {
+		t.Fatalf("expected fresh value, got %q, %v", v, err)
+	}
+	if cached, _ := memory.Get(ctx, "k"); cached != "fresh" {
+		t.Errorf("expected reload to replace cached value, got %q", cached)
+	}
+}
+
+// MockLocker распределенная блокировка в памяти, общая для "реплик" в тесте
+type MockLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+	err  error
+}
+
+func NewMockLocker() *MockLocker {
+	return &MockLocker{held: make(map[string]bool)}
+}
+
+func (m *MockLocker) TryLock(ctx context.Context, name string) (output.Lock, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	if m.held[name] {
+		return nil, false, nil
+	}
+	m.held[name] = true
+	return mockLock{m, name}, true, nil
+}
+
+func (m *MockLocker) isHeld(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.held[name]
+}
+
+type mockLock struct {
+	locker *MockLocker
+	name   string
+}
+
+func (l mockLock) Release(ctx context.Context) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+	delete(l.locker.held, l.name)
+	return nil
+}
+
+// countingFetcher считает обращения к источнику
+type countingFetcher struct {
+	items []*item.Item
+	calls atomic.Int32
+}
+
+func (f *countingFetcher) FetchItems(ctx context.Context) ([]*item.Item, error) {
+	f.calls.Add(1)
+	return f.items, nil
+}
+
+func TestItemService_Locker_FollowerWaitsForLeader(t *testing.T) {
+	shared := cache.NewInMemoryCache(time.Minute)
+	defer shared.Close()
+	locker := NewMockLocker()
+
+	fetcher := &countingFetcher{items: []*item.Item{{MarketHashName: "AK-47"}}}
+	follower := NewItemService(fetcher, itemsCache(shared), 5*time.Minute)
+	follower.SetLocker(locker, time.Second)
+	follower.loader.pollInterval = 5 * time.Millisecond
+
+	ctx := context.Background()
+
+	// Другая реплика захватила блокировку и обновляет каталог
+	leaderLock, _, _ := locker.TryLock(ctx, "cache-load:skinport:items")
+
+	done := make(chan []*item.Item)
+	go func() {
+		items, _ := follower.GetItems(ctx)
+		done <- items
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	leaderItems := []*item.Item{{MarketHashName: "AWP"}}
+	shared.Set(ctx, "skinport:items", mustCachedCatalogue(t, leaderItems), time.Minute)
+	leaderLock.Release(ctx)
+
+	select {
+	case items := <-done:
+		if len(items) != 1 || items[0].MarketHashName != "AWP" {
+			t.Errorf("expected leader's catalogue, got %+v", items)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("follower did not pick up leader's catalogue")
+	}
+	if n := fetcher.calls.Load(); n != 0 {
+		t.Errorf("expected follower not to call source, got %d calls", n)
+	}
+}
+
+func TestItemService_Locker_ServesStaleWhileLeaderRefreshes(t *testing.T) {
+	shared := cache.NewInMemoryCache(time.Minute)
+	defer shared.Close()
+	locker := NewMockLocker()
+
+	fetcher := &countingFetcher{items: []*item.Item{{MarketHashName: "AK-47"}}}
+	service := NewItemService(fetcher, itemsCache(shared), 5*time.Minute)
+	service.SetLocker(locker, time.Minute)
+
+	ctx := context.Background()
+
+	if _, err := service.GetItems(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if locker.isHeld("cache-load:skinport:items") {
+		t.Error("expected lock to be released after load")
+	}
+
+	// Кеш истек, обновление выполняет другая реплика — отдаем прошлый каталог без ожидания
+	shared.Clear(ctx)
+	locker.TryLock(ctx, "cache-load:skinport:items")
+
+	start := time.Now()
+	items, err := service.GetItems(ctx)
+	if err != nil || len(items) != 1 || items[0].MarketHashName != "AK-47" {
+		t.Errorf("expected stale catalogue, got %+v, %v", items, err)
+	}
+	if time.Since(start) > 100*time.Millisecond {
+		t.Error("expected stale catalogue to be served without waiting")
+	}
+	if n := fetcher.calls.Load(); n != 1 {
+		t.Errorf("expected single source call, got %d", n)
+	}
+}
+
+func TestItemService_Locker_FallbackToSource(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("leader timeout", func(t *testing.T) {
+		locker := NewMockLocker()
+		locker.TryLock(ctx, "cache-load:skinport:items")
+
+		fetcher := &countingFetcher{items: []*item.Item{{MarketHashName: "AK-47"}}}
+		service := NewItemService(fetcher, itemsCache(NewMockCache()), 5*time.Minute)
+		service.SetLocker(locker, 20*time.Millisecond)
+		service.loader.pollInterval = 5 * time.Millisecond
+
+		if items, err := service.GetItems(ctx); err != nil || len(items) != 1 {
+			t.Errorf("expected catalogue from source after wait timeout, got %+v, %v", items, err)
+		}
+		if n := fetcher.calls.Load(); n != 1 {
+			t.Errorf("expected source call after timeout, got %d", n)
+		}
+	})
+
+	t.Run("locker error", func(t *testing.T) {
+		locker := NewMockLocker()
+		locker.err = errors.New("database is down")
+
+		fetcher := &countingFetcher{items: []*item.Item{{MarketHashName: "AK-47"}}}
+		service := NewItemService(fetcher, itemsCache(NewMockCache()), 5*time.Minute)
+		service.SetLocker(locker, time.Minute)
+
+		if items, err := service.GetItems(ctx); err != nil || len(items) != 1 {
+			t.Errorf("expected catalogue from source when locker fails, got %+v, %v", items, err)
+		}
+	})
+}
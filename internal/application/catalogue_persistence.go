@@ -0,0 +1,73 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// CataloguePersistence сохраняет последний успешно полученный каталог и восстанавливает его при старте,
+// чтобы перезапуск или недоступность Skinport не приводили к холодному кешу
+type CataloguePersistence struct {
+	store  output.CatalogueStore
+	items  *ItemServiceImpl
+	maxAge time.Duration
+	logger *slog.Logger
+}
+
+// NewCataloguePersistence создает новый экземпляр CataloguePersistence.
+// Снимки старше maxAge при восстановлении игнорируются (0 — без ограничения).
+func NewCataloguePersistence(
+	store output.CatalogueStore,
+	items *ItemServiceImpl,
+	maxAge time.Duration,
+	logger *slog.Logger,
+) *CataloguePersistence {
+	return &CataloguePersistence{
+		store:  store,
+		items:  items,
+		maxAge: maxAge,
+		logger: logger,
+	}
+}
+
+// Restore загружает сохраненный каталог в кеш. Возвращает true, если каталог восстановлен.
+func (p *CataloguePersistence) Restore(ctx context.Context) (bool, error) {
+	cat, err := p.store.Load(ctx)
+	if errors.Is(err, item.ErrCatalogueNotStored) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	age := time.Since(cat.UpdatedAt)
+	if p.maxAge > 0 && age > p.maxAge {
+		p.logger.WarnContext(ctx, "stored catalogue is too old, ignoring it",
+			slog.Time("saved_at", cat.UpdatedAt),
+			slog.Duration("max_age", p.maxAge),
+		)
+		return false, nil
+	}
+
+	if !p.items.Restore(ctx, cat) {
+		return false, nil
+	}
+
+	p.logger.InfoContext(ctx, "catalogue restored from store",
+		slog.Int("items", len(cat.Items)),
+		slog.Time("saved_at", cat.UpdatedAt),
+	)
+	return true, nil
+}
+
+// Save сохраняет свежий каталог. Подходит как RefreshHook для ItemServiceImpl.
+func (p *CataloguePersistence) Save(ctx context.Context, items []*item.Item) {
+	if err := p.store.Save(ctx, items, time.Now().UTC()); err != nil {
+		p.logger.ErrorContext(ctx, "failed to persist catalogue", slog.Any("error", err))
+	}
+}
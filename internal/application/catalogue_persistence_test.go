@@ -0,0 +1,141 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+type MockCatalogueStore struct {
+	mu      sync.Mutex
+	stored  *item.Catalogue
+	loadErr error
+	saves   int
+}
+
+func (m *MockCatalogueStore) Save(_ context.Context, items []*item.Item, savedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stored = &item.Catalogue{Items: items, UpdatedAt: savedAt}
+	m.saves++
+	return nil
+}
+
+func (m *MockCatalogueStore) Load(_ context.Context) (*item.Catalogue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	if m.stored == nil {
+		return nil, item.ErrCatalogueNotStored
+	}
+	return m.stored, nil
+}
+
+func TestCataloguePersistence_RestoreThenRefresh(t *testing.T) {
+	stale := decimal.NewFromFloat(100)
+	fresh := decimal.NewFromFloat(90)
+	savedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	store := &MockCatalogueStore{stored: &item.Catalogue{
+		Items:     []*item.Item{{MarketHashName: "AK-47", TradableMinPrice: &stale}},
+		UpdatedAt: savedAt,
+	}}
+	fetcher := &MockItemFetcher{err: errors.New("skinport is down")}
+	itemService := NewItemService(fetcher, itemsCache(NewMockCache()), 5*time.Minute)
+	persistence := NewCataloguePersistence(store, itemService, 24*time.Hour, newTestLogger())
+
+	ctx := context.Background()
+
+	restored, err := persistence.Restore(ctx)
+	if err != nil || !restored {
+		t.Fatalf("expected catalogue to be restored, got %v, %v", restored, err)
+	}
+
+	// Skinport недоступен, но каталог отдается из восстановленного снимка
+	cat, err := itemService.GetCatalogue(ctx)
+	if err != nil {
+		t.Fatalf("expected restored catalogue, got error %v", err)
+	}
+	if !cat.Items[0].TradableMinPrice.Equal(stale) || !cat.UpdatedAt.Equal(savedAt) {
+		t.Errorf("unexpected restored catalogue: %+v", cat)
+	}
+
+	if err := itemService.Refresh(ctx); err == nil {
+		t.Fatal("expected refresh error while skinport is down")
+	}
+
+	// После восстановления Skinport живой каталог заменяет снимок
+	fetcher.err = nil
+	fetcher.items = []*item.Item{{MarketHashName: "AK-47", TradableMinPrice: &fresh}}
+	if err := itemService.Refresh(ctx); err != nil {
+		t.Fatalf("expected refresh to succeed, got %v", err)
+	}
+
+	items, _ := itemService.GetItems(ctx)
+	if !items[0].TradableMinPrice.Equal(fresh) {
+		t.Errorf("expected live catalogue to replace restored one, got %s", items[0].TradableMinPrice)
+	}
+}
+
+func TestCataloguePersistence_Restore_Skips(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nothing stored", func(t *testing.T) {
+		itemService := NewItemService(&MockItemFetcher{}, itemsCache(NewMockCache()), time.Minute)
+		restored, err := NewCataloguePersistence(&MockCatalogueStore{}, itemService, 0, newTestLogger()).Restore(ctx)
+		if err != nil || restored {
+			t.Errorf("expected nothing restored, got %v, %v", restored, err)
+		}
+	})
+
+	t.Run("too old", func(t *testing.T) {
+		store := &MockCatalogueStore{stored: &item.Catalogue{Items: []*item.Item{{MarketHashName: "AK-47"}}, UpdatedAt: time.Now().Add(-48 * time.Hour)}}
+		itemService := NewItemService(&MockItemFetcher{}, itemsCache(NewMockCache()), time.Minute)
+		restored, err := NewCataloguePersistence(store, itemService, 24*time.Hour, newTestLogger()).Restore(ctx)
+		if err != nil || restored {
+			t.Errorf("expected stale snapshot to be ignored, got %v, %v", restored, err)
+		}
+	})
+
+	t.Run("load error", func(t *testing.T) {
+		itemService := NewItemService(&MockItemFetcher{}, itemsCache(NewMockCache()), time.Minute)
+		store := &MockCatalogueStore{loadErr: errors.New("db down")}
+		if _, err := NewCataloguePersistence(store, itemService, 0, newTestLogger()).Restore(ctx); err == nil {
+			t.Error("expected load error to be returned")
+		}
+	})
+}
+
+func TestCataloguePersistence_SaveAsRefreshHook(t *testing.T) {
+	store := &MockCatalogueStore{}
+	fetcher := &MockItemFetcher{items: []*item.Item{{MarketHashName: "AK-47"}}}
+	itemService := NewItemService(fetcher, itemsCache(NewMockCache()), time.Minute)
+	persistence := NewCataloguePersistence(store, itemService, 0, newTestLogger())
+
+	done := make(chan struct{})
+	itemService.AddRefreshHook(persistence.Save)
+	itemService.AddRefreshHook(func(context.Context, []*item.Item) { close(done) })
+
+	if err := itemService.Refresh(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected refresh hooks to run")
+	}
+
+	cat, err := store.Load(context.Background())
+	if err != nil || len(cat.Items) != 1 {
+		t.Errorf("expected refreshed catalogue to be persisted, got %v, %v", cat, err)
+	}
+}
@@ -0,0 +1,84 @@
+package application
+
+import (
+	"context"
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+// InsightServiceImpl реализует аналитику по закэшированному каталогу
+type InsightServiceImpl struct {
+	items              input.ItemService
+	defaultFeePercent  decimal.Decimal
+	defaultMinQuantity int
+}
+
+// NewInsightService создает новый экземпляр InsightService
+func NewInsightService(
+	items input.ItemService,
+	defaultFeePercent decimal.Decimal,
+	defaultMinQuantity int,
+) *InsightServiceImpl {
+	return &InsightServiceImpl{
+		items:              items,
+		defaultFeePercent:  defaultFeePercent,
+		defaultMinQuantity: defaultMinQuantity,
+	}
+}
+
+// GetInsights возвращает предметы, ранжированные по выбранной метрике по убыванию.
+// Предметы, для которых метрику нельзя рассчитать, в выдачу не попадают.
+func (s *InsightServiceImpl) GetInsights(ctx context.Context, q input.InsightQuery) ([]*item.Insight, error) {
+	if _, err := item.ParseInsightSort(string(q.SortBy)); err != nil {
+		return nil, err
+	}
+
+	fee := s.defaultFeePercent
+	if q.FeePercent != nil {
+		fee = *q.FeePercent
+	}
+	if err := item.ValidateFeePercent(fee); err != nil {
+		return nil, err
+	}
+
+	minQuantity := s.defaultMinQuantity
+	if q.MinQuantity != nil {
+		minQuantity = *q.MinQuantity
+	}
+
+	items, err := s.items.GetItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	insights := make([]*item.Insight, 0, len(items))
+	for _, it := range items {
+		if it.Quantity < minQuantity {
+			continue
+		}
+
+		in := item.NewInsight(it, fee)
+		if in.Metric(q.SortBy) == nil {
+			continue
+		}
+		insights = append(insights, in)
+	}
+
+	sort.Slice(insights, func(i, j int) bool {
+		a, b := insights[i].Metric(q.SortBy), insights[j].Metric(q.SortBy)
+		if !a.Equal(*b) {
+			return a.GreaterThan(*b)
+		}
+		return insights[i].MarketHashName < insights[j].MarketHashName
+	})
+
+	if q.Limit > 0 && len(insights) > q.Limit {
+		insights = insights[:q.Limit]
+	}
+
+	return insights, nil
+}
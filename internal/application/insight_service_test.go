@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/input"
+)
+
+func priced(name string, tradable, nonTradable, suggested, mean string, quantity int) *item.Item {
+	parse := func(s string) *decimal.Decimal {
+		if s == "" {
+			return nil
+		}
+		d := decimal.RequireFromString(s)
+		return &d
+	}
+	return &item.Item{
+		MarketHashName:      name,
+		TradableMinPrice:    parse(tradable),
+		NonTradableMinPrice: parse(nonTradable),
+		SuggestedPrice:      parse(suggested),
+		MeanPrice:           parse(mean),
+		Quantity:            quantity,
+	}
+}
+
+func newInsightTestService(items []*item.Item) *InsightServiceImpl {
+	itemService := NewItemService(&MockItemFetcher{items: items}, itemsCache(NewMockCache()), 5*time.Minute)
+	return NewInsightService(itemService, decimal.NewFromInt(10), 1)
+}
+
+func TestInsightService_GetInsights_RanksBySpread(t *testing.T) {
+	service := newInsightTestService([]*item.Item{
+		priced("small spread", "11", "10", "", "", 5),
+		priced("big spread", "20", "10", "", "", 5),
+		priced("no non-tradable", "20", "", "", "", 5),
+		priced("illiquid", "100", "10", "", "", 0),
+	})
+
+	insights, err := service.GetInsights(context.Background(), input.InsightQuery{SortBy: item.InsightSortSpread})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights, got %d", len(insights))
+	}
+	if insights[0].MarketHashName != "big spread" || insights[1].MarketHashName != "small spread" {
+		t.Errorf("unexpected order: %s, %s", insights[0].MarketHashName, insights[1].MarketHashName)
+	}
+}
+
+func TestInsightService_GetInsights_Overrides(t *testing.T) {
+	service := newInsightTestService([]*item.Item{
+		priced("deep discount", "5", "", "10", "", 1),
+		priced("shallow discount", "9", "", "10", "", 50),
+	})
+
+	fee := decimal.NewFromInt(5)
+	minQuantity := 10
+	insights, err := service.GetInsights(context.Background(), input.InsightQuery{
+		SortBy:      item.InsightSortDiscount,
+		FeePercent:  &fee,
+		MinQuantity: &minQuantity,
+		Limit:       10,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(insights) != 1 || insights[0].MarketHashName != "shallow discount" {
+		t.Errorf("expected only liquid item, got %+v", insights)
+	}
+}
+
+func TestInsightService_GetInsights_Limit(t *testing.T) {
+	service := newInsightTestService([]*item.Item{
+		priced("a", "10", "", "", "20", 1),
+		priced("b", "10", "", "", "30", 1),
+		priced("c", "10", "", "", "40", 1),
+	})
+
+	insights, err := service.GetInsights(context.Background(), input.InsightQuery{SortBy: item.InsightSortMeanGap, Limit: 2})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(insights) != 2 || insights[0].MarketHashName != "c" {
+		t.Errorf("expected top-2 starting with c, got %+v", insights)
+	}
+}
+
+func TestInsightService_GetInsights_Validation(t *testing.T) {
+	service := newInsightTestService(nil)
+
+	_, err := service.GetInsights(context.Background(), input.InsightQuery{SortBy: "profit"})
+	if !errors.Is(err, item.ErrInvalidInsightSort) {
+		t.Errorf("expected ErrInvalidInsightSort, got %v", err)
+	}
+
+	fee := decimal.NewFromInt(100)
+	_, err = service.GetInsights(context.Background(), input.InsightQuery{SortBy: item.InsightSortSpread, FeePercent: &fee})
+	if !errors.Is(err, item.ErrInvalidFeePercent) {
+		t.Errorf("expected ErrInvalidFeePercent, got %v", err)
+	}
+}
@@ -2,35 +2,113 @@ package application
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
-	"golang.org/x/sync/singleflight"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/tracing"
 	"github.com/akonovalovdev/DDD_example/internal/ports/output"
 )
 
 const itemsCacheKey = "skinport:items"
 
+// RefreshHook вызывается после каждого успешного обновления каталога из внешнего источника
+type RefreshHook func(ctx context.Context, items []*item.Item)
+
+// CachedCatalogue каталог в кеше вместе с ревизией — хешем содержимого. Замену каталога
+// в общем кеше другой репликой видно по ревизии без поэлементного сравнения предметов,
+// а повторная загрузка того же каталога не считается изменением.
+type CachedCatalogue struct {
+	Items    []*item.Item `json:"items"`
+	Revision string       `json:"revision"`
+}
+
+// newCachedCatalogue готовит каталог к записи в кеш: ревизия считается один раз на загрузку
+func newCachedCatalogue(items []*item.Item) (CachedCatalogue, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return CachedCatalogue{}, fmt.Errorf("failed to encode catalogue: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return CachedCatalogue{Items: items, Revision: hex.EncodeToString(sum[:16])}, nil
+}
+
 // ItemServiceImpl реализует сервис для работы с предметами
 type ItemServiceImpl struct {
 	fetcher  output.ItemFetcher
-	cache    output.Cache
+	cache    output.TypedCache[string, CachedCatalogue]
 	cacheTTL time.Duration
-	sfGroup  singleflight.Group // защита от thundering herd
+	loader   *CacheLoader[string, CachedCatalogue] // защита от thundering herd
+
+	// catalogueMu сериализует замену каталога: полное обновление и применение событий ленты
+	catalogueMu sync.Mutex
+	current     *item.Catalogue // каталог из кеша с примененными событиями ленты продаж
+	revision    string          // ревизия в кеше, на основе которой построен current
+	patched     bool            // к current применены события ленты
+	lastFetch   error           // результат последнего запроса каталога из внешнего источника
+
+	pendingMu sync.Mutex
+	pending   []*item.SaleEvent // события ленты, еще не примененные к current
+
+	hooksMu sync.RWMutex
+	hooks   []RefreshHook
 }
 
 // NewItemService создает новый экземпляр ItemService
 func NewItemService(
 	fetcher output.ItemFetcher,
-	cache output.Cache,
+	cache output.TypedCache[string, CachedCatalogue],
 	cacheTTL time.Duration,
 ) *ItemServiceImpl {
-	return &ItemServiceImpl{
+	s := &ItemServiceImpl{
 		fetcher:  fetcher,
 		cache:    cache,
 		cacheTTL: cacheTTL,
 	}
+	s.loader = NewCacheLoaderWithStore(cache, s.store)
+	return s
+}
+
+// SetLocker включает распределенную блокировку обновления каталога: при общем кеше
+// Skinport опрашивает только одна реплика, остальные отдают предыдущий каталог
+// или ждут до wait, пока новый появится в кеше. Вызывается при инициализации.
+func (s *ItemServiceImpl) SetLocker(locker output.Locker, wait time.Duration) {
+	s.loader.SetLocker(locker, wait)
+	s.loader.SetStale(s.staleItems)
+}
+
+// staleItems возвращает последний опубликованный каталог, даже если его время жизни истекло
+func (s *ItemServiceImpl) staleItems(ctx context.Context, key string) (CachedCatalogue, bool) {
+	s.catalogueMu.Lock()
+	defer s.catalogueMu.Unlock()
+
+	if s.current == nil {
+		return CachedCatalogue{}, false
+	}
+	return CachedCatalogue{Items: s.current.Items, Revision: s.revision}, true
+}
+
+// LoaderStats возвращает счетчики загрузок каталога и запросов, дождавшихся чужой загрузки
+func (s *ItemServiceImpl) LoaderStats() LoaderStats {
+	return s.loader.Stats()
+}
+
+// AddRefreshHook регистрирует обработчик, вызываемый после обновления каталога.
+// Обработчики выполняются последовательно в фоне и не задерживают ответ клиенту.
+func (s *ItemServiceImpl) AddRefreshHook(hook RefreshHook) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+
+	s.hooks = append(s.hooks, hook)
 }
 
 // WarmUp прогревает кеш при запуске приложения
@@ -41,37 +119,275 @@ func (s *ItemServiceImpl) WarmUp(ctx context.Context) error {
 
 // GetItems возвращает список предметов с минимальными ценами
 func (s *ItemServiceImpl) GetItems(ctx context.Context) ([]*item.Item, error) {
-	// 1. Проверяем кэш
-	if cached, ok := s.cache.Get(ctx, itemsCacheKey); ok {
-		if items, ok := cached.([]*item.Item); ok {
-			return items, nil
+	cat, err := s.getCached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cat.Items, nil
+}
+
+// getCached возвращает текущую версию каталога, при промахе кеша загружает его из источника.
+// Пока ревизия в кеше не меняется, отдается локальная копия с примененными событиями ленты.
+func (s *ItemServiceImpl) getCached(ctx context.Context) (*item.Catalogue, error) {
+	ctx, span := tracing.Start(ctx, "ItemServiceImpl.GetItems")
+	defer span.End()
+
+	cached, err := s.loader.GetOrLoad(ctx, itemsCacheKey, s.fetch)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	s.catalogueMu.Lock()
+	// Каталог мог записать в общий кеш другая реплика — тогда это новая версия.
+	// Внешний кеш на каждое чтение возвращает новую копию, поэтому сравниваются ревизии
+	if s.current == nil || cached.Revision != s.revision {
+		s.publishCatalogue(cached, time.Now())
+	}
+	current := s.current
+	s.catalogueMu.Unlock()
+
+	span.SetAttributes(attribute.Int("items.count", len(current.Items)))
+	return current, nil
+}
+
+// Refresh принудительно обновляет каталог из внешнего источника, минуя кеш
+func (s *ItemServiceImpl) Refresh(ctx context.Context) error {
+	_, err := s.loader.Reload(ctx, itemsCacheKey, s.fetch)
+	return err
+}
+
+// fetch запрашивает каталог из внешнего источника и запоминает результат для CatalogueStatus
+func (s *ItemServiceImpl) fetch(ctx context.Context) (CachedCatalogue, error) {
+	items, err := s.fetcher.FetchItems(ctx)
+	// Запрос, отмененный вызывающим, ничего не говорит о состоянии источника
+	if ctx.Err() == nil {
+		s.catalogueMu.Lock()
+		s.lastFetch = err
+		s.catalogueMu.Unlock()
+	}
+
+	if err != nil {
+		return CachedCatalogue{Items: items}, err
+	}
+	return newCachedCatalogue(items)
+}
+
+// CatalogueStatus описывает состояние каталога для проверки готовности
+type CatalogueStatus struct {
+	// Loaded каталог был загружен из источника или восстановлен из снимка
+	Loaded    bool
+	UpdatedAt time.Time
+	// LastError ошибка последнего обновления из внешнего источника; nil, если оно удалось
+	LastError error
+}
+
+// Ready проверяет, что каталогом можно обслуживать запросы: он загружен и не устарел.
+// Старый каталог допустим, пока обновления не падают: без запросов он не обновляется.
+func (st CatalogueStatus) Ready(maxAge time.Duration, now time.Time) error {
+	if !st.Loaded {
+		if st.LastError != nil {
+			return fmt.Errorf("catalogue is not loaded: %w", st.LastError)
 		}
+		return errors.New("catalogue is not loaded yet")
+	}
+	if age := now.Sub(st.UpdatedAt); st.LastError != nil && age > maxAge {
+		return fmt.Errorf("catalogue is %s old and refresh failed: %w", age.Round(time.Second), st.LastError)
 	}
+	return nil
+}
 
-	// 2. Singleflight — дедупликация параллельных запросов
-	result, err, _ := s.sfGroup.Do(itemsCacheKey, func() (interface{}, error) {
-		// Повторная проверка кеша (мог заполниться пока ждали)
-		if cached, ok := s.cache.Get(ctx, itemsCacheKey); ok {
-			if items, ok := cached.([]*item.Item); ok {
-				return items, nil
-			}
+// CatalogueStatus возвращает состояние каталога, не обращаясь к кешу и источнику
+func (s *ItemServiceImpl) CatalogueStatus() CatalogueStatus {
+	s.catalogueMu.Lock()
+	defer s.catalogueMu.Unlock()
+
+	status := CatalogueStatus{LastError: s.lastFetch}
+	if s.current != nil {
+		status.Loaded = true
+		status.UpdatedAt = s.current.UpdatedAt
+	}
+	return status
+}
+
+// Restore кладет в кеш ранее сохраненный каталог, если актуального каталога еще нет.
+// Время обновления каталога берется из снимка, обработчики обновления не вызываются —
+// данные не новые. Возвращает true, если каталог был восстановлен.
+func (s *ItemServiceImpl) Restore(ctx context.Context, cat *item.Catalogue) bool {
+	s.catalogueMu.Lock()
+	defer s.catalogueMu.Unlock()
+
+	if _, ok := s.cache.Get(ctx, itemsCacheKey); ok {
+		return false
+	}
+
+	cached, err := newCachedCatalogue(cat.Items)
+	if err != nil {
+		return false
+	}
+	s.cache.Set(ctx, itemsCacheKey, cached, s.cacheTTL)
+	s.publishCatalogue(cached, cat.UpdatedAt)
+
+	return true
+}
+
+// store кладет полученный из API каталог в кеш и запускает обработчики обновления.
+// Запись идет под catalogueMu, чтобы не перетереть свежий каталог событиями ленты продаж.
+func (s *ItemServiceImpl) store(ctx context.Context, key string, cached CachedCatalogue) {
+	s.catalogueMu.Lock()
+	s.cache.Set(ctx, key, cached, s.cacheTTL)
+	s.publishCatalogue(cached, time.Now())
+	s.catalogueMu.Unlock()
+
+	s.runRefreshHooks(ctx, cached.Items)
+}
+
+// ApplySaleEvents ставит события ленты продаж в очередь. Очередь применяется к каталогу
+// в памяти раз в интервал RunSaleEventFlush, поэтому частые события не пересобирают
+// и не перезаписывают каталог на каждую пачку.
+func (s *ItemServiceImpl) ApplySaleEvents(_ context.Context, events []*item.SaleEvent) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	s.pending = append(s.pending, events...)
+}
+
+// RunSaleEventFlush периодически применяет накопленные события ленты продаж.
+// Блокируется до отмены контекста.
+func (s *ItemServiceImpl) RunSaleEventFlush(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.flushSaleEvents()
+		}
+	}
+}
+
+// flushSaleEvents применяет накопленные события к текущему каталогу одной новой версией.
+// События меняют только каталог в памяти реплики: общий кеш, ревизия и время жизни
+// не трогаются, лента лишь уточняет каталог до следующего полного обновления.
+// Каталог заменяется обновленной копией (copy-on-write), поэтому конкурентные читатели
+// не видят частичных изменений. Если каталог еще не загружен, события пропускаются.
+func (s *ItemServiceImpl) flushSaleEvents() {
+	s.pendingMu.Lock()
+	events := s.pending
+	s.pending = nil
+	s.pendingMu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	s.catalogueMu.Lock()
+	defer s.catalogueMu.Unlock()
+
+	if s.current == nil {
+		return
+	}
+	items := s.current.Items
+
+	updated := make([]*item.Item, len(items), len(items)+len(events))
+	copy(updated, items)
+
+	index := make(map[string]int, len(updated))
+	for i, it := range updated {
+		index[it.MarketHashName] = i
+	}
+
+	changed, added := false, false
+	for _, ev := range events {
+		i, exists := index[ev.MarketHashName]
+
+		var current *item.Item
+		if exists {
+			current = updated[i]
 		}
 
-		// Запрашиваем из API
-		items, err := s.fetcher.FetchItems(ctx)
-		if err != nil {
-			return nil, err
+		next := item.ApplySale(current, ev)
+		if next == nil {
+			continue
 		}
 
-		// Сохраняем в кэш
-		s.cache.Set(ctx, itemsCacheKey, items, s.cacheTTL)
+		if exists {
+			updated[i] = next
+		} else {
+			index[ev.MarketHashName] = len(updated)
+			updated = append(updated, next)
+			added = true
+		}
+		changed = true
+	}
 
-		return items, nil
-	})
+	if !changed {
+		return
+	}
+	// Новые предметы встают на свое место, чтобы каталог оставался упорядоченным
+	if added {
+		slices.SortFunc(updated, func(a, b *item.Item) int {
+			return strings.Compare(a.MarketHashName, b.MarketHashName)
+		})
+	}
+	s.current = &item.Catalogue{
+		Items:     updated,
+		Version:   s.current.Version + 1,
+		UpdatedAt: time.Now().UTC(),
+	}
+	s.patched = true
+}
 
-	if err != nil {
-		return nil, err
+// GetCatalogue возвращает каталог с версией и временем последнего обновления.
+// Версия меняется только при замене каталога, поэтому по ней можно кешировать производные данные.
+func (s *ItemServiceImpl) GetCatalogue(ctx context.Context) (*item.Catalogue, error) {
+	return s.getCached(ctx)
+}
+
+// publishCatalogue фиксирует каталог из кеша. Версия растет, только если содержимое
+// изменилось: другая ревизия или сброс примененных событий ленты. Вызывается под catalogueMu.
+func (s *ItemServiceImpl) publishCatalogue(cached CachedCatalogue, updatedAt time.Time) {
+	if s.current != nil && cached.Revision == s.revision && !s.patched {
+		s.current = &item.Catalogue{
+			Items:     s.current.Items,
+			Version:   s.current.Version,
+			UpdatedAt: updatedAt.UTC(),
+		}
+		return
+	}
+
+	var version uint64 = 1
+	if s.current != nil {
+		version = s.current.Version + 1
+	}
+
+	s.current = &item.Catalogue{
+		Items:     cached.Items,
+		Version:   version,
+		UpdatedAt: updatedAt.UTC(),
+	}
+	s.revision = cached.Revision
+	s.patched = false
+}
+
+// runRefreshHooks запускает обработчики обновления каталога в отдельной горутине.
+// Контекст отвязывается от запроса, чтобы его отмена не прерывала обработчики.
+func (s *ItemServiceImpl) runRefreshHooks(ctx context.Context, items []*item.Item) {
+	s.hooksMu.RLock()
+	hooks := make([]RefreshHook, len(s.hooks))
+	copy(hooks, s.hooks)
+	s.hooksMu.RUnlock()
+
+	if len(hooks) == 0 {
+		return
 	}
 
-	return result.([]*item.Item), nil
+	hookCtx := context.WithoutCancel(ctx)
+	go func() {
+		for _, hook := range hooks {
+			hook(hookCtx, items)
+		}
+	}()
 }
@@ -9,6 +9,8 @@ import (
 	"github.com/shopspring/decimal"
 
 	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/pkg/cache"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
 )
 
 type MockItemFetcher struct {
@@ -45,6 +47,26 @@ func (m *MockCache) Clear(ctx context.Context) {
 	m.data = make(map[string]interface{})
 }
 
+func mustCachedCatalogue(t *testing.T, items []*item.Item) CachedCatalogue {
+	t.Helper()
+
+	cached, err := newCachedCatalogue(items)
+	if err != nil {
+		t.Fatalf("failed to prepare catalogue: %v", err)
+	}
+	return cached
+}
+
+// itemsCache типизирует нетипизированный mock-кэш для ItemService
+func itemsCache(c output.Cache) output.TypedCache[string, CachedCatalogue] {
+	return cache.NewTyped[CachedCatalogue](c, nil)
+}
+
+// salesHistoryCache типизирует нетипизированный mock-кэш для SalesHistoryService
+func salesHistoryCache(c output.Cache) output.TypedCache[string, map[string]*item.SalesHistory] {
+	return cache.NewTyped[map[string]*item.SalesHistory](c, nil)
+}
+
 func TestItemService_GetItems_FromAPI(t *testing.T) {
 	price1 := decimal.NewFromFloat(100)
 	price2 := decimal.NewFromFloat(90)
@@ -59,7 +81,7 @@ func TestItemService_GetItems_FromAPI(t *testing.T) {
 	fetcher := &MockItemFetcher{items: expectedItems}
 	cache := NewMockCache()
 
-	service := NewItemService(fetcher, cache, 5*time.Minute)
+	service := NewItemService(fetcher, itemsCache(cache), 5*time.Minute)
 
 	items, err := service.GetItems(context.Background())
 
@@ -76,7 +98,7 @@ func TestItemService_GetItems_FromAPI(t *testing.T) {
 		t.Error("expected items to be cached")
 	}
 
-	cachedItems := cached.([]*item.Item)
+	cachedItems := cached.(CachedCatalogue).Items
 	if len(cachedItems) != len(expectedItems) {
 		t.Errorf("expected %d cached items, got %d", len(expectedItems), len(cachedItems))
 	}
@@ -95,9 +117,9 @@ func TestItemService_GetItems_FromCache(t *testing.T) {
 	}
 
 	cache := NewMockCache()
-	cache.Set(context.Background(), "skinport:items", cachedItems, 5*time.Minute)
+	cache.Set(context.Background(), "skinport:items", CachedCatalogue{Items: cachedItems, Revision: "r1"}, 5*time.Minute)
 
-	service := NewItemService(fetcher, cache, 5*time.Minute)
+	service := NewItemService(fetcher, itemsCache(cache), 5*time.Minute)
 
 	items, err := service.GetItems(context.Background())
 
@@ -120,7 +142,7 @@ func TestItemService_GetItems_FetchError(t *testing.T) {
 	fetcher := &MockItemFetcher{err: expectedError}
 	cache := NewMockCache()
 
-	service := NewItemService(fetcher, cache, 5*time.Minute)
+	service := NewItemService(fetcher, itemsCache(cache), 5*time.Minute)
 
 	_, err := service.GetItems(context.Background())
 
@@ -132,3 +154,310 @@ func TestItemService_GetItems_FetchError(t *testing.T) {
 		t.Errorf("expected error %v, got %v", expectedError, err)
 	}
 }
+
+func TestItemService_GetItems_RunsRefreshHooks(t *testing.T) {
+	price := decimal.NewFromFloat(100)
+	fetcher := &MockItemFetcher{items: []*item.Item{{MarketHashName: "AK-47", TradableMinPrice: &price}}}
+
+	service := NewItemService(fetcher, itemsCache(NewMockCache()), 5*time.Minute)
+
+	called := make(chan []*item.Item, 1)
+	service.AddRefreshHook(func(_ context.Context, items []*item.Item) {
+		called <- items
+	})
+
+	if _, err := service.GetItems(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	select {
+	case items := <-called:
+		if len(items) != 1 {
+			t.Errorf("expected 1 item in hook, got %d", len(items))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected refresh hook to be called")
+	}
+
+	// Ответ из кэша не является обновлением каталога
+	if _, err := service.GetItems(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	select {
+	case <-called:
+		t.Error("expected hook not to be called on cache hit")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestItemService_ApplySaleEvents(t *testing.T) {
+	price := decimal.NewFromFloat(100)
+	original := &item.Item{MarketHashName: "AK-47", TradableMinPrice: &price, Quantity: 1}
+
+	fetcher := &MockItemFetcher{items: []*item.Item{original}}
+	cache := NewMockCache()
+	service := NewItemService(fetcher, itemsCache(cache), 5*time.Minute)
+
+	ctx := context.Background()
+	before, err := service.GetItems(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	listed, _ := item.NewSaleEvent(item.SaleEventListed, 1, "AK-47", "USD", decimal.NewFromFloat(90), true, time.Now())
+	newItem, _ := item.NewSaleEvent(item.SaleEventListed, 2, "AWP", "USD", decimal.NewFromFloat(500), true, time.Now())
+	unknownSold, _ := item.NewSaleEvent(item.SaleEventSold, 3, "M4A4", "USD", decimal.NewFromFloat(5), true, time.Now())
+
+	service.ApplySaleEvents(ctx, []*item.SaleEvent{listed, newItem})
+	service.ApplySaleEvents(ctx, []*item.SaleEvent{unknownSold})
+
+	// До сброса очереди каталог не меняется
+	if queued, _ := service.GetItems(ctx); len(queued) != 1 || queued[0] != original {
+		t.Fatalf("expected events to wait for flush, got %+v", queued)
+	}
+	service.flushSaleEvents()
+
+	after, err := service.GetItems(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(after) != 2 {
+		t.Fatalf("expected 2 items after applying events, got %d", len(after))
+	}
+	if after[0].TradableMinPrice.String() != "90" || after[0].Quantity != 2 {
+		t.Errorf("expected AK-47 updated from listing, got %+v", after[0])
+	}
+	if after[1].MarketHashName != "AWP" {
+		t.Errorf("expected AWP appended, got %s", after[1].MarketHashName)
+	}
+
+	// Прежний срез каталога не должен изменяться
+	if len(before) != 1 || before[0] != original || original.Quantity != 1 {
+		t.Errorf("expected previous catalogue untouched, got %+v", before)
+	}
+
+	// События меняют только каталог в памяти: общий кеш не перезаписывается
+	cached, _ := cache.Get(ctx, "skinport:items")
+	if items := cached.(CachedCatalogue).Items; len(items) != 1 || items[0] != original {
+		t.Errorf("expected cached catalogue untouched, got %+v", items)
+	}
+}
+
+func TestItemService_FlushSaleEvents_OneVersionPerFlush(t *testing.T) {
+	price := decimal.NewFromFloat(100)
+	fetcher := &MockItemFetcher{items: []*item.Item{{MarketHashName: "AK-47", TradableMinPrice: &price, Quantity: 1}}}
+	service := NewItemService(fetcher, itemsCache(NewMockCache()), 5*time.Minute)
+
+	ctx := context.Background()
+	first, err := service.GetCatalogue(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for i := range 3 {
+		listed, _ := item.NewSaleEvent(item.SaleEventListed, int64(i), "AK-47", "USD", decimal.NewFromFloat(90), true, time.Now())
+		service.ApplySaleEvents(ctx, []*item.SaleEvent{listed})
+	}
+	awp, _ := item.NewSaleEvent(item.SaleEventListed, 10, "AWP", "USD", decimal.NewFromFloat(500), true, time.Now())
+	aug, _ := item.NewSaleEvent(item.SaleEventListed, 11, "AUG", "USD", decimal.NewFromFloat(5), true, time.Now())
+	service.ApplySaleEvents(ctx, []*item.SaleEvent{awp, aug})
+	service.flushSaleEvents()
+
+	flushed, _ := service.GetCatalogue(ctx)
+	if flushed.Version != first.Version+1 {
+		t.Errorf("expected one new version for all queued batches, got %d after %d", flushed.Version, first.Version)
+	}
+	if len(flushed.Items) != 3 || flushed.Items[0].Quantity != 4 {
+		t.Fatalf("expected all queued events applied, got %+v", flushed.Items)
+	}
+	// Новые предметы встают по порядку MarketHashName
+	if flushed.Items[1].MarketHashName != "AUG" || flushed.Items[2].MarketHashName != "AWP" {
+		t.Errorf("expected catalogue to stay sorted, got %s, %s", flushed.Items[1].MarketHashName, flushed.Items[2].MarketHashName)
+	}
+
+	// Пустая очередь не создает новую версию
+	service.flushSaleEvents()
+	if again, _ := service.GetCatalogue(ctx); again.Version != flushed.Version {
+		t.Errorf("expected stable version without events, got %d and %d", flushed.Version, again.Version)
+	}
+}
+
+func TestItemService_ApplySaleEvents_WithoutCatalogue(t *testing.T) {
+	cache := NewMockCache()
+	service := NewItemService(&MockItemFetcher{}, itemsCache(cache), 5*time.Minute)
+
+	listed, _ := item.NewSaleEvent(item.SaleEventListed, 1, "AK-47", "USD", decimal.NewFromFloat(90), true, time.Now())
+	service.ApplySaleEvents(context.Background(), []*item.SaleEvent{listed})
+	service.flushSaleEvents()
+
+	if _, ok := cache.Get(context.Background(), "skinport:items"); ok {
+		t.Error("expected events to be ignored when catalogue is not cached")
+	}
+}
+
+func TestItemService_GetCatalogue_Versioning(t *testing.T) {
+	price := decimal.NewFromFloat(100)
+	fetcher := &MockItemFetcher{items: []*item.Item{{MarketHashName: "AK-47", TradableMinPrice: &price, Quantity: 1}}}
+	cache := NewMockCache()
+	service := NewItemService(fetcher, itemsCache(cache), 5*time.Minute)
+
+	ctx := context.Background()
+
+	first, err := service.GetCatalogue(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	again, _ := service.GetCatalogue(ctx)
+	if again.Version != first.Version {
+		t.Errorf("expected stable version for unchanged catalogue, got %d and %d", first.Version, again.Version)
+	}
+
+	// Инкрементальное обновление из ленты продаж — новая версия
+	listed, _ := item.NewSaleEvent(item.SaleEventListed, 1, "AK-47", "USD", decimal.NewFromFloat(90), true, time.Now())
+	service.ApplySaleEvents(ctx, []*item.SaleEvent{listed})
+	service.flushSaleEvents()
+
+	afterSale, _ := service.GetCatalogue(ctx)
+	if afterSale.Version <= first.Version {
+		t.Errorf("expected version to grow after sale event, got %d after %d", afterSale.Version, first.Version)
+	}
+
+	// Полное обновление после истечения кеша сбрасывает события ленты — тоже новая версия
+	cache.Clear(ctx)
+	refreshed, _ := service.GetCatalogue(ctx)
+	if refreshed.Version <= afterSale.Version {
+		t.Errorf("expected version to grow after refresh, got %d after %d", refreshed.Version, afterSale.Version)
+	}
+
+	// Повторная загрузка того же каталога версию не меняет
+	cache.Clear(ctx)
+	reloaded, _ := service.GetCatalogue(ctx)
+	if reloaded.Version != refreshed.Version {
+		t.Errorf("expected stable version for unchanged upstream catalogue, got %d after %d", reloaded.Version, refreshed.Version)
+	}
+}
+
+// copyingCache имитирует внешний кеш (Redis), который на каждое чтение возвращает новую копию
+type copyingCache struct {
+	*MockCache
+}
+
+func (c copyingCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	val, ok := c.MockCache.Get(ctx, key)
+	if cached, isCatalogue := val.(CachedCatalogue); isCatalogue {
+		copied := make([]*item.Item, len(cached.Items))
+		for i, it := range cached.Items {
+			clone := *it
+			copied[i] = &clone
+		}
+		return CachedCatalogue{Items: copied, Revision: cached.Revision}, ok
+	}
+	return val, ok
+}
+
+func TestItemService_GetCatalogue_StableWithCopyingCache(t *testing.T) {
+	price := decimal.NewFromFloat(100)
+	fetcher := &MockItemFetcher{items: []*item.Item{{MarketHashName: "AK-47", TradableMinPrice: &price, Quantity: 1}}}
+	service := NewItemService(fetcher, itemsCache(copyingCache{NewMockCache()}), 5*time.Minute)
+
+	ctx := context.Background()
+
+	first, err := service.GetCatalogue(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	again, _ := service.GetCatalogue(ctx)
+	if again.Version != first.Version {
+		t.Errorf("expected stable version when cache returns equal copies, got %d and %d", first.Version, again.Version)
+	}
+}
+
+func TestItemService_GetCatalogue_NewRevisionFromOtherReplica(t *testing.T) {
+	price := decimal.NewFromFloat(100)
+	fetcher := &MockItemFetcher{items: []*item.Item{{MarketHashName: "AK-47", TradableMinPrice: &price, Quantity: 1}}}
+	shared := copyingCache{NewMockCache()}
+	service := NewItemService(fetcher, itemsCache(shared), 5*time.Minute)
+
+	ctx := context.Background()
+
+	first, err := service.GetCatalogue(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Другая реплика записала в общий кеш тот же каталог: ревизия та же — и версия та же
+	shared.Set(ctx, "skinport:items", mustCachedCatalogue(t, fetcher.items), time.Minute)
+	same, _ := service.GetCatalogue(ctx)
+	if same.Version != first.Version {
+		t.Errorf("expected stable version for identical content, got %d after %d", same.Version, first.Version)
+	}
+
+	// Другой каталог — новая ревизия и новая версия, предметы при этом не сравниваются
+	cheaper := decimal.NewFromFloat(90)
+	shared.Set(ctx, "skinport:items", mustCachedCatalogue(t, []*item.Item{{MarketHashName: "AK-47", TradableMinPrice: &cheaper, Quantity: 1}}), time.Minute)
+	replaced, _ := service.GetCatalogue(ctx)
+	if replaced.Version <= first.Version {
+		t.Errorf("expected version to grow after another replica's write, got %d after %d", replaced.Version, first.Version)
+	}
+
+	again, _ := service.GetCatalogue(ctx)
+	if again.Version != replaced.Version {
+		t.Errorf("expected stable version for the same revision, got %d and %d", replaced.Version, again.Version)
+	}
+}
+
+func TestItemService_CatalogueStatus(t *testing.T) {
+	fetcher := &MockItemFetcher{err: errors.New("skinport is down")}
+	cache := NewMockCache()
+	service := NewItemService(fetcher, itemsCache(cache), 5*time.Minute)
+
+	if status := service.CatalogueStatus(); status.Loaded || status.LastError != nil {
+		t.Fatalf("expected empty status before first load, got %+v", status)
+	}
+
+	if _, err := service.GetItems(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if status := service.CatalogueStatus(); status.Loaded || status.LastError == nil {
+		t.Fatalf("expected failed load to be reported, got %+v", status)
+	}
+
+	fetcher.err = nil
+	fetcher.items = []*item.Item{{MarketHashName: "AK-47"}}
+	if err := service.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	status := service.CatalogueStatus()
+	if !status.Loaded || status.LastError != nil || time.Since(status.UpdatedAt) > time.Minute {
+		t.Errorf("expected fresh catalogue after refresh, got %+v", status)
+	}
+}
+
+func TestCatalogueStatus_Ready(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	errDown := errors.New("skinport is down")
+
+	tests := []struct {
+		name   string
+		status CatalogueStatus
+		ready  bool
+	}{
+		{"not loaded", CatalogueStatus{}, false},
+		{"first load failed", CatalogueStatus{LastError: errDown}, false},
+		{"fresh", CatalogueStatus{Loaded: true, UpdatedAt: now.Add(-time.Minute)}, true},
+		{"old but idle", CatalogueStatus{Loaded: true, UpdatedAt: now.Add(-3 * time.Hour)}, true},
+		{"old and refresh failing", CatalogueStatus{Loaded: true, UpdatedAt: now.Add(-3 * time.Hour), LastError: errDown}, false},
+		{"refresh failing within max age", CatalogueStatus{Loaded: true, UpdatedAt: now.Add(-time.Minute), LastError: errDown}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.status.Ready(time.Hour, now)
+			if (err == nil) != tt.ready {
+				t.Errorf("Ready() error = %v, want ready %v", err, tt.ready)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+const (
+	// minCandleInterval минимальный шаг агрегации истории цен
+	minCandleInterval = time.Minute
+	// maxCandles максимальное количество интервалов в одном запросе
+	maxCandles = 1000
+	// snapshotTimeout ограничивает время записи одного среза каталога
+	snapshotTimeout = time.Minute
+)
+
+// PriceHistoryServiceImpl реализует сервис собственной истории цен
+type PriceHistoryServiceImpl struct {
+	repo      output.PriceSnapshotRepository
+	retention time.Duration
+	logger    *slog.Logger
+}
+
+// NewPriceHistoryService создает новый экземпляр PriceHistoryService
+func NewPriceHistoryService(
+	repo output.PriceSnapshotRepository,
+	retention time.Duration,
+	logger *slog.Logger,
+) *PriceHistoryServiceImpl {
+	return &PriceHistoryServiceImpl{
+		repo:      repo,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+// RecordSnapshot сохраняет срез цен всего каталога.
+// Подходит как RefreshHook для ItemServiceImpl.
+func (s *PriceHistoryServiceImpl) RecordSnapshot(ctx context.Context, items []*item.Item) {
+	ctx, cancel := context.WithTimeout(ctx, snapshotTimeout)
+	defer cancel()
+
+	takenAt := time.Now().UTC()
+	snapshots := make([]*item.PriceSnapshot, len(items))
+	for i, it := range items {
+		snapshots[i] = item.NewPriceSnapshot(it, takenAt)
+	}
+
+	if err := s.repo.SaveSnapshots(ctx, snapshots); err != nil {
+		s.logger.ErrorContext(ctx, "failed to save price snapshot", slog.Any("error", err))
+		return
+	}
+
+	s.logger.DebugContext(ctx, "price snapshot saved", slog.Int("items", len(snapshots)))
+}
+
+// GetPriceCandles возвращает OHLC агрегаты цен предмета за период [from, to) с шагом interval
+func (s *PriceHistoryServiceImpl) GetPriceCandles(
+	ctx context.Context,
+	marketHashName string,
+	from, to time.Time,
+	interval time.Duration,
+) ([]*item.PriceCandle, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", item.ErrInvalidTimeRange)
+	}
+	if interval < minCandleInterval {
+		return nil, fmt.Errorf("%w: must be at least %s", item.ErrInvalidInterval, minCandleInterval)
+	}
+	if to.Sub(from)/interval > maxCandles {
+		return nil, fmt.Errorf("%w: range produces more than %d intervals", item.ErrInvalidInterval, maxCandles)
+	}
+
+	return s.repo.GetCandles(ctx, marketHashName, from, to, interval)
+}
+
+// RunRetention периодически удаляет срезы старше срока хранения.
+// Блокируется до отмены контекста.
+func (s *PriceHistoryServiceImpl) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.deleteExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteExpired удаляет срезы старше срока хранения
+func (s *PriceHistoryServiceImpl) deleteExpired(ctx context.Context) {
+	before := time.Now().UTC().Add(-s.retention)
+
+	deleted, err := s.repo.DeleteOlderThan(ctx, before)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.ErrorContext(ctx, "failed to delete expired price snapshots", slog.Any("error", err))
+		}
+		return
+	}
+
+	if deleted > 0 {
+		s.logger.InfoContext(ctx, "expired price snapshots deleted", slog.Int64("deleted", deleted))
+	}
+}
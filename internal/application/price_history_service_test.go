@@ -0,0 +1,144 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+type MockPriceSnapshotRepository struct {
+	mu        sync.Mutex
+	saved     []*item.PriceSnapshot
+	saveErr   error
+	candles   []*item.PriceCandle
+	deletedAt time.Time
+}
+
+func (m *MockPriceSnapshotRepository) SaveSnapshots(_ context.Context, snapshots []*item.PriceSnapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.saved = append(m.saved, snapshots...)
+	return nil
+}
+
+func (m *MockPriceSnapshotRepository) GetCandles(_ context.Context, _ string, _, _ time.Time, _ time.Duration) ([]*item.PriceCandle, error) {
+	return m.candles, nil
+}
+
+func (m *MockPriceSnapshotRepository) DeleteOlderThan(_ context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deletedAt = before
+	return 0, nil
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestPriceHistoryService_RecordSnapshot(t *testing.T) {
+	price := decimal.NewFromFloat(12.5)
+	items := []*item.Item{
+		{MarketHashName: "AK-47", TradableMinPrice: &price, Quantity: 3},
+		{MarketHashName: "AWP", Quantity: 1},
+	}
+
+	repo := &MockPriceSnapshotRepository{}
+	service := NewPriceHistoryService(repo, 24*time.Hour, newTestLogger())
+
+	service.RecordSnapshot(context.Background(), items)
+
+	if len(repo.saved) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(repo.saved))
+	}
+
+	if repo.saved[0].TakenAt != repo.saved[1].TakenAt {
+		t.Error("expected all snapshots of one refresh to share timestamp")
+	}
+
+	if !repo.saved[0].TradableMinPrice.Equal(price) || repo.saved[0].Quantity != 3 {
+		t.Errorf("unexpected snapshot: %+v", repo.saved[0])
+	}
+}
+
+func TestPriceHistoryService_GetPriceCandles_Validation(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		interval time.Duration
+		wantErr  error
+	}{
+		{"from after to", now, now.Add(-time.Hour), time.Hour, item.ErrInvalidTimeRange},
+		{"empty range", now, now, time.Hour, item.ErrInvalidTimeRange},
+		{"interval too small", now.Add(-time.Hour), now, time.Second, item.ErrInvalidInterval},
+		{"too many candles", now.Add(-30 * 24 * time.Hour), now, time.Minute, item.ErrInvalidInterval},
+		{"valid", now.Add(-24 * time.Hour), now, time.Hour, nil},
+	}
+
+	service := NewPriceHistoryService(&MockPriceSnapshotRepository{}, 24*time.Hour, newTestLogger())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.GetPriceCandles(context.Background(), "AK-47", tt.from, tt.to, tt.interval)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPriceHistoryService_RunRetention(t *testing.T) {
+	repo := &MockPriceSnapshotRepository{}
+	service := NewPriceHistoryService(repo, 48*time.Hour, newTestLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.RunRetention(ctx, time.Hour)
+		close(done)
+	}()
+
+	// Первая очистка выполняется сразу при запуске
+	deadline := time.After(time.Second)
+	for {
+		repo.mu.Lock()
+		deletedAt := repo.deletedAt
+		repo.mu.Unlock()
+
+		if !deletedAt.IsZero() {
+			expected := time.Now().Add(-48 * time.Hour)
+			if deletedAt.Sub(expected).Abs() > time.Minute {
+				t.Errorf("expected cutoff near %s, got %s", expected, deletedAt)
+			}
+			break
+		}
+
+		select {
+		case <-deadline:
+			t.Fatal("retention did not run")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// SaleEventApplier применяет события ленты продаж к каталогу
+type SaleEventApplier interface {
+	ApplySaleEvents(ctx context.Context, events []*item.SaleEvent)
+}
+
+// SaleFeedServiceImpl реализует сервис ленты продаж: обновляет каталог и раздает события подписчикам
+type SaleFeedServiceImpl struct {
+	feed       output.SaleFeed
+	catalogue  SaleEventApplier
+	bufferSize int
+	logger     *slog.Logger
+
+	mu          sync.Mutex
+	subscribers map[chan *item.SaleEvent]struct{}
+}
+
+// NewSaleFeedService создает новый экземпляр SaleFeedService.
+// bufferSize — сколько событий может накопиться у подписчика, прежде чем он будет отключен.
+func NewSaleFeedService(
+	feed output.SaleFeed,
+	catalogue SaleEventApplier,
+	bufferSize int,
+	logger *slog.Logger,
+) *SaleFeedServiceImpl {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &SaleFeedServiceImpl{
+		feed:        feed,
+		catalogue:   catalogue,
+		bufferSize:  bufferSize,
+		logger:      logger,
+		subscribers: make(map[chan *item.SaleEvent]struct{}),
+	}
+}
+
+// Run подписывается на внешнюю ленту и блокируется до отмены контекста
+func (s *SaleFeedServiceImpl) Run(ctx context.Context) error {
+	return s.feed.Subscribe(ctx, s.handle)
+}
+
+func (s *SaleFeedServiceImpl) handle(ctx context.Context, events []*item.SaleEvent) {
+	s.catalogue.ApplySaleEvents(ctx, events)
+	s.publish(events)
+}
+
+// Subscribe подписывает клиента на события ленты продаж.
+// Канал закрывается при отмене контекста или при переполнении буфера подписчика.
+func (s *SaleFeedServiceImpl) Subscribe(ctx context.Context) <-chan *item.SaleEvent {
+	ch := make(chan *item.SaleEvent, s.bufferSize)
+
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.unsubscribe(ch)
+	}()
+
+	return ch
+}
+
+// publish раздает события подписчикам без блокировки.
+// Медленный подписчик, у которого заполнен буфер, отключается, чтобы не тормозить остальных.
+func (s *SaleFeedServiceImpl) publish(events []*item.SaleEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers {
+	send:
+		for _, ev := range events {
+			select {
+			case ch <- ev:
+			default:
+				s.logger.Warn("dropping slow sale stream subscriber", slog.Int("buffer", s.bufferSize))
+				delete(s.subscribers, ch)
+				close(ch)
+				break send
+			}
+		}
+	}
+}
+
+func (s *SaleFeedServiceImpl) unsubscribe(ch chan *item.SaleEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[ch]; ok {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,144 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+type MockSaleFeed struct {
+	batches [][]*item.SaleEvent
+}
+
+func (m *MockSaleFeed) Subscribe(ctx context.Context, handler output.SaleEventHandler) error {
+	for _, batch := range m.batches {
+		handler(ctx, batch)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+type MockSaleEventApplier struct {
+	mu      sync.Mutex
+	applied int
+}
+
+func (m *MockSaleEventApplier) ApplySaleEvents(_ context.Context, events []*item.SaleEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.applied += len(events)
+}
+
+func newSaleEvents(t *testing.T, n int) []*item.SaleEvent {
+	t.Helper()
+
+	events := make([]*item.SaleEvent, n)
+	for i := range events {
+		ev, err := item.NewSaleEvent(item.SaleEventListed, int64(i), "AK-47", "USD", decimal.NewFromInt(1), true, time.Now())
+		if err != nil {
+			t.Fatalf("failed to create event: %v", err)
+		}
+		events[i] = ev
+	}
+	return events
+}
+
+func TestSaleFeedService_FanOut(t *testing.T) {
+	service := NewSaleFeedService(&MockSaleFeed{}, &MockSaleEventApplier{}, 10, newTestLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := service.Subscribe(ctx)
+	second := service.Subscribe(ctx)
+
+	service.handle(ctx, newSaleEvents(t, 3))
+
+	for _, ch := range []<-chan *item.SaleEvent{first, second} {
+		for i := 0; i < 3; i++ {
+			ev := <-ch
+			if ev.SaleID != int64(i) {
+				t.Errorf("expected sale %d, got %d", i, ev.SaleID)
+			}
+		}
+	}
+}
+
+func TestSaleFeedService_DropsSlowSubscriber(t *testing.T) {
+	service := NewSaleFeedService(&MockSaleFeed{}, &MockSaleEventApplier{}, 2, newTestLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	slow := service.Subscribe(ctx)
+	fast := service.Subscribe(ctx)
+
+	service.handle(ctx, newSaleEvents(t, 2))
+	<-fast
+	<-fast
+
+	// Буфер медленного подписчика уже заполнен — следующее событие его отключает
+	service.handle(ctx, newSaleEvents(t, 1))
+
+	received := 0
+	for range slow {
+		received++
+	}
+	if received != 2 {
+		t.Errorf("expected slow subscriber to receive buffered events before close, got %d", received)
+	}
+
+	select {
+	case ev, ok := <-fast:
+		if !ok || ev == nil {
+			t.Error("expected fast subscriber to stay connected")
+		}
+	case <-time.After(time.Second):
+		t.Error("expected event for fast subscriber")
+	}
+}
+
+func TestSaleFeedService_UnsubscribeOnCancel(t *testing.T) {
+	service := NewSaleFeedService(&MockSaleFeed{}, &MockSaleEventApplier{}, 1, newTestLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := service.Subscribe(ctx)
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("expected channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected channel to be closed after cancel")
+	}
+
+	// Публикация после отписки не должна паниковать на закрытом канале
+	service.handle(context.Background(), newSaleEvents(t, 1))
+}
+
+func TestSaleFeedService_RunAppliesEvents(t *testing.T) {
+	applier := &MockSaleEventApplier{}
+	feed := &MockSaleFeed{batches: [][]*item.SaleEvent{newSaleEvents(t, 2), newSaleEvents(t, 3)}}
+	service := NewSaleFeedService(feed, applier, 10, newTestLogger())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := service.Run(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	applier.mu.Lock()
+	defer applier.mu.Unlock()
+	if applier.applied != 5 {
+		t.Errorf("expected 5 applied events, got %d", applier.applied)
+	}
+}
@@ -0,0 +1,71 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+const salesHistoryCacheKey = "skinport:sales_history"
+
+// SalesHistoryServiceImpl реализует сервис истории продаж предметов
+type SalesHistoryServiceImpl struct {
+	fetcher output.SalesHistoryFetcher
+	loader  *CacheLoader[string, map[string]*item.SalesHistory] // защита от thundering herd
+}
+
+// NewSalesHistoryService создает новый экземпляр SalesHistoryService
+func NewSalesHistoryService(
+	fetcher output.SalesHistoryFetcher,
+	cache output.TypedCache[string, map[string]*item.SalesHistory],
+	cacheTTL time.Duration,
+) *SalesHistoryServiceImpl {
+	// Свой TTL — история меняется реже каталога
+	return &SalesHistoryServiceImpl{
+		fetcher: fetcher,
+		loader:  NewCacheLoader(cache, cacheTTL),
+	}
+}
+
+// SetLocker включает распределенную блокировку загрузки истории продаж между репликами.
+// Вызывается при инициализации.
+func (s *SalesHistoryServiceImpl) SetLocker(locker output.Locker, wait time.Duration) {
+	s.loader.SetLocker(locker, wait)
+}
+
+// GetSalesHistory возвращает историю продаж предмета по market_hash_name
+func (s *SalesHistoryServiceImpl) GetSalesHistory(ctx context.Context, marketHashName string) (*item.SalesHistory, error) {
+	histories, err := s.GetSalesHistories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history, ok := histories[marketHashName]
+	if !ok {
+		return nil, item.ErrItemNotFound
+	}
+
+	return history, nil
+}
+
+// GetSalesHistories возвращает историю продаж всех предметов, индексированную по market_hash_name
+func (s *SalesHistoryServiceImpl) GetSalesHistories(ctx context.Context) (map[string]*item.SalesHistory, error) {
+	return s.loader.GetOrLoad(ctx, salesHistoryCacheKey, s.fetchSalesHistories)
+}
+
+// fetchSalesHistories запрашивает историю продаж из API и индексирует ее по market_hash_name
+func (s *SalesHistoryServiceImpl) fetchSalesHistories(ctx context.Context) (map[string]*item.SalesHistory, error) {
+	list, err := s.fetcher.FetchSalesHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make(map[string]*item.SalesHistory, len(list))
+	for _, h := range list {
+		histories[h.MarketHashName] = h
+	}
+
+	return histories, nil
+}
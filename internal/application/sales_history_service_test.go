@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+type MockSalesHistoryFetcher struct {
+	histories []*item.SalesHistory
+	err       error
+	calls     int
+}
+
+func (m *MockSalesHistoryFetcher) FetchSalesHistory(ctx context.Context) ([]*item.SalesHistory, error) {
+	m.calls++
+	return m.histories, m.err
+}
+
+func TestSalesHistoryService_GetSalesHistory_FromAPI(t *testing.T) {
+	median := decimal.NewFromFloat(12.8)
+	fetcher := &MockSalesHistoryFetcher{
+		histories: []*item.SalesHistory{
+			{MarketHashName: "AK-47", Last24Hours: item.SalesStats{Median: &median, Volume: 42}},
+			{MarketHashName: "AWP"},
+		},
+	}
+	cache := NewMockCache()
+
+	service := NewSalesHistoryService(fetcher, salesHistoryCache(cache), 10*time.Minute)
+
+	history, err := service.GetSalesHistory(context.Background(), "AK-47")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if history.Last24Hours.Volume != 42 || !history.Last24Hours.Median.Equal(median) {
+		t.Errorf("unexpected history: %+v", history.Last24Hours)
+	}
+
+	if _, ok := cache.Get(context.Background(), "skinport:sales_history"); !ok {
+		t.Error("expected sales history to be cached")
+	}
+
+	// Повторный запрос должен обслуживаться из кэша
+	if _, err := service.GetSalesHistory(context.Background(), "AWP"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if fetcher.calls != 1 {
+		t.Errorf("expected 1 fetch, got %d", fetcher.calls)
+	}
+}
+
+func TestSalesHistoryService_GetSalesHistory_NotFound(t *testing.T) {
+	fetcher := &MockSalesHistoryFetcher{
+		histories: []*item.SalesHistory{{MarketHashName: "AK-47"}},
+	}
+
+	service := NewSalesHistoryService(fetcher, salesHistoryCache(NewMockCache()), 10*time.Minute)
+
+	_, err := service.GetSalesHistory(context.Background(), "M4A4 | Howl")
+	if !errors.Is(err, item.ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound, got %v", err)
+	}
+}
+
+func TestSalesHistoryService_GetSalesHistory_FetchError(t *testing.T) {
+	expectedError := errors.New("fetch failed")
+	fetcher := &MockSalesHistoryFetcher{err: expectedError}
+
+	service := NewSalesHistoryService(fetcher, salesHistoryCache(NewMockCache()), 10*time.Minute)
+
+	_, err := service.GetSalesHistory(context.Background(), "AK-47")
+	if !errors.Is(err, expectedError) {
+		t.Errorf("expected error %v, got %v", expectedError, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -11,11 +12,22 @@ import (
 
 // Config представляет конфигурацию приложения
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	Cache    CacheConfig    `yaml:"cache"`
-	Skinport SkinportConfig `yaml:"skinport"`
-	Log      LogConfig      `yaml:"log"`
+	Server         ServerConfig         `yaml:"server"`
+	Database       DatabaseConfig       `yaml:"database"`
+	Cache          CacheConfig          `yaml:"cache"`
+	RefreshLock    RefreshLockConfig    `yaml:"refresh_lock"`
+	CatalogueStore CatalogueStoreConfig `yaml:"catalogue_store"`
+	Skinport       SkinportConfig       `yaml:"skinport"`
+	SaleFeed       SaleFeedConfig       `yaml:"sale_feed"`
+	PriceHistory   PriceHistoryConfig   `yaml:"price_history"`
+	Alerts         AlertsConfig         `yaml:"alerts"`
+	Insights       InsightsConfig       `yaml:"insights"`
+	Auth           AuthConfig           `yaml:"auth"`
+	RateLimit      RateLimitConfig      `yaml:"rate_limit"`
+	Metrics        MetricsConfig        `yaml:"metrics"`
+	Tracing        TracingConfig        `yaml:"tracing"`
+	Health         HealthConfig         `yaml:"health"`
+	Log            LogConfig            `yaml:"log"`
 }
 
 // ServerConfig конфигурация HTTP сервера
@@ -24,6 +36,9 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration `yaml:"read_timeout"`
 	WriteTimeout    time.Duration `yaml:"write_timeout"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// DrainDelay сколько /readyz отвечает 503 перед остановкой сервера, чтобы балансировщик
+	// успел снять реплику
+	DrainDelay time.Duration `yaml:"drain_delay"`
 }
 
 // DatabaseConfig конфигурация базы данных
@@ -34,17 +49,210 @@ type DatabaseConfig struct {
 	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
 }
 
+// Бэкенды кэша
+const (
+	CacheBackendMemory = "memory"
+	CacheBackendRedis  = "redis"
+	CacheBackendTiered = "tiered"
+)
+
+// Кодеки сериализации значений в Redis
+const (
+	RedisCodecJSON = "json"
+	RedisCodecGob  = "gob"
+)
+
 // CacheConfig конфигурация кэша
 type CacheConfig struct {
-	TTL time.Duration `yaml:"ttl"`
+	Backend         string        `yaml:"backend"`
+	TTL             time.Duration `yaml:"ttl"`
+	SalesHistoryTTL time.Duration `yaml:"sales_history_ttl"`
+	// MaxEntries и MaxBytes ограничивают in-memory кэш; 0 — без ограничения,
+	// nil — значение по умолчанию
+	MaxEntries *int          `yaml:"max_entries"`
+	MaxBytes   *int64        `yaml:"max_bytes"`
+	LocalTTL   time.Duration `yaml:"local_ttl"`
+	Redis      RedisConfig   `yaml:"redis"`
+}
+
+// RedisConfig конфигурация подключения к Redis
+type RedisConfig struct {
+	Addr                string        `yaml:"addr"`
+	Password            string        `yaml:"password"`
+	DB                  int           `yaml:"db"`
+	KeyPrefix           string        `yaml:"key_prefix"`
+	Codec               string        `yaml:"codec"`
+	PoolSize            int           `yaml:"pool_size"`
+	DialTimeout         time.Duration `yaml:"dial_timeout"`
+	IOTimeout           time.Duration `yaml:"io_timeout"`
+	InvalidationChannel string        `yaml:"invalidation_channel"`
+}
+
+// RefreshLockConfig конфигурация распределенной блокировки обновления данных Skinport
+type RefreshLockConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Wait    time.Duration `yaml:"wait"`
+}
+
+// Бэкенды хранилища каталога
+const (
+	CatalogueStoreNone     = "none"
+	CatalogueStorePostgres = "postgres"
+	CatalogueStoreFile     = "file"
+)
+
+// CatalogueStoreConfig конфигурация хранения последнего каталога между перезапусками
+type CatalogueStoreConfig struct {
+	Backend string        `yaml:"backend"`
+	Path    string        `yaml:"path"`
+	MaxAge  time.Duration `yaml:"max_age"`
 }
 
+// Режимы работы с Skinport API
+const (
+	SkinportModeLive    = "live"
+	SkinportModeFixture = "fixture"
+	SkinportModeRecord  = "record"
+)
+
 // SkinportConfig конфигурация Skinport API
 type SkinportConfig struct {
-	APIURL  string        `yaml:"api_url"`
+	APIURL      string        `yaml:"api_url"`
+	Timeout     time.Duration `yaml:"timeout"`
+	Mode        string        `yaml:"mode"`
+	FixturesDir string        `yaml:"fixtures_dir"`
+	// BreakerThreshold число неудачных обращений подряд, после которого запросы к API
+	// не отправляются в течение BreakerCooldown
+	BreakerThreshold int           `yaml:"breaker_threshold"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`
+}
+
+// SaleFeedConfig конфигурация ленты продаж Skinport в реальном времени
+type SaleFeedConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	URL          string        `yaml:"url"`
+	Currency     string        `yaml:"currency"`
+	Locale       string        `yaml:"locale"`
+	ClientBuffer int           `yaml:"client_buffer"`
+	Heartbeat    time.Duration `yaml:"heartbeat"`
+	// FlushInterval как часто накопленные события применяются к каталогу в памяти
+	FlushInterval time.Duration `yaml:"flush_interval"`
+}
+
+// PriceHistoryConfig конфигурация собственной истории цен
+type PriceHistoryConfig struct {
+	Retention       time.Duration `yaml:"retention"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval"`
+	BatchSize       int           `yaml:"batch_size"`
+}
+
+// AlertsConfig конфигурация ценовых алертов и доставки вебхуков
+type AlertsConfig struct {
+	WebhookSecret  string        `yaml:"webhook_secret"`
+	WebhookTimeout time.Duration `yaml:"webhook_timeout"`
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+}
+
+// InsightsConfig конфигурация аналитики по каталогу
+type InsightsConfig struct {
+	// FeePercent комиссия площадки в процентах; 0 — площадка без комиссии, nil — значение по умолчанию
+	FeePercent  *float64 `yaml:"fee_percent"`
+	MinQuantity int      `yaml:"min_quantity"`
+}
+
+// AuthConfig конфигурация аутентификации пользовательских эндпоинтов
+type AuthConfig struct {
+	// Disabled отключает проверку: все запросы считаются запросами администратора
+	Disabled bool      `yaml:"disabled"`
+	JWT      JWTConfig `yaml:"jwt"`
+	// APIKeys статические ключи вида "key:subject[:scope+scope]" через запятую
+	APIKeys string `yaml:"api_keys"`
+}
+
+// JWTConfig конфигурация проверки JWT. Можно задать любое сочетание источников ключей.
+type JWTConfig struct {
+	HS256Secret        string        `yaml:"hs256_secret"`
+	RS256PublicKeyFile string        `yaml:"rs256_public_key_file"`
+	JWKSFile           string        `yaml:"jwks_file"`
+	Issuer             string        `yaml:"issuer"`
+	Audience           string        `yaml:"audience"`
+	Leeway             time.Duration `yaml:"leeway"`
+}
+
+// Enabled сообщает, задан ли хотя бы один источник ключей JWT
+func (c JWTConfig) Enabled() bool {
+	return c.HS256Secret != "" || c.RS256PublicKeyFile != "" || c.JWKSFile != ""
+}
+
+// Хранилища бюджетов ограничения частоты запросов
+const (
+	RateLimitBackendMemory = "memory"
+	RateLimitBackendRedis  = "redis"
+)
+
+// RateLimitConfig конфигурация ограничения частоты запросов
+type RateLimitConfig struct {
+	Disabled bool   `yaml:"disabled"`
+	Backend  string `yaml:"backend"`
+	// RedisPrefix префикс ключей bucket'ов в Redis. Не должен пересекаться с префиксом кэша:
+	// иначе очистка кэша сбрасывает бюджеты клиентов
+	RedisPrefix string `yaml:"redis_prefix"`
+	// TrustedProxies адреса или CIDR прокси через запятую, которым доверяется X-Forwarded-For
+	TrustedProxies string          `yaml:"trusted_proxies"`
+	Read           RateLimitBudget `yaml:"read"`
+	Money          RateLimitBudget `yaml:"money"`
+	Default        RateLimitBudget `yaml:"default"`
+	// IP бюджет адреса клиента на все защищенные маршруты, расходуется до аутентификации
+	IP RateLimitBudget `yaml:"ip"`
+}
+
+// RateLimitBudget скорость пополнения и емкость token bucket'а
+type RateLimitBudget struct {
+	RPS   float64 `yaml:"rps"`
+	Burst int     `yaml:"burst"`
+}
+
+// MetricsConfig конфигурация эндпоинта метрик Prometheus
+type MetricsConfig struct {
+	// Disabled убирает GET /metrics и не собирает метрики HTTP запросов
+	Disabled bool `yaml:"disabled"`
+}
+
+// Экспортеры трасс
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterOTLP   = "otlp"
+)
+
+// TracingConfig конфигурация трассировки запросов
+type TracingConfig struct {
+	// Exporter куда отправляются span'ы: none (только проброс traceparent), stdout или otlp
+	Exporter    string `yaml:"exporter"`
+	ServiceName string `yaml:"service_name"`
+	// SampleRatio доля сэмплируемых корневых трасс в [0, 1]; 0 — новые трассы не записываются,
+	// nil — значение по умолчанию 1
+	SampleRatio *float64          `yaml:"sample_ratio"`
+	OTLP        TracingOTLPConfig `yaml:"otlp"`
+}
+
+// TracingOTLPConfig параметры коллектора OpenTelemetry (OTLP/HTTP)
+type TracingOTLPConfig struct {
+	Endpoint string `yaml:"endpoint"`
+	// Headers заголовки вида "key=value,key2=value2"
+	Headers string        `yaml:"headers"`
 	Timeout time.Duration `yaml:"timeout"`
 }
 
+// HealthConfig конфигурация проверки готовности GET /readyz
+type HealthConfig struct {
+	CheckTimeout time.Duration `yaml:"check_timeout"`
+	// CatalogueMaxAge возраст каталога, после которого реплика не готова, если последнее
+	// обновление из Skinport завершилось ошибкой
+	CatalogueMaxAge time.Duration `yaml:"catalogue_max_age"`
+}
+
 // LogConfig конфигурация логирования
 type LogConfig struct {
 	Level  string `yaml:"level"`
@@ -110,6 +318,11 @@ func (c *Config) loadFromEnv() {
 			c.Server.ShutdownTimeout = d
 		}
 	}
+	if delay := os.Getenv("SERVER_DRAIN_DELAY"); delay != "" {
+		if d, err := time.ParseDuration(delay); err == nil {
+			c.Server.DrainDelay = d
+		}
+	}
 
 	// Database
 	if url := os.Getenv("DATABASE_URL"); url != "" {
@@ -132,11 +345,96 @@ func (c *Config) loadFromEnv() {
 	}
 
 	// Cache
+	if backend := os.Getenv("CACHE_BACKEND"); backend != "" {
+		c.Cache.Backend = backend
+	}
 	if ttl := os.Getenv("CACHE_TTL"); ttl != "" {
 		if d, err := time.ParseDuration(ttl); err == nil {
 			c.Cache.TTL = d
 		}
 	}
+	if ttl := os.Getenv("CACHE_SALES_HISTORY_TTL"); ttl != "" {
+		if d, err := time.ParseDuration(ttl); err == nil {
+			c.Cache.SalesHistoryTTL = d
+		}
+	}
+	if entries := os.Getenv("CACHE_MAX_ENTRIES"); entries != "" {
+		if n, err := strconv.Atoi(entries); err == nil {
+			c.Cache.MaxEntries = &n
+		}
+	}
+	if bytes := os.Getenv("CACHE_MAX_BYTES"); bytes != "" {
+		if n, err := strconv.ParseInt(bytes, 10, 64); err == nil {
+			c.Cache.MaxBytes = &n
+		}
+	}
+	if ttl := os.Getenv("CACHE_LOCAL_TTL"); ttl != "" {
+		if d, err := time.ParseDuration(ttl); err == nil {
+			c.Cache.LocalTTL = d
+		}
+	}
+
+	// Redis
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		c.Cache.Redis.Addr = addr
+	}
+	if password := os.Getenv("REDIS_PASSWORD"); password != "" {
+		c.Cache.Redis.Password = password
+	}
+	if db := os.Getenv("REDIS_DB"); db != "" {
+		if n, err := strconv.Atoi(db); err == nil {
+			c.Cache.Redis.DB = n
+		}
+	}
+	if prefix := os.Getenv("REDIS_KEY_PREFIX"); prefix != "" {
+		c.Cache.Redis.KeyPrefix = prefix
+	}
+	if codec := os.Getenv("REDIS_CODEC"); codec != "" {
+		c.Cache.Redis.Codec = codec
+	}
+	if size := os.Getenv("REDIS_POOL_SIZE"); size != "" {
+		if n, err := strconv.Atoi(size); err == nil {
+			c.Cache.Redis.PoolSize = n
+		}
+	}
+	if timeout := os.Getenv("REDIS_DIAL_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			c.Cache.Redis.DialTimeout = d
+		}
+	}
+	if timeout := os.Getenv("REDIS_IO_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			c.Cache.Redis.IOTimeout = d
+		}
+	}
+	if channel := os.Getenv("REDIS_INVALIDATION_CHANNEL"); channel != "" {
+		c.Cache.Redis.InvalidationChannel = channel
+	}
+
+	// Refresh lock
+	if enabled := os.Getenv("REFRESH_LOCK_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.RefreshLock.Enabled = b
+		}
+	}
+	if wait := os.Getenv("REFRESH_LOCK_WAIT"); wait != "" {
+		if d, err := time.ParseDuration(wait); err == nil {
+			c.RefreshLock.Wait = d
+		}
+	}
+
+	// Catalogue store
+	if backend := os.Getenv("CATALOGUE_STORE_BACKEND"); backend != "" {
+		c.CatalogueStore.Backend = backend
+	}
+	if path := os.Getenv("CATALOGUE_STORE_PATH"); path != "" {
+		c.CatalogueStore.Path = path
+	}
+	if maxAge := os.Getenv("CATALOGUE_STORE_MAX_AGE"); maxAge != "" {
+		if d, err := time.ParseDuration(maxAge); err == nil {
+			c.CatalogueStore.MaxAge = d
+		}
+	}
 
 	// Skinport
 	if url := os.Getenv("SKINPORT_API_URL"); url != "" {
@@ -147,6 +445,195 @@ func (c *Config) loadFromEnv() {
 			c.Skinport.Timeout = d
 		}
 	}
+	if mode := os.Getenv("SKINPORT_MODE"); mode != "" {
+		c.Skinport.Mode = mode
+	}
+	if dir := os.Getenv("SKINPORT_FIXTURES_DIR"); dir != "" {
+		c.Skinport.FixturesDir = dir
+	}
+	if threshold := os.Getenv("SKINPORT_BREAKER_THRESHOLD"); threshold != "" {
+		if n, err := strconv.Atoi(threshold); err == nil {
+			c.Skinport.BreakerThreshold = n
+		}
+	}
+	if cooldown := os.Getenv("SKINPORT_BREAKER_COOLDOWN"); cooldown != "" {
+		if d, err := time.ParseDuration(cooldown); err == nil {
+			c.Skinport.BreakerCooldown = d
+		}
+	}
+
+	// Sale feed
+	if enabled := os.Getenv("SALE_FEED_ENABLED"); enabled != "" {
+		if b, err := strconv.ParseBool(enabled); err == nil {
+			c.SaleFeed.Enabled = b
+		}
+	}
+	if url := os.Getenv("SALE_FEED_URL"); url != "" {
+		c.SaleFeed.URL = url
+	}
+	if currency := os.Getenv("SALE_FEED_CURRENCY"); currency != "" {
+		c.SaleFeed.Currency = currency
+	}
+	if locale := os.Getenv("SALE_FEED_LOCALE"); locale != "" {
+		c.SaleFeed.Locale = locale
+	}
+	if buffer := os.Getenv("SALE_FEED_CLIENT_BUFFER"); buffer != "" {
+		if n, err := strconv.Atoi(buffer); err == nil {
+			c.SaleFeed.ClientBuffer = n
+		}
+	}
+	if heartbeat := os.Getenv("SALE_FEED_HEARTBEAT"); heartbeat != "" {
+		if d, err := time.ParseDuration(heartbeat); err == nil {
+			c.SaleFeed.Heartbeat = d
+		}
+	}
+	if interval := os.Getenv("SALE_FEED_FLUSH_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			c.SaleFeed.FlushInterval = d
+		}
+	}
+
+	// Price history
+	if retention := os.Getenv("PRICE_HISTORY_RETENTION"); retention != "" {
+		if d, err := time.ParseDuration(retention); err == nil {
+			c.PriceHistory.Retention = d
+		}
+	}
+	if interval := os.Getenv("PRICE_HISTORY_CLEANUP_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			c.PriceHistory.CleanupInterval = d
+		}
+	}
+	if size := os.Getenv("PRICE_HISTORY_BATCH_SIZE"); size != "" {
+		if n, err := strconv.Atoi(size); err == nil {
+			c.PriceHistory.BatchSize = n
+		}
+	}
+
+	// Alerts
+	if secret := os.Getenv("ALERTS_WEBHOOK_SECRET"); secret != "" {
+		c.Alerts.WebhookSecret = secret
+	}
+	if timeout := os.Getenv("ALERTS_WEBHOOK_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			c.Alerts.WebhookTimeout = d
+		}
+	}
+	if attempts := os.Getenv("ALERTS_MAX_ATTEMPTS"); attempts != "" {
+		if n, err := strconv.Atoi(attempts); err == nil {
+			c.Alerts.MaxAttempts = n
+		}
+	}
+	if backoff := os.Getenv("ALERTS_INITIAL_BACKOFF"); backoff != "" {
+		if d, err := time.ParseDuration(backoff); err == nil {
+			c.Alerts.InitialBackoff = d
+		}
+	}
+
+	// Insights
+	if fee := os.Getenv("INSIGHTS_FEE_PERCENT"); fee != "" {
+		if f, err := strconv.ParseFloat(fee, 64); err == nil {
+			c.Insights.FeePercent = &f
+		}
+	}
+	if quantity := os.Getenv("INSIGHTS_MIN_QUANTITY"); quantity != "" {
+		if n, err := strconv.Atoi(quantity); err == nil {
+			c.Insights.MinQuantity = n
+		}
+	}
+
+	// Auth
+	if disabled := os.Getenv("AUTH_DISABLED"); disabled != "" {
+		if b, err := strconv.ParseBool(disabled); err == nil {
+			c.Auth.Disabled = b
+		}
+	}
+	if secret := os.Getenv("AUTH_JWT_HS256_SECRET"); secret != "" {
+		c.Auth.JWT.HS256Secret = secret
+	}
+	if path := os.Getenv("AUTH_JWT_RS256_PUBLIC_KEY_FILE"); path != "" {
+		c.Auth.JWT.RS256PublicKeyFile = path
+	}
+	if path := os.Getenv("AUTH_JWT_JWKS_FILE"); path != "" {
+		c.Auth.JWT.JWKSFile = path
+	}
+	if issuer := os.Getenv("AUTH_JWT_ISSUER"); issuer != "" {
+		c.Auth.JWT.Issuer = issuer
+	}
+	if audience := os.Getenv("AUTH_JWT_AUDIENCE"); audience != "" {
+		c.Auth.JWT.Audience = audience
+	}
+	if leeway := os.Getenv("AUTH_JWT_LEEWAY"); leeway != "" {
+		if d, err := time.ParseDuration(leeway); err == nil {
+			c.Auth.JWT.Leeway = d
+		}
+	}
+	if keys := os.Getenv("AUTH_API_KEYS"); keys != "" {
+		c.Auth.APIKeys = keys
+	}
+
+	// Rate limit
+	if disabled := os.Getenv("RATE_LIMIT_DISABLED"); disabled != "" {
+		if b, err := strconv.ParseBool(disabled); err == nil {
+			c.RateLimit.Disabled = b
+		}
+	}
+	if backend := os.Getenv("RATE_LIMIT_BACKEND"); backend != "" {
+		c.RateLimit.Backend = backend
+	}
+	if prefix := os.Getenv("RATE_LIMIT_REDIS_PREFIX"); prefix != "" {
+		c.RateLimit.RedisPrefix = prefix
+	}
+	if proxies := os.Getenv("RATE_LIMIT_TRUSTED_PROXIES"); proxies != "" {
+		c.RateLimit.TrustedProxies = proxies
+	}
+	loadBudgetFromEnv("RATE_LIMIT_READ", &c.RateLimit.Read)
+	loadBudgetFromEnv("RATE_LIMIT_MONEY", &c.RateLimit.Money)
+	loadBudgetFromEnv("RATE_LIMIT_DEFAULT", &c.RateLimit.Default)
+	loadBudgetFromEnv("RATE_LIMIT_IP", &c.RateLimit.IP)
+
+	// Metrics
+	if disabled := os.Getenv("METRICS_DISABLED"); disabled != "" {
+		if b, err := strconv.ParseBool(disabled); err == nil {
+			c.Metrics.Disabled = b
+		}
+	}
+
+	// Tracing
+	if exporter := os.Getenv("TRACING_EXPORTER"); exporter != "" {
+		c.Tracing.Exporter = exporter
+	}
+	if name := os.Getenv("TRACING_SERVICE_NAME"); name != "" {
+		c.Tracing.ServiceName = name
+	}
+	if ratio := os.Getenv("TRACING_SAMPLE_RATIO"); ratio != "" {
+		if f, err := strconv.ParseFloat(ratio, 64); err == nil {
+			c.Tracing.SampleRatio = &f
+		}
+	}
+	if endpoint := os.Getenv("TRACING_OTLP_ENDPOINT"); endpoint != "" {
+		c.Tracing.OTLP.Endpoint = endpoint
+	}
+	if headers := os.Getenv("TRACING_OTLP_HEADERS"); headers != "" {
+		c.Tracing.OTLP.Headers = headers
+	}
+	if timeout := os.Getenv("TRACING_OTLP_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			c.Tracing.OTLP.Timeout = d
+		}
+	}
+
+	// Health
+	if timeout := os.Getenv("HEALTH_CHECK_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			c.Health.CheckTimeout = d
+		}
+	}
+	if maxAge := os.Getenv("HEALTH_CATALOGUE_MAX_AGE"); maxAge != "" {
+		if d, err := time.ParseDuration(maxAge); err == nil {
+			c.Health.CatalogueMaxAge = d
+		}
+	}
 
 	// Log
 	if level := os.Getenv("LOG_LEVEL"); level != "" {
@@ -157,6 +644,20 @@ func (c *Config) loadFromEnv() {
 	}
 }
 
+// loadBudgetFromEnv читает пару переменных <prefix>_RPS и <prefix>_BURST
+func loadBudgetFromEnv(prefix string, b *RateLimitBudget) {
+	if rps := os.Getenv(prefix + "_RPS"); rps != "" {
+		if f, err := strconv.ParseFloat(rps, 64); err == nil {
+			b.RPS = f
+		}
+	}
+	if burst := os.Getenv(prefix + "_BURST"); burst != "" {
+		if n, err := strconv.Atoi(burst); err == nil {
+			b.Burst = n
+		}
+	}
+}
+
 func (c *Config) setDefaults() {
 	// Server defaults
 	if c.Server.Port == 0 {
@@ -171,6 +672,9 @@ func (c *Config) setDefaults() {
 	if c.Server.ShutdownTimeout == 0 {
 		c.Server.ShutdownTimeout = 5 * time.Second
 	}
+	if c.Server.DrainDelay == 0 {
+		c.Server.DrainDelay = 5 * time.Second
+	}
 
 	// Database defaults
 	if c.Database.URL == "" {
@@ -187,9 +691,64 @@ func (c *Config) setDefaults() {
 	}
 
 	// Cache defaults
+	if c.Cache.Backend == "" {
+		c.Cache.Backend = CacheBackendMemory
+	}
 	if c.Cache.TTL == 0 {
 		c.Cache.TTL = 5 * time.Minute
 	}
+	if c.Cache.SalesHistoryTTL == 0 {
+		c.Cache.SalesHistoryTTL = 10 * time.Minute
+	}
+	// Явный 0 отключает лимит, поэтому умолчание подставляется только для незаданного значения
+	if c.Cache.MaxEntries == nil {
+		c.Cache.MaxEntries = ptr(10000)
+	}
+	if c.Cache.MaxBytes == nil {
+		c.Cache.MaxBytes = ptr[int64](256 << 20)
+	}
+	if c.Cache.LocalTTL == 0 {
+		c.Cache.LocalTTL = 30 * time.Second
+	}
+
+	// Redis defaults
+	if c.Cache.Redis.Addr == "" {
+		c.Cache.Redis.Addr = "localhost:6379"
+	}
+	if c.Cache.Redis.KeyPrefix == "" {
+		c.Cache.Redis.KeyPrefix = "ddd_example:"
+	}
+	if c.Cache.Redis.Codec == "" {
+		c.Cache.Redis.Codec = RedisCodecJSON
+	}
+	if c.Cache.Redis.PoolSize == 0 {
+		c.Cache.Redis.PoolSize = 10
+	}
+	if c.Cache.Redis.DialTimeout == 0 {
+		c.Cache.Redis.DialTimeout = 5 * time.Second
+	}
+	if c.Cache.Redis.IOTimeout == 0 {
+		c.Cache.Redis.IOTimeout = 3 * time.Second
+	}
+	if c.Cache.Redis.InvalidationChannel == "" {
+		c.Cache.Redis.InvalidationChannel = "ddd_example:cache:invalidate"
+	}
+
+	// Refresh lock defaults
+	if c.RefreshLock.Wait == 0 {
+		c.RefreshLock.Wait = 30 * time.Second
+	}
+
+	// Catalogue store defaults
+	if c.CatalogueStore.Backend == "" {
+		c.CatalogueStore.Backend = CatalogueStorePostgres
+	}
+	if c.CatalogueStore.Path == "" {
+		c.CatalogueStore.Path = "data/catalogue.json.gz"
+	}
+	if c.CatalogueStore.MaxAge == 0 {
+		c.CatalogueStore.MaxAge = 24 * time.Hour
+	}
 
 	// Skinport defaults
 	if c.Skinport.APIURL == "" {
@@ -198,6 +757,110 @@ func (c *Config) setDefaults() {
 	if c.Skinport.Timeout == 0 {
 		c.Skinport.Timeout = 30 * time.Second
 	}
+	if c.Skinport.Mode == "" {
+		c.Skinport.Mode = SkinportModeLive
+	}
+	if c.Skinport.FixturesDir == "" {
+		c.Skinport.FixturesDir = "fixtures/skinport"
+	}
+	if c.Skinport.BreakerThreshold == 0 {
+		c.Skinport.BreakerThreshold = 5
+	}
+	if c.Skinport.BreakerCooldown == 0 {
+		c.Skinport.BreakerCooldown = 30 * time.Second
+	}
+
+	// Sale feed defaults
+	if c.SaleFeed.URL == "" {
+		c.SaleFeed.URL = "wss://skinport.com"
+	}
+	if c.SaleFeed.Currency == "" {
+		c.SaleFeed.Currency = "USD"
+	}
+	if c.SaleFeed.Locale == "" {
+		c.SaleFeed.Locale = "en"
+	}
+	if c.SaleFeed.ClientBuffer == 0 {
+		c.SaleFeed.ClientBuffer = 64
+	}
+	if c.SaleFeed.Heartbeat == 0 {
+		c.SaleFeed.Heartbeat = 15 * time.Second
+	}
+	if c.SaleFeed.FlushInterval == 0 {
+		c.SaleFeed.FlushInterval = 5 * time.Second
+	}
+
+	// Price history defaults
+	if c.PriceHistory.Retention == 0 {
+		c.PriceHistory.Retention = 30 * 24 * time.Hour
+	}
+	if c.PriceHistory.CleanupInterval == 0 {
+		c.PriceHistory.CleanupInterval = time.Hour
+	}
+	if c.PriceHistory.BatchSize == 0 {
+		c.PriceHistory.BatchSize = 1000
+	}
+
+	// Alerts defaults
+	if c.Alerts.WebhookTimeout == 0 {
+		c.Alerts.WebhookTimeout = 5 * time.Second
+	}
+	if c.Alerts.MaxAttempts == 0 {
+		c.Alerts.MaxAttempts = 5
+	}
+	if c.Alerts.InitialBackoff == 0 {
+		c.Alerts.InitialBackoff = time.Second
+	}
+
+	// Insights defaults
+	if c.Insights.FeePercent == nil {
+		c.Insights.FeePercent = ptr(12.0)
+	}
+	if c.Insights.MinQuantity == 0 {
+		c.Insights.MinQuantity = 1
+	}
+
+	// Auth defaults
+	if c.Auth.JWT.Leeway == 0 {
+		c.Auth.JWT.Leeway = 30 * time.Second
+	}
+
+	// Rate limit defaults
+	if c.RateLimit.Backend == "" {
+		c.RateLimit.Backend = RateLimitBackendMemory
+	}
+	if c.RateLimit.RedisPrefix == "" {
+		c.RateLimit.RedisPrefix = "ddd_example_ratelimit:"
+	}
+	setBudgetDefaults(&c.RateLimit.Read, 20, 40)
+	setBudgetDefaults(&c.RateLimit.Money, 0.5, 5)
+	setBudgetDefaults(&c.RateLimit.Default, 5, 10)
+	setBudgetDefaults(&c.RateLimit.IP, 50, 100)
+
+	// Tracing defaults
+	if c.Tracing.Exporter == "" {
+		c.Tracing.Exporter = TracingExporterNone
+	}
+	if c.Tracing.ServiceName == "" {
+		c.Tracing.ServiceName = "ddd-example"
+	}
+	if c.Tracing.SampleRatio == nil {
+		c.Tracing.SampleRatio = ptr(1.0)
+	}
+	if c.Tracing.OTLP.Endpoint == "" {
+		c.Tracing.OTLP.Endpoint = "http://localhost:4318"
+	}
+	if c.Tracing.OTLP.Timeout == 0 {
+		c.Tracing.OTLP.Timeout = 10 * time.Second
+	}
+
+	// Health defaults
+	if c.Health.CheckTimeout == 0 {
+		c.Health.CheckTimeout = 2 * time.Second
+	}
+	if c.Health.CatalogueMaxAge == 0 {
+		c.Health.CatalogueMaxAge = time.Hour
+	}
 
 	// Log defaults
 	if c.Log.Level == "" {
@@ -208,6 +871,20 @@ func (c *Config) setDefaults() {
 	}
 }
 
+// ptr возвращает указатель на значение по умолчанию для полей, где nil означает «не задано»
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func setBudgetDefaults(b *RateLimitBudget, rps float64, burst int) {
+	if b.RPS == 0 {
+		b.RPS = rps
+	}
+	if b.Burst == 0 {
+		b.Burst = burst
+	}
+}
+
 func (c *Config) validate() error {
 	if c.Server.Port <= 0 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
@@ -221,5 +898,133 @@ func (c *Config) validate() error {
 		return fmt.Errorf("skinport API URL is required")
 	}
 
+	// Интервалы уходят в time.NewTicker, который паникует на неположительном значении
+	if c.PriceHistory.Retention <= 0 {
+		return fmt.Errorf("invalid price history retention: %s", c.PriceHistory.Retention)
+	}
+	if c.PriceHistory.CleanupInterval <= 0 {
+		return fmt.Errorf("invalid price history cleanup interval: %s", c.PriceHistory.CleanupInterval)
+	}
+
+	// Без секрета подпись вебхука может подделать любой, кто знает формат
+	if c.Alerts.WebhookSecret == "" {
+		return fmt.Errorf("alerts webhook secret is required (set ALERTS_WEBHOOK_SECRET)")
+	}
+	if len(c.Alerts.WebhookSecret) < 32 {
+		return fmt.Errorf("alerts webhook secret must be at least 32 bytes, got %d", len(c.Alerts.WebhookSecret))
+	}
+
+	if c.Alerts.MaxAttempts < 0 {
+		return fmt.Errorf("invalid alerts max attempts: %d", c.Alerts.MaxAttempts)
+	}
+
+	if fee := *c.Insights.FeePercent; fee < 0 || fee >= 100 {
+		return fmt.Errorf("invalid insights fee percent: %v", fee)
+	}
+
+	switch c.Skinport.Mode {
+	case SkinportModeLive, SkinportModeFixture, SkinportModeRecord:
+	default:
+		return fmt.Errorf("invalid skinport mode: %q", c.Skinport.Mode)
+	}
+
+	switch c.Cache.Backend {
+	case CacheBackendMemory, CacheBackendRedis, CacheBackendTiered:
+	default:
+		return fmt.Errorf("invalid cache backend: %q", c.Cache.Backend)
+	}
+
+	switch c.Cache.Redis.Codec {
+	case RedisCodecJSON, RedisCodecGob:
+	default:
+		return fmt.Errorf("invalid redis codec: %q", c.Cache.Redis.Codec)
+	}
+
+	if *c.Cache.MaxEntries < 0 || *c.Cache.MaxBytes < 0 {
+		return fmt.Errorf("invalid cache limits: max entries %d, max bytes %d", *c.Cache.MaxEntries, *c.Cache.MaxBytes)
+	}
+
+	if c.Cache.Redis.PoolSize < 0 {
+		return fmt.Errorf("invalid redis pool size: %d", c.Cache.Redis.PoolSize)
+	}
+
+	if c.RefreshLock.Wait < 0 {
+		return fmt.Errorf("invalid refresh lock wait: %s", c.RefreshLock.Wait)
+	}
+	// С локальным кешем реплика, не захватившая блокировку, не увидит чужое обновление:
+	// она только ждет REFRESH_LOCK_WAIT и отдает прежний каталог
+	if c.RefreshLock.Enabled && c.Cache.Backend == CacheBackendMemory {
+		return fmt.Errorf("refresh lock requires a shared cache backend (redis or tiered), got %q", c.Cache.Backend)
+	}
+
+	switch c.CatalogueStore.Backend {
+	case CatalogueStoreNone, CatalogueStorePostgres, CatalogueStoreFile:
+	default:
+		return fmt.Errorf("invalid catalogue store backend: %q", c.CatalogueStore.Backend)
+	}
+
+	if c.SaleFeed.ClientBuffer < 0 {
+		return fmt.Errorf("invalid sale feed client buffer: %d", c.SaleFeed.ClientBuffer)
+	}
+	if c.SaleFeed.Heartbeat <= 0 {
+		return fmt.Errorf("invalid sale feed heartbeat: %s", c.SaleFeed.Heartbeat)
+	}
+	if c.SaleFeed.FlushInterval <= 0 {
+		return fmt.Errorf("invalid sale feed flush interval: %s", c.SaleFeed.FlushInterval)
+	}
+
+	if c.PriceHistory.BatchSize < 0 {
+		return fmt.Errorf("invalid price history batch size: %d", c.PriceHistory.BatchSize)
+	}
+
+	if !c.Auth.Disabled && !c.Auth.JWT.Enabled() && c.Auth.APIKeys == "" {
+		return fmt.Errorf("auth requires jwt keys or api keys (set AUTH_DISABLED=true to run without auth)")
+	}
+	// HMAC секрет короче выхода SHA-256 ослабляет подпись до перебора
+	if secret := c.Auth.JWT.HS256Secret; secret != "" && len(secret) < 32 {
+		return fmt.Errorf("auth jwt hs256 secret must be at least 32 bytes, got %d", len(secret))
+	}
+	if c.Auth.JWT.Leeway < 0 {
+		return fmt.Errorf("invalid auth jwt leeway: %s", c.Auth.JWT.Leeway)
+	}
+
+	switch c.RateLimit.Backend {
+	case RateLimitBackendMemory, RateLimitBackendRedis:
+	default:
+		return fmt.Errorf("invalid rate limit backend: %q", c.RateLimit.Backend)
+	}
+	if c.RateLimit.Backend == RateLimitBackendRedis {
+		rl, cache := c.RateLimit.RedisPrefix, c.Cache.Redis.KeyPrefix
+		if strings.HasPrefix(rl, cache) || strings.HasPrefix(cache, rl) {
+			return fmt.Errorf("rate limit redis prefix %q must not overlap cache key prefix %q", rl, cache)
+		}
+	}
+	for name, b := range map[string]RateLimitBudget{
+		"read":    c.RateLimit.Read,
+		"money":   c.RateLimit.Money,
+		"default": c.RateLimit.Default,
+		"ip":      c.RateLimit.IP,
+	} {
+		if b.RPS <= 0 || b.Burst < 1 {
+			return fmt.Errorf("invalid rate limit %s budget: rps %v, burst %d", name, b.RPS, b.Burst)
+		}
+	}
+
+	if c.Skinport.BreakerThreshold < 1 || c.Skinport.BreakerCooldown < 0 {
+		return fmt.Errorf("invalid skinport breaker: threshold %d, cooldown %s", c.Skinport.BreakerThreshold, c.Skinport.BreakerCooldown)
+	}
+	if c.Health.CheckTimeout < 0 || c.Server.DrainDelay < 0 {
+		return fmt.Errorf("invalid health timings: check timeout %s, drain delay %s", c.Health.CheckTimeout, c.Server.DrainDelay)
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingExporterNone, TracingExporterStdout, TracingExporterOTLP:
+	default:
+		return fmt.Errorf("invalid tracing exporter: %q", c.Tracing.Exporter)
+	}
+	if ratio := *c.Tracing.SampleRatio; ratio < 0 || ratio > 1 {
+		return fmt.Errorf("invalid tracing sample ratio: %v", ratio)
+	}
+
 	return nil
 }
@@ -0,0 +1,69 @@
+package config
+
+import "testing"
+
+// loadTestConfig загружает конфигурацию только из переменных окружения с минимально
+// необходимыми для валидации значениями
+func loadTestConfig(t *testing.T, env map[string]string) *Config {
+	t.Helper()
+
+	t.Setenv("SKINPORT_API_URL", "https://api.skinport.com/v1")
+	t.Setenv("ALERTS_WEBHOOK_SECRET", "0123456789abcdef0123456789abcdef")
+	t.Setenv("AUTH_DISABLED", "true")
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	return cfg
+}
+
+func TestLoad_CacheLimits(t *testing.T) {
+	cfg := loadTestConfig(t, nil)
+	if *cfg.Cache.MaxEntries != 10000 || *cfg.Cache.MaxBytes != 256<<20 {
+		t.Errorf("expected default cache limits, got %d entries, %d bytes", *cfg.Cache.MaxEntries, *cfg.Cache.MaxBytes)
+	}
+
+	// Явный 0 — без ограничения, а не значение по умолчанию
+	cfg = loadTestConfig(t, map[string]string{"CACHE_MAX_ENTRIES": "0", "CACHE_MAX_BYTES": "0"})
+	if *cfg.Cache.MaxEntries != 0 || *cfg.Cache.MaxBytes != 0 {
+		t.Errorf("expected unlimited cache, got %d entries, %d bytes", *cfg.Cache.MaxEntries, *cfg.Cache.MaxBytes)
+	}
+}
+
+func TestLoad_ZeroFeePercent(t *testing.T) {
+	if cfg := loadTestConfig(t, nil); *cfg.Insights.FeePercent != 12 {
+		t.Errorf("expected default fee percent, got %v", *cfg.Insights.FeePercent)
+	}
+
+	// Площадка без комиссии
+	if cfg := loadTestConfig(t, map[string]string{"INSIGHTS_FEE_PERCENT": "0"}); *cfg.Insights.FeePercent != 0 {
+		t.Errorf("expected zero fee percent, got %v", *cfg.Insights.FeePercent)
+	}
+}
+
+func TestLoad_ZeroSampleRatio(t *testing.T) {
+	if cfg := loadTestConfig(t, nil); *cfg.Tracing.SampleRatio != 1 {
+		t.Errorf("expected default sample ratio, got %v", *cfg.Tracing.SampleRatio)
+	}
+
+	// 0 — новые трассы не сэмплируются, а не «значение по умолчанию»
+	if cfg := loadTestConfig(t, map[string]string{"TRACING_SAMPLE_RATIO": "0"}); *cfg.Tracing.SampleRatio != 0 {
+		t.Errorf("expected zero sample ratio, got %v", *cfg.Tracing.SampleRatio)
+	}
+}
+
+func TestLoad_FileWithUnsetVariables(t *testing.T) {
+	// config.yaml ссылается на незаданные переменные: пустое значение означает умолчание
+	cfg := &Config{}
+	if err := cfg.loadFromFile("../../config/config.yaml"); err != nil {
+		t.Fatalf("failed to load config file: %v", err)
+	}
+	cfg.setDefaults()
+	if *cfg.Cache.MaxEntries != 10000 {
+		t.Errorf("expected default max entries, got %d", *cfg.Cache.MaxEntries)
+	}
+}
@@ -0,0 +1,31 @@
+package alert
+
+import "net/netip"
+
+// reservedPrefixes специальные диапазоны, которые не покрываются методами netip.Addr
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "этот" хост и сеть
+	netip.MustParsePrefix("100.64.0.0/10"),  // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // стенды тестирования производительности
+	netip.MustParsePrefix("240.0.0.0/4"),    // зарезервировано и broadcast
+	netip.MustParsePrefix("64:ff9b:1::/48"), // локальный NAT64
+	netip.MustParsePrefix("100::/64"),       // discard-only
+	netip.MustParsePrefix("2001:db8::/32"),  // документация
+}
+
+// IsPublicAddress сообщает, можно ли доставлять вебхук на адрес.
+// Запрещены loopback, частные сети RFC 1918 и ULA, link-local (включая 169.254.169.254
+// метаданных облака), multicast, неуказанный адрес и прочие специальные диапазоны
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, p := range reservedPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
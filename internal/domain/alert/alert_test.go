@@ -0,0 +1,141 @@
+package alert
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+func TestNewAlert_Validation(t *testing.T) {
+	tests := []struct {
+		name      string
+		itemName  string
+		direction Direction
+		priceType PriceType
+		threshold float64
+		url       string
+		wantErr   error
+	}{
+		{"valid", "AK-47", DirectionBelow, PriceTypeTradable, 10, "https://example.com/hook", nil},
+		{"empty name", " ", DirectionBelow, PriceTypeTradable, 10, "https://example.com/hook", ErrInvalidMarketHashName},
+		{"bad direction", "AK-47", "sideways", PriceTypeTradable, 10, "https://example.com/hook", ErrInvalidDirection},
+		{"bad price type", "AK-47", DirectionAbove, "mean", 10, "https://example.com/hook", ErrInvalidPriceType},
+		{"zero threshold", "AK-47", DirectionAbove, PriceTypeTradable, 0, "https://example.com/hook", ErrInvalidThreshold},
+		{"relative url", "AK-47", DirectionAbove, PriceTypeTradable, 10, "/hook", ErrInvalidWebhookURL},
+		{"bad scheme", "AK-47", DirectionAbove, PriceTypeTradable, 10, "ftp://example.com/hook", ErrInvalidWebhookURL},
+		{"localhost", "AK-47", DirectionAbove, PriceTypeTradable, 10, "http://localhost:8080/hook", ErrInvalidWebhookURL},
+		{"loopback ip", "AK-47", DirectionAbove, PriceTypeTradable, 10, "http://127.0.0.1/hook", ErrInvalidWebhookURL},
+		{"private ip", "AK-47", DirectionAbove, PriceTypeTradable, 10, "http://10.0.0.5/hook", ErrInvalidWebhookURL},
+		{"cloud metadata", "AK-47", DirectionAbove, PriceTypeTradable, 10, "http://169.254.169.254/latest/meta-data", ErrInvalidWebhookURL},
+		{"ipv6 loopback", "AK-47", DirectionAbove, PriceTypeTradable, 10, "http://[::1]/hook", ErrInvalidWebhookURL},
+		{"public ip", "AK-47", DirectionAbove, PriceTypeTradable, 10, "https://93.184.216.34/hook", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAlert(1, tt.itemName, tt.direction, tt.priceType, decimal.NewFromFloat(tt.threshold), tt.url)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && !a.Active {
+				t.Error("expected new alert to be active")
+			}
+		})
+	}
+}
+
+func TestIsPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"255.255.255.255", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := IsPublicAddress(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAlert_Evaluate(t *testing.T) {
+	tradable := decimal.NewFromFloat(12.50)
+	nonTradable := decimal.NewFromFloat(10.20)
+	it := &item.Item{MarketHashName: "AK-47", TradableMinPrice: &tradable, NonTradableMinPrice: &nonTradable}
+
+	tests := []struct {
+		name      string
+		direction Direction
+		priceType PriceType
+		threshold float64
+		expected  bool
+	}{
+		{"tradable below triggered", DirectionBelow, PriceTypeTradable, 13, true},
+		{"tradable below equal triggered", DirectionBelow, PriceTypeTradable, 12.5, true},
+		{"tradable below not triggered", DirectionBelow, PriceTypeTradable, 12, false},
+		{"tradable above triggered", DirectionAbove, PriceTypeTradable, 12, true},
+		{"tradable above not triggered", DirectionAbove, PriceTypeTradable, 13, false},
+		{"non-tradable below triggered", DirectionBelow, PriceTypeNonTradable, 11, true},
+		{"non-tradable above not triggered", DirectionAbove, PriceTypeNonTradable, 11, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAlert(1, "AK-47", tt.direction, tt.priceType, decimal.NewFromFloat(tt.threshold), "https://example.com/hook")
+			if err != nil {
+				t.Fatalf("failed to create alert: %v", err)
+			}
+
+			_, triggered := a.Evaluate(it)
+			if triggered != tt.expected {
+				t.Errorf("expected triggered=%v, got %v", tt.expected, triggered)
+			}
+		})
+	}
+}
+
+func TestAlert_Evaluate_SkipsMissingPriceAndInactive(t *testing.T) {
+	a, err := NewAlert(1, "AK-47", DirectionBelow, PriceTypeTradable, decimal.NewFromFloat(100), "https://example.com/hook")
+	if err != nil {
+		t.Fatalf("failed to create alert: %v", err)
+	}
+
+	if _, triggered := a.Evaluate(&item.Item{MarketHashName: "AK-47"}); triggered {
+		t.Error("expected no trigger without tradable price")
+	}
+
+	if _, triggered := a.Evaluate(nil); triggered {
+		t.Error("expected no trigger for missing item")
+	}
+
+	price := decimal.NewFromFloat(1)
+	a.Trigger(time.Now())
+	if _, triggered := a.Evaluate(&item.Item{MarketHashName: "AK-47", TradableMinPrice: &price}); triggered {
+		t.Error("expected triggered alert to stay inactive")
+	}
+	if a.TriggeredAt == nil {
+		t.Error("expected triggered at to be set")
+	}
+}
@@ -0,0 +1,138 @@
+package alert
+
+import (
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// Direction определяет направление пересечения порога
+type Direction string
+
+const (
+	// DirectionAbove срабатывает когда цена поднимается до порога или выше
+	DirectionAbove Direction = "above"
+	// DirectionBelow срабатывает когда цена опускается до порога или ниже
+	DirectionBelow Direction = "below"
+)
+
+// PriceType определяет, за какой минимальной ценой следит алерт
+type PriceType string
+
+const (
+	// PriceTypeTradable минимальная цена tradable предложений
+	PriceTypeTradable PriceType = "tradable"
+	// PriceTypeNonTradable минимальная цена non-tradable предложений
+	PriceTypeNonTradable PriceType = "non_tradable"
+)
+
+// Alert представляет ценовой алерт пользователя.
+// Алерт одноразовый: после срабатывания он деактивируется.
+type Alert struct {
+	ID             uuid.UUID       `json:"id"`
+	UserID         int64           `json:"user_id"`
+	MarketHashName string          `json:"market_hash_name"`
+	Direction      Direction       `json:"direction"`
+	PriceType      PriceType       `json:"price_type"`
+	Threshold      decimal.Decimal `json:"threshold"`
+	WebhookURL     string          `json:"webhook_url"`
+	Active         bool            `json:"active"`
+	CreatedAt      time.Time       `json:"created_at"`
+	TriggeredAt    *time.Time      `json:"triggered_at,omitempty"`
+}
+
+// NewAlert создает новый активный алерт с валидацией параметров
+func NewAlert(
+	userID int64,
+	marketHashName string,
+	direction Direction,
+	priceType PriceType,
+	threshold decimal.Decimal,
+	webhookURL string,
+) (*Alert, error) {
+	if strings.TrimSpace(marketHashName) == "" {
+		return nil, ErrInvalidMarketHashName
+	}
+	if direction != DirectionAbove && direction != DirectionBelow {
+		return nil, ErrInvalidDirection
+	}
+	if priceType != PriceTypeTradable && priceType != PriceTypeNonTradable {
+		return nil, ErrInvalidPriceType
+	}
+	if threshold.LessThanOrEqual(decimal.Zero) {
+		return nil, ErrInvalidThreshold
+	}
+	if !isValidWebhookURL(webhookURL) {
+		return nil, ErrInvalidWebhookURL
+	}
+
+	return &Alert{
+		ID:             uuid.New(),
+		UserID:         userID,
+		MarketHashName: marketHashName,
+		Direction:      direction,
+		PriceType:      priceType,
+		Threshold:      threshold,
+		WebhookURL:     webhookURL,
+		Active:         true,
+		CreatedAt:      time.Now().UTC(),
+	}, nil
+}
+
+// Evaluate проверяет, пересекла ли цена предмета порог алерта.
+// Возвращает отслеживаемую цену и признак срабатывания.
+func (a *Alert) Evaluate(it *item.Item) (decimal.Decimal, bool) {
+	if !a.Active || it == nil || it.MarketHashName != a.MarketHashName {
+		return decimal.Zero, false
+	}
+
+	price := it.TradableMinPrice
+	if a.PriceType == PriceTypeNonTradable {
+		price = it.NonTradableMinPrice
+	}
+	if price == nil {
+		return decimal.Zero, false
+	}
+
+	switch a.Direction {
+	case DirectionAbove:
+		return *price, price.GreaterThanOrEqual(a.Threshold)
+	case DirectionBelow:
+		return *price, price.LessThanOrEqual(a.Threshold)
+	default:
+		return decimal.Zero, false
+	}
+}
+
+// Trigger деактивирует алерт и фиксирует время срабатывания
+func (a *Alert) Trigger(at time.Time) {
+	a.Active = false
+	a.TriggeredAt = &at
+}
+
+// isValidWebhookURL проверяет схему и отсекает адреса внутренней сети, указанные явно.
+// Имена хостов проверяются при доставке по разрешенному IP, см. IsPublicAddress
+func isValidWebhookURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return false
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return IsPublicAddress(addr)
+	}
+	return true
+}
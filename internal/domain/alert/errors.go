@@ -0,0 +1,26 @@
+package alert
+
+import "errors"
+
+var (
+	// ErrAlertNotFound возвращается когда алерт не найден
+	ErrAlertNotFound = errors.New("alert not found")
+
+	// ErrInvalidDirection возвращается когда направление алерта некорректно
+	ErrInvalidDirection = errors.New("invalid direction: must be above or below")
+
+	// ErrInvalidPriceType возвращается когда тип цены алерта некорректен
+	ErrInvalidPriceType = errors.New("invalid price type: must be tradable or non_tradable")
+
+	// ErrInvalidThreshold возвращается когда порог цены некорректен (отрицательный или ноль)
+	ErrInvalidThreshold = errors.New("invalid threshold: must be positive")
+
+	// ErrInvalidMarketHashName возвращается когда не указан предмет
+	ErrInvalidMarketHashName = errors.New("market hash name is required")
+
+	// ErrInvalidWebhookURL возвращается когда адрес вебхука некорректен
+	ErrInvalidWebhookURL = errors.New("invalid webhook url: must be absolute http or https url to a public host")
+
+	// ErrDeliveryRejected возвращается когда получатель вебхука окончательно отклонил уведомление
+	ErrDeliveryRejected = errors.New("webhook delivery rejected")
+)
@@ -0,0 +1,58 @@
+package alert
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Notification представляет уведомление о срабатывании алерта (тело вебхука)
+type Notification struct {
+	ID             uuid.UUID       `json:"id"`
+	AlertID        uuid.UUID       `json:"alert_id"`
+	UserID         int64           `json:"user_id"`
+	MarketHashName string          `json:"market_hash_name"`
+	Direction      Direction       `json:"direction"`
+	PriceType      PriceType       `json:"price_type"`
+	Threshold      decimal.Decimal `json:"threshold"`
+	Price          decimal.Decimal `json:"price"`
+	TriggeredAt    time.Time       `json:"triggered_at"`
+}
+
+// NewNotification создает уведомление о срабатывании алерта
+func NewNotification(a *Alert, price decimal.Decimal, triggeredAt time.Time) *Notification {
+	return &Notification{
+		ID:             uuid.New(),
+		AlertID:        a.ID,
+		UserID:         a.UserID,
+		MarketHashName: a.MarketHashName,
+		Direction:      a.Direction,
+		PriceType:      a.PriceType,
+		Threshold:      a.Threshold,
+		Price:          price,
+		TriggeredAt:    triggeredAt,
+	}
+}
+
+// DeadLetter представляет уведомление, которое не удалось доставить после всех попыток
+type DeadLetter struct {
+	ID           uuid.UUID     `json:"id"`
+	Notification *Notification `json:"notification"`
+	WebhookURL   string        `json:"webhook_url"`
+	Attempts     int           `json:"attempts"`
+	LastError    string        `json:"last_error"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// NewDeadLetter создает запись о недоставленном уведомлении
+func NewDeadLetter(n *Notification, webhookURL string, attempts int, lastErr error) *DeadLetter {
+	return &DeadLetter{
+		ID:           uuid.New(),
+		Notification: n,
+		WebhookURL:   webhookURL,
+		Attempts:     attempts,
+		LastError:    lastErr.Error(),
+		CreatedAt:    time.Now().UTC(),
+	}
+}
@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Action тип действия, которое фиксируется в журнале аудита
+type Action string
+
+const (
+	// ActionCacheInspect просмотр содержимого кэша
+	ActionCacheInspect Action = "cache.inspect"
+	// ActionCacheRefresh принудительное обновление каталога из Skinport
+	ActionCacheRefresh Action = "cache.refresh"
+	// ActionCacheDelete удаление ключа кэша
+	ActionCacheDelete Action = "cache.delete"
+	// ActionCacheClear очистка всего кэша
+	ActionCacheClear Action = "cache.clear"
+)
+
+// Outcome результат действия
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+)
+
+// Entry запись журнала аудита административных действий
+type Entry struct {
+	ID         uuid.UUID `json:"id"`
+	Actor      string    `json:"actor"`
+	Action     Action    `json:"action"`
+	Target     string    `json:"target,omitempty"`
+	Outcome    Outcome   `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewEntry создает запись аудита. Ошибка действия, если есть, сохраняется в записи.
+func NewEntry(actor string, action Action, target, remoteAddr string, actionErr error) *Entry {
+	e := &Entry{
+		ID:         uuid.New(),
+		Actor:      actor,
+		Action:     action,
+		Target:     target,
+		Outcome:    OutcomeSuccess,
+		RemoteAddr: remoteAddr,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if actionErr != nil {
+		e.Outcome = OutcomeFailure
+		e.Error = actionErr.Error()
+	}
+
+	return e
+}
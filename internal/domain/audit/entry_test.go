@@ -0,0 +1,21 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewEntry(t *testing.T) {
+	ok := NewEntry("admin", ActionCacheDelete, "skinport:items", "10.0.0.1", nil)
+	if ok.Outcome != OutcomeSuccess || ok.Error != "" || ok.Target != "skinport:items" {
+		t.Errorf("unexpected successful entry: %+v", ok)
+	}
+
+	failed := NewEntry("admin", ActionCacheRefresh, "", "10.0.0.1", errors.New("skinport is down"))
+	if failed.Outcome != OutcomeFailure || failed.Error != "skinport is down" {
+		t.Errorf("expected failure to be recorded, got %+v", failed)
+	}
+	if failed.ID == ok.ID {
+		t.Error("expected unique entry ids")
+	}
+}
@@ -0,0 +1,11 @@
+package item
+
+import "time"
+
+// Catalogue представляет версию каталога предметов.
+// Version монотонно растет при каждой замене каталога, UpdatedAt — время замены.
+type Catalogue struct {
+	Items     []*Item
+	Version   uint64
+	UpdatedAt time.Time
+}
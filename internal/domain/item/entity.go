@@ -17,3 +17,30 @@ type Item struct {
 	CreatedAt           int64            `json:"created_at"`
 	UpdatedAt           int64            `json:"updated_at"`
 }
+
+// Equal сравнивает предметы по значению, цены — с учетом масштаба (12.3 == 12.30)
+func (i *Item) Equal(other *Item) bool {
+	if i == nil || other == nil {
+		return i == other
+	}
+
+	return i.MarketHashName == other.MarketHashName &&
+		i.Currency == other.Currency &&
+		i.ItemPage == other.ItemPage &&
+		i.MarketPage == other.MarketPage &&
+		i.Quantity == other.Quantity &&
+		i.CreatedAt == other.CreatedAt &&
+		i.UpdatedAt == other.UpdatedAt &&
+		equalPrice(i.SuggestedPrice, other.SuggestedPrice) &&
+		equalPrice(i.TradableMinPrice, other.TradableMinPrice) &&
+		equalPrice(i.NonTradableMinPrice, other.NonTradableMinPrice) &&
+		equalPrice(i.MaxPrice, other.MaxPrice) &&
+		equalPrice(i.MeanPrice, other.MeanPrice)
+}
+
+func equalPrice(a, b *decimal.Decimal) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -11,4 +11,13 @@ var (
 
 	// ErrEmptyResponse возвращается когда API вернул пустой ответ
 	ErrEmptyResponse = errors.New("empty response from API")
+
+	// ErrInvalidTimeRange возвращается когда период запроса истории цен некорректен
+	ErrInvalidTimeRange = errors.New("invalid time range")
+
+	// ErrCatalogueNotStored возвращается когда сохраненного каталога нет
+	ErrCatalogueNotStored = errors.New("catalogue not stored")
+
+	// ErrInvalidInterval возвращается когда интервал агрегации некорректен
+	ErrInvalidInterval = errors.New("invalid interval")
 )
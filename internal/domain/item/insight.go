@@ -0,0 +1,129 @@
+package item
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// InsightSort определяет метрику ранжирования аналитики
+type InsightSort string
+
+const (
+	// InsightSortSpread ранжирует по доходности перепродажи non-tradable → tradable после комиссии
+	InsightSortSpread InsightSort = "spread"
+	// InsightSortDiscount ранжирует по скидке минимальной цены относительно рекомендованной
+	InsightSortDiscount InsightSort = "discount"
+	// InsightSortMeanGap ранжирует по отставанию минимальной цены от средней
+	InsightSortMeanGap InsightSort = "mean_gap"
+)
+
+var (
+	// ErrInvalidInsightSort возвращается когда метрика ранжирования неизвестна
+	ErrInvalidInsightSort = errors.New("invalid sort: must be spread, discount or mean_gap")
+
+	// ErrInvalidFeePercent возвращается когда комиссия вне диапазона [0, 100)
+	ErrInvalidFeePercent = errors.New("invalid fee percent: must be in range [0, 100)")
+
+	hundred = decimal.NewFromInt(100)
+)
+
+// Insight представляет аналитику цен предмета.
+// Процентные метрики отсутствуют, если для их расчета не хватает цен.
+type Insight struct {
+	MarketHashName      string           `json:"market_hash_name"`
+	Currency            string           `json:"currency"`
+	TradableMinPrice    *decimal.Decimal `json:"tradable_min_price,omitempty"`
+	NonTradableMinPrice *decimal.Decimal `json:"non_tradable_min_price,omitempty"`
+	SuggestedPrice      *decimal.Decimal `json:"suggested_price,omitempty"`
+	MeanPrice           *decimal.Decimal `json:"mean_price,omitempty"`
+	Quantity            int              `json:"quantity"`
+
+	// Spread — выручка от продажи по tradable минимуму за вычетом комиссии минус цена покупки non-tradable
+	Spread        *decimal.Decimal `json:"spread,omitempty"`
+	SpreadPercent *decimal.Decimal `json:"spread_percent,omitempty"`
+	// DiscountPercent — насколько самая низкая минимальная цена ниже рекомендованной
+	DiscountPercent *decimal.Decimal `json:"discount_percent,omitempty"`
+	// MeanGapPercent — насколько tradable минимум ниже средней цены
+	MeanGapPercent *decimal.Decimal `json:"mean_gap_percent,omitempty"`
+}
+
+// ParseInsightSort проверяет метрику ранжирования
+func ParseInsightSort(s string) (InsightSort, error) {
+	switch InsightSort(s) {
+	case InsightSortSpread, InsightSortDiscount, InsightSortMeanGap:
+		return InsightSort(s), nil
+	default:
+		return "", ErrInvalidInsightSort
+	}
+}
+
+// ValidateFeePercent проверяет что комиссия в процентах лежит в диапазоне [0, 100)
+func ValidateFeePercent(fee decimal.Decimal) error {
+	if fee.IsNegative() || fee.GreaterThanOrEqual(hundred) {
+		return ErrInvalidFeePercent
+	}
+	return nil
+}
+
+// NewInsight рассчитывает аналитику предмета с учетом комиссии продажи в процентах
+func NewInsight(it *Item, feePercent decimal.Decimal) *Insight {
+	in := &Insight{
+		MarketHashName:      it.MarketHashName,
+		Currency:            it.Currency,
+		TradableMinPrice:    it.TradableMinPrice,
+		NonTradableMinPrice: it.NonTradableMinPrice,
+		SuggestedPrice:      it.SuggestedPrice,
+		MeanPrice:           it.MeanPrice,
+		Quantity:            it.Quantity,
+	}
+
+	if it.TradableMinPrice != nil && it.NonTradableMinPrice != nil && it.NonTradableMinPrice.IsPositive() {
+		keep := hundred.Sub(feePercent).Div(hundred)
+		spread := it.TradableMinPrice.Mul(keep).Sub(*it.NonTradableMinPrice).Round(2)
+		in.Spread = &spread
+		in.SpreadPercent = percentOf(spread, *it.NonTradableMinPrice)
+	}
+
+	if low := lowestMinPrice(it); low != nil && it.SuggestedPrice != nil && it.SuggestedPrice.IsPositive() {
+		in.DiscountPercent = percentOf(it.SuggestedPrice.Sub(*low), *it.SuggestedPrice)
+	}
+
+	if it.TradableMinPrice != nil && it.MeanPrice != nil && it.MeanPrice.IsPositive() {
+		in.MeanGapPercent = percentOf(it.MeanPrice.Sub(*it.TradableMinPrice), *it.MeanPrice)
+	}
+
+	return in
+}
+
+// Metric возвращает значение метрики ранжирования или nil если она не рассчитана
+func (in *Insight) Metric(sort InsightSort) *decimal.Decimal {
+	switch sort {
+	case InsightSortSpread:
+		return in.SpreadPercent
+	case InsightSortDiscount:
+		return in.DiscountPercent
+	case InsightSortMeanGap:
+		return in.MeanGapPercent
+	default:
+		return nil
+	}
+}
+
+func lowestMinPrice(it *Item) *decimal.Decimal {
+	switch {
+	case it.TradableMinPrice == nil:
+		return it.NonTradableMinPrice
+	case it.NonTradableMinPrice == nil:
+		return it.TradableMinPrice
+	case it.NonTradableMinPrice.LessThan(*it.TradableMinPrice):
+		return it.NonTradableMinPrice
+	default:
+		return it.TradableMinPrice
+	}
+}
+
+func percentOf(part, whole decimal.Decimal) *decimal.Decimal {
+	p := part.Mul(hundred).Div(whole).Round(2)
+	return &p
+}
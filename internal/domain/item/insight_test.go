@@ -0,0 +1,101 @@
+package item
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func decPtr(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
+func TestNewInsight_Metrics(t *testing.T) {
+	it := &Item{
+		MarketHashName:      "AK-47 | Redline",
+		TradableMinPrice:    decPtr("12.50"),
+		NonTradableMinPrice: decPtr("10.00"),
+		SuggestedPrice:      decPtr("15.00"),
+		MeanPrice:           decPtr("20.00"),
+		Quantity:            5,
+	}
+
+	in := NewInsight(it, decimal.NewFromInt(12))
+
+	// 12.50 * 0.88 - 10.00 = 1.00
+	if in.Spread.String() != "1" {
+		t.Errorf("expected spread 1, got %s", in.Spread.String())
+	}
+	if in.SpreadPercent.String() != "10" {
+		t.Errorf("expected spread percent 10, got %s", in.SpreadPercent.String())
+	}
+	// (15 - 10) / 15 = 33.33%
+	if in.DiscountPercent.String() != "33.33" {
+		t.Errorf("expected discount 33.33, got %s", in.DiscountPercent.String())
+	}
+	// (20 - 12.5) / 20 = 37.5%
+	if in.MeanGapPercent.String() != "37.5" {
+		t.Errorf("expected mean gap 37.5, got %s", in.MeanGapPercent.String())
+	}
+}
+
+func TestNewInsight_MissingPrices(t *testing.T) {
+	in := NewInsight(&Item{MarketHashName: "Sticker", TradableMinPrice: decPtr("1.00")}, decimal.Zero)
+
+	if in.Spread != nil || in.SpreadPercent != nil {
+		t.Error("expected no spread without non-tradable price")
+	}
+	if in.DiscountPercent != nil {
+		t.Error("expected no discount without suggested price")
+	}
+	if in.MeanGapPercent != nil {
+		t.Error("expected no mean gap without mean price")
+	}
+	if in.Metric(InsightSortSpread) != nil {
+		t.Error("expected nil metric")
+	}
+}
+
+func TestNewInsight_NegativeSpread(t *testing.T) {
+	it := &Item{TradableMinPrice: decPtr("10.00"), NonTradableMinPrice: decPtr("10.00")}
+
+	in := NewInsight(it, decimal.NewFromInt(12))
+
+	if !in.Spread.IsNegative() {
+		t.Errorf("expected negative spread after fee, got %s", in.Spread.String())
+	}
+}
+
+func TestParseInsightSort(t *testing.T) {
+	for _, s := range []string{"spread", "discount", "mean_gap"} {
+		if _, err := ParseInsightSort(s); err != nil {
+			t.Errorf("expected %s to be valid, got %v", s, err)
+		}
+	}
+
+	if _, err := ParseInsightSort("profit"); !errors.Is(err, ErrInvalidInsightSort) {
+		t.Errorf("expected ErrInvalidInsightSort, got %v", err)
+	}
+}
+
+func TestValidateFeePercent(t *testing.T) {
+	tests := []struct {
+		fee   string
+		valid bool
+	}{
+		{"0", true},
+		{"12", true},
+		{"99.99", true},
+		{"100", false},
+		{"-1", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateFeePercent(decimal.RequireFromString(tt.fee))
+		if (err == nil) != tt.valid {
+			t.Errorf("fee %s: expected valid=%v, got %v", tt.fee, tt.valid, err)
+		}
+	}
+}
@@ -45,3 +45,24 @@ func TestItem_NilPrices(t *testing.T) {
 		t.Error("expected nil non-tradable price")
 	}
 }
+
+func TestItem_Equal(t *testing.T) {
+	a := decimal.RequireFromString("12.3")
+	b := decimal.RequireFromString("12.30")
+	c := decimal.RequireFromString("12.31")
+
+	base := &Item{MarketHashName: "Test Item", Currency: "USD", TradableMinPrice: &a, Quantity: 2}
+
+	if !base.Equal(&Item{MarketHashName: "Test Item", Currency: "USD", TradableMinPrice: &b, Quantity: 2}) {
+		t.Error("expected items with equal prices of different scale to be equal")
+	}
+	if base.Equal(&Item{MarketHashName: "Test Item", Currency: "USD", TradableMinPrice: &c, Quantity: 2}) {
+		t.Error("expected items with different prices to differ")
+	}
+	if base.Equal(&Item{MarketHashName: "Test Item", Currency: "USD", Quantity: 2}) {
+		t.Error("expected nil price to differ from set price")
+	}
+	if base.Equal(nil) {
+		t.Error("expected item not to equal nil")
+	}
+}
@@ -0,0 +1,46 @@
+package item
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceSnapshot представляет срез цен предмета на момент обновления каталога
+type PriceSnapshot struct {
+	TakenAt             time.Time        `json:"taken_at"`
+	MarketHashName      string           `json:"market_hash_name"`
+	TradableMinPrice    *decimal.Decimal `json:"tradable_min_price,omitempty"`
+	NonTradableMinPrice *decimal.Decimal `json:"non_tradable_min_price,omitempty"`
+	MeanPrice           *decimal.Decimal `json:"mean_price,omitempty"`
+	MaxPrice            *decimal.Decimal `json:"max_price,omitempty"`
+	Quantity            int              `json:"quantity"`
+}
+
+// NewPriceSnapshot создает срез цен предмета
+func NewPriceSnapshot(it *Item, takenAt time.Time) *PriceSnapshot {
+	return &PriceSnapshot{
+		TakenAt:             takenAt,
+		MarketHashName:      it.MarketHashName,
+		TradableMinPrice:    it.TradableMinPrice,
+		NonTradableMinPrice: it.NonTradableMinPrice,
+		MeanPrice:           it.MeanPrice,
+		MaxPrice:            it.MaxPrice,
+		Quantity:            it.Quantity,
+	}
+}
+
+// PriceCandle представляет OHLC агрегат цен предмета за интервал.
+// Open/High/Low/Close считаются по минимальной tradable цене.
+type PriceCandle struct {
+	Time           time.Time        `json:"time"`
+	Open           *decimal.Decimal `json:"open,omitempty"`
+	High           *decimal.Decimal `json:"high,omitempty"`
+	Low            *decimal.Decimal `json:"low,omitempty"`
+	Close          *decimal.Decimal `json:"close,omitempty"`
+	NonTradableLow *decimal.Decimal `json:"non_tradable_low,omitempty"`
+	AvgMeanPrice   *decimal.Decimal `json:"avg_mean_price,omitempty"`
+	MaxPrice       *decimal.Decimal `json:"max_price,omitempty"`
+	Quantity       int              `json:"quantity"`
+	Samples        int              `json:"samples"`
+}
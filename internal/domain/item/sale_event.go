@@ -0,0 +1,116 @@
+package item
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SaleEventType тип события ленты продаж
+type SaleEventType string
+
+const (
+	// SaleEventListed предмет выставлен на продажу
+	SaleEventListed SaleEventType = "listed"
+	// SaleEventSold предмет продан и снят с продажи
+	SaleEventSold SaleEventType = "sold"
+)
+
+// ErrInvalidSaleEvent возвращается когда событие ленты продаж некорректно
+var ErrInvalidSaleEvent = errors.New("invalid sale event")
+
+// SaleEvent представляет нормализованное событие ленты продаж Skinport
+type SaleEvent struct {
+	Type           SaleEventType    `json:"type"`
+	SaleID         int64            `json:"sale_id"`
+	MarketHashName string           `json:"market_hash_name"`
+	Currency       string           `json:"currency"`
+	Price          decimal.Decimal  `json:"price"`
+	SuggestedPrice *decimal.Decimal `json:"suggested_price,omitempty"`
+	Tradable       bool             `json:"tradable"`
+	Wear           *float64         `json:"wear,omitempty"`
+	OccurredAt     time.Time        `json:"occurred_at"`
+}
+
+// NewSaleEvent создает событие ленты продаж с проверкой обязательных полей
+func NewSaleEvent(
+	eventType SaleEventType,
+	saleID int64,
+	marketHashName, currency string,
+	price decimal.Decimal,
+	tradable bool,
+	occurredAt time.Time,
+) (*SaleEvent, error) {
+	if eventType != SaleEventListed && eventType != SaleEventSold {
+		return nil, ErrInvalidSaleEvent
+	}
+	if marketHashName == "" || price.IsNegative() {
+		return nil, ErrInvalidSaleEvent
+	}
+
+	return &SaleEvent{
+		Type:           eventType,
+		SaleID:         saleID,
+		MarketHashName: marketHashName,
+		Currency:       currency,
+		Price:          price,
+		Tradable:       tradable,
+		OccurredAt:     occurredAt,
+	}, nil
+}
+
+// ApplySale возвращает копию предмета с учетом события ленты продаж; исходный предмет не изменяется.
+// Для нового предмета (it == nil) создается запись по данным события.
+//
+// Выставление увеличивает количество и может понизить минимальную цену.
+// Продажа уменьшает количество; пересчитать минимум без полного каталога нельзя,
+// поэтому минимальная цена сбрасывается только когда предложений не осталось.
+func ApplySale(it *Item, ev *SaleEvent) *Item {
+	var updated Item
+	if it != nil {
+		updated = *it
+	} else {
+		if ev.Type != SaleEventListed {
+			return nil
+		}
+		updated = Item{
+			MarketHashName: ev.MarketHashName,
+			Currency:       ev.Currency,
+			SuggestedPrice: ev.SuggestedPrice,
+			CreatedAt:      ev.OccurredAt.Unix(),
+		}
+	}
+
+	price := ev.Price
+	switch ev.Type {
+	case SaleEventListed:
+		updated.Quantity++
+		if ev.Tradable {
+			updated.TradableMinPrice = minPrice(updated.TradableMinPrice, &price)
+		} else {
+			updated.NonTradableMinPrice = minPrice(updated.NonTradableMinPrice, &price)
+		}
+		if updated.MaxPrice == nil || price.GreaterThan(*updated.MaxPrice) {
+			updated.MaxPrice = &price
+		}
+	case SaleEventSold:
+		if updated.Quantity > 0 {
+			updated.Quantity--
+		}
+		if updated.Quantity == 0 {
+			updated.TradableMinPrice = nil
+			updated.NonTradableMinPrice = nil
+		}
+	}
+
+	updated.UpdatedAt = ev.OccurredAt.Unix()
+	return &updated
+}
+
+func minPrice(current, candidate *decimal.Decimal) *decimal.Decimal {
+	if current == nil || candidate.LessThan(*current) {
+		return candidate
+	}
+	return current
+}
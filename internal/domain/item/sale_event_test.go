@@ -0,0 +1,99 @@
+package item
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func newTestSaleEvent(t *testing.T, eventType SaleEventType, price string, tradable bool) *SaleEvent {
+	t.Helper()
+
+	ev, err := NewSaleEvent(eventType, 1, "AK-47 | Redline (Field-Tested)", "USD",
+		decimal.RequireFromString(price), tradable, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("failed to create sale event: %v", err)
+	}
+	return ev
+}
+
+func TestNewSaleEvent_Validation(t *testing.T) {
+	now := time.Now()
+
+	if _, err := NewSaleEvent("unknown", 1, "AK", "USD", decimal.NewFromInt(1), true, now); !errors.Is(err, ErrInvalidSaleEvent) {
+		t.Errorf("expected ErrInvalidSaleEvent for unknown type, got %v", err)
+	}
+	if _, err := NewSaleEvent(SaleEventSold, 1, "", "USD", decimal.NewFromInt(1), true, now); !errors.Is(err, ErrInvalidSaleEvent) {
+		t.Errorf("expected ErrInvalidSaleEvent for empty name, got %v", err)
+	}
+	if _, err := NewSaleEvent(SaleEventSold, 1, "AK", "USD", decimal.NewFromInt(-1), true, now); !errors.Is(err, ErrInvalidSaleEvent) {
+		t.Errorf("expected ErrInvalidSaleEvent for negative price, got %v", err)
+	}
+}
+
+func TestApplySale_Listed(t *testing.T) {
+	tradable := decimal.RequireFromString("12.50")
+	nonTradable := decimal.RequireFromString("10.20")
+	original := &Item{
+		MarketHashName:      "AK-47 | Redline (Field-Tested)",
+		TradableMinPrice:    &tradable,
+		NonTradableMinPrice: &nonTradable,
+		Quantity:            3,
+	}
+
+	updated := ApplySale(original, newTestSaleEvent(t, SaleEventListed, "11.00", true))
+
+	if updated.Quantity != 4 {
+		t.Errorf("expected quantity 4, got %d", updated.Quantity)
+	}
+	if updated.TradableMinPrice.String() != "11" {
+		t.Errorf("expected tradable min 11, got %s", updated.TradableMinPrice)
+	}
+	if !updated.NonTradableMinPrice.Equal(nonTradable) {
+		t.Errorf("expected non-tradable min unchanged, got %s", updated.NonTradableMinPrice)
+	}
+	if updated.UpdatedAt != 1700000000 {
+		t.Errorf("expected updated_at from event, got %d", updated.UpdatedAt)
+	}
+
+	// Исходный предмет не должен изменяться — он может читаться конкурентно из кеша
+	if original.Quantity != 3 || !original.TradableMinPrice.Equal(tradable) {
+		t.Errorf("expected original item untouched, got %+v", original)
+	}
+
+	higher := ApplySale(updated, newTestSaleEvent(t, SaleEventListed, "30.00", false))
+	if higher.NonTradableMinPrice.String() != "10.2" {
+		t.Errorf("expected non-tradable min to stay 10.2, got %s", higher.NonTradableMinPrice)
+	}
+	if higher.MaxPrice.String() != "30" {
+		t.Errorf("expected max price 30, got %s", higher.MaxPrice)
+	}
+}
+
+func TestApplySale_Sold(t *testing.T) {
+	price := decimal.RequireFromString("12.50")
+	original := &Item{MarketHashName: "AK-47 | Redline (Field-Tested)", TradableMinPrice: &price, Quantity: 2}
+
+	once := ApplySale(original, newTestSaleEvent(t, SaleEventSold, "12.50", true))
+	if once.Quantity != 1 || once.TradableMinPrice == nil {
+		t.Errorf("expected quantity 1 with price kept, got %d / %v", once.Quantity, once.TradableMinPrice)
+	}
+
+	twice := ApplySale(once, newTestSaleEvent(t, SaleEventSold, "12.50", true))
+	if twice.Quantity != 0 || twice.TradableMinPrice != nil {
+		t.Errorf("expected sold out item without min price, got %d / %v", twice.Quantity, twice.TradableMinPrice)
+	}
+}
+
+func TestApplySale_NewItem(t *testing.T) {
+	if got := ApplySale(nil, newTestSaleEvent(t, SaleEventSold, "1", true)); got != nil {
+		t.Errorf("expected nil for sale of unknown item, got %+v", got)
+	}
+
+	created := ApplySale(nil, newTestSaleEvent(t, SaleEventListed, "5.5", true))
+	if created == nil || created.Quantity != 1 || created.TradableMinPrice.String() != "5.5" || created.Currency != "USD" {
+		t.Errorf("unexpected created item: %+v", created)
+	}
+}
@@ -0,0 +1,25 @@
+package item
+
+import "github.com/shopspring/decimal"
+
+// SalesStats представляет статистику продаж предмета за период.
+// Цены отсутствуют если за период не было продаж.
+type SalesStats struct {
+	Min    *decimal.Decimal `json:"min,omitempty"`
+	Max    *decimal.Decimal `json:"max,omitempty"`
+	Avg    *decimal.Decimal `json:"avg,omitempty"`
+	Median *decimal.Decimal `json:"median,omitempty"`
+	Volume int              `json:"volume"`
+}
+
+// SalesHistory представляет историю продаж предмета на Skinport
+type SalesHistory struct {
+	MarketHashName string     `json:"market_hash_name"`
+	Currency       string     `json:"currency"`
+	ItemPage       string     `json:"item_page"`
+	MarketPage     string     `json:"market_page"`
+	Last24Hours    SalesStats `json:"last_24_hours"`
+	Last7Days      SalesStats `json:"last_7_days"`
+	Last30Days     SalesStats `json:"last_30_days"`
+	Last90Days     SalesStats `json:"last_90_days"`
+}
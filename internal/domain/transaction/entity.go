@@ -52,3 +52,19 @@ func NewWithdrawTransaction(
 		"withdraw",
 	)
 }
+
+// NewDepositTransaction создает транзакцию зачисления
+func NewDepositTransaction(
+	userID int64,
+	amount decimal.Decimal,
+	balanceBefore decimal.Decimal,
+	balanceAfter decimal.Decimal,
+) *Transaction {
+	return NewTransaction(
+		userID,
+		amount,
+		balanceBefore,
+		balanceAfter,
+		"deposit",
+	)
+}
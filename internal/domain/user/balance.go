@@ -2,9 +2,18 @@ package user
 
 import "github.com/shopspring/decimal"
 
+// AmountPlaces число знаков после запятой в суммах; совпадает с масштабом колонок DECIMAL(15,2)
+const AmountPlaces = 2
+
+// IsValidAmount проверяет, что сумма положительна и не содержит долей меньше копейки.
+// Нули в конце допустимы: "10.500" равно "10.50"
+func IsValidAmount(amount decimal.Decimal) bool {
+	return amount.IsPositive() && amount.Equal(amount.Truncate(AmountPlaces))
+}
+
 // CanWithdraw проверяет, может ли пользователь снять указанную сумму
 func (u *User) CanWithdraw(amount decimal.Decimal) bool {
-	if amount.LessThanOrEqual(decimal.Zero) {
+	if u.Frozen || !IsValidAmount(amount) {
 		return false
 	}
 	return u.Balance.GreaterThanOrEqual(amount)
@@ -13,10 +22,14 @@ func (u *User) CanWithdraw(amount decimal.Decimal) bool {
 // Withdraw списывает сумму с баланса пользователя
 // Возвращает баланс до операции и ошибку если операция невозможна
 func (u *User) Withdraw(amount decimal.Decimal) (balanceBefore decimal.Decimal, err error) {
-	if amount.LessThanOrEqual(decimal.Zero) {
+	if !IsValidAmount(amount) {
 		return decimal.Zero, ErrInvalidAmount
 	}
 
+	if u.Frozen {
+		return decimal.Zero, ErrAccountFrozen
+	}
+
 	if u.Balance.LessThan(amount) {
 		return decimal.Zero, ErrInsufficientBalance
 	}
@@ -27,6 +40,33 @@ func (u *User) Withdraw(amount decimal.Decimal) (balanceBefore decimal.Decimal,
 	return balanceBefore, nil
 }
 
+// Deposit зачисляет сумму на баланс пользователя
+// Возвращает баланс до операции и ошибку если операция невозможна
+func (u *User) Deposit(amount decimal.Decimal) (balanceBefore decimal.Decimal, err error) {
+	if !IsValidAmount(amount) {
+		return decimal.Zero, ErrInvalidAmount
+	}
+
+	if u.Frozen {
+		return decimal.Zero, ErrAccountFrozen
+	}
+
+	balanceBefore = u.Balance
+	u.Balance = u.Balance.Add(amount)
+
+	return balanceBefore, nil
+}
+
+// Freeze замораживает счет
+func (u *User) Freeze() {
+	u.Frozen = true
+}
+
+// Unfreeze размораживает счет
+func (u *User) Unfreeze() {
+	u.Frozen = false
+}
+
 // GetBalance возвращает текущий баланс
 func (u *User) GetBalance() decimal.Decimal {
 	return u.Balance
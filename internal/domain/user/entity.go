@@ -6,6 +6,8 @@ import "github.com/shopspring/decimal"
 type User struct {
 	ID      int64           `json:"id"`
 	Balance decimal.Decimal `json:"balance"`
+	// Frozen замороженный счет не допускает движения средств
+	Frozen bool `json:"frozen"`
 }
 
 // NewUser создает нового пользователя
@@ -9,8 +9,11 @@ var (
 	// ErrInsufficientBalance возвращается когда недостаточно средств на балансе
 	ErrInsufficientBalance = errors.New("insufficient balance")
 
-	// ErrInvalidAmount возвращается когда сумма некорректна (отрицательная или ноль)
-	ErrInvalidAmount = errors.New("invalid amount: must be positive")
+	// ErrInvalidAmount возвращается когда сумма некорректна (не положительная или точнее копейки)
+	ErrInvalidAmount = errors.New("invalid amount: must be positive with at most 2 decimal places")
+
+	// ErrAccountFrozen возвращается при попытке движения средств по замороженному счету
+	ErrAccountFrozen = errors.New("account is frozen")
 
 	// ErrUserAlreadyExists возвращается когда пользователь уже существует
 	ErrUserAlreadyExists = errors.New("user already exists")
@@ -88,6 +88,7 @@ func TestUser_CanWithdraw(t *testing.T) {
 		{"insufficient balance", 50.00, 100.00, false},
 		{"zero amount", 100.00, 0, false},
 		{"negative amount", 100.00, -50.00, false},
+		{"fraction of a cent", 100.00, 10.005, false},
 	}
 
 	for _, tt := range tests {
@@ -123,3 +124,83 @@ func TestNewUser(t *testing.T) {
 		t.Errorf("expected balance 1234.56, got %s", user.Balance.String())
 	}
 }
+
+func TestUser_Deposit(t *testing.T) {
+	user := NewUser(1, decimal.NewFromFloat(100.00))
+
+	balanceBefore, err := user.Deposit(decimal.NewFromFloat(25.50))
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !balanceBefore.Equal(decimal.NewFromFloat(100.00)) {
+		t.Errorf("expected balance before 100.00, got %s", balanceBefore.String())
+	}
+
+	if !user.Balance.Equal(decimal.NewFromFloat(125.50)) {
+		t.Errorf("expected balance after 125.50, got %s", user.Balance.String())
+	}
+
+	if _, err := user.Deposit(decimal.Zero); err != ErrInvalidAmount {
+		t.Errorf("expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestUser_SubCentAmount(t *testing.T) {
+	tests := []struct {
+		amount string
+		valid  bool
+	}{
+		{"10", true},
+		{"10.5", true},
+		{"10.55", true},
+		{"10.500", true},
+		{"10.555", false},
+		{"0.001", false},
+		{"0.00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount, func(t *testing.T) {
+			amount := decimal.RequireFromString(tt.amount)
+			user := NewUser(1, decimal.NewFromInt(100))
+
+			_, err := user.Deposit(amount)
+			if (err == nil) != tt.valid || (err != nil && err != ErrInvalidAmount) {
+				t.Errorf("deposit: expected valid=%v, got %v", tt.valid, err)
+			}
+			_, err = user.Withdraw(amount)
+			if (err == nil) != tt.valid || (err != nil && err != ErrInvalidAmount) {
+				t.Errorf("withdraw: expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestUser_Frozen(t *testing.T) {
+	user := NewUser(1, decimal.NewFromFloat(100.00))
+	user.Freeze()
+
+	if _, err := user.Withdraw(decimal.NewFromFloat(10.00)); err != ErrAccountFrozen {
+		t.Errorf("expected ErrAccountFrozen on withdraw, got %v", err)
+	}
+
+	if _, err := user.Deposit(decimal.NewFromFloat(10.00)); err != ErrAccountFrozen {
+		t.Errorf("expected ErrAccountFrozen on deposit, got %v", err)
+	}
+
+	if user.CanWithdraw(decimal.NewFromFloat(10.00)) {
+		t.Error("frozen account must not allow withdraw")
+	}
+
+	if !user.Balance.Equal(decimal.NewFromFloat(100.00)) {
+		t.Errorf("balance should not change on frozen account, got %s", user.Balance.String())
+	}
+
+	user.Unfreeze()
+
+	if _, err := user.Withdraw(decimal.NewFromFloat(10.00)); err != nil {
+		t.Errorf("expected withdraw after unfreeze, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen возвращается, пока автомат разомкнут и вызовы к зависимости не выполняются
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State состояние автомата
+type State int
+
+const (
+	// StateClosed вызовы выполняются, ошибки подряд подсчитываются
+	StateClosed State = iota
+	// StateOpen вызовы отклоняются до истечения паузы
+	StateOpen
+	// StateHalfOpen пауза истекла, следующий вызов — пробный
+	StateHalfOpen
+)
+
+// String возвращает название состояния для логов, метрик и проверки готовности
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker размыкается после threshold ошибок подряд и не пропускает вызовы в течение
+// cooldown. После паузы пропускается один пробный вызов: успех замыкает автомат,
+// ошибка снова размыкает его на cooldown.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker создает замкнутый автомат
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow проверяет, можно ли выполнить вызов. Каждый разрешенный вызов должен
+// завершиться Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case StateClosed:
+		return nil
+	case StateHalfOpen:
+		// Пока идет пробный вызов, остальные отклоняются
+		if b.probing {
+			return ErrOpen
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return nil
+	default:
+		return ErrOpen
+	}
+}
+
+// Record сообщает результат разрешенного вызова
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.state = StateClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
+
+// Cancel освобождает разрешение без результата, например если вызывающий отменил запрос.
+// Ошибки подряд при этом не сбрасываются.
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// State возвращает текущее состояние. Разомкнутый автомат с истекшей паузой — half-open.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.currentState()
+}
+
+func (b *Breaker) currentState() State {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return StateHalfOpen
+	}
+	return b.state
+}
@@ -0,0 +1,94 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errBoom = errors.New("boom")
+
+func newTestBreaker(now *time.Time) *Breaker {
+	b := NewBreaker(3, 30*time.Second)
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	b := newTestBreaker(&now)
+
+	// Успех сбрасывает счетчик ошибок подряд
+	for _, err := range []error{errBoom, errBoom, nil, errBoom, errBoom} {
+		if b.Allow() != nil {
+			t.Fatal("expected closed breaker to allow calls")
+		}
+		b.Record(err)
+	}
+	if b.State() != StateClosed {
+		t.Fatalf("expected closed after non-consecutive failures, got %s", b.State())
+	}
+
+	b.Allow() //nolint:errcheck // closed breaker always allows
+	b.Record(errBoom)
+	if b.State() != StateOpen {
+		t.Fatalf("expected open after 3 consecutive failures, got %s", b.State())
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Errorf("expected ErrOpen, got %v", err)
+	}
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	b := newTestBreaker(&now)
+	for i := 0; i < 3; i++ {
+		b.Allow() //nolint:errcheck // closed breaker always allows
+		b.Record(errBoom)
+	}
+
+	now = now.Add(30 * time.Second)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("expected half-open after cooldown, got %s", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatal("expected only one probe at a time")
+	}
+
+	// Неудачная проба снова размыкает автомат на полную паузу
+	b.Record(errBoom)
+	now = now.Add(29 * time.Second)
+	if b.State() != StateOpen {
+		t.Fatalf("expected open after failed probe, got %s", b.State())
+	}
+
+	now = now.Add(time.Second)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected second probe to be allowed, got %v", err)
+	}
+	b.Record(nil)
+	if b.State() != StateClosed || b.Allow() != nil {
+		t.Fatalf("expected closed after successful probe, got %s", b.State())
+	}
+}
+
+func TestBreaker_CancelReleasesProbe(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	b := newTestBreaker(&now)
+	for i := 0; i < 3; i++ {
+		b.Allow() //nolint:errcheck // closed breaker always allows
+		b.Record(errBoom)
+	}
+	now = now.Add(time.Minute)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected probe, got %v", err)
+	}
+	b.Cancel()
+	if err := b.Allow(); err != nil {
+		t.Errorf("expected a new probe after cancel, got %v", err)
+	}
+}
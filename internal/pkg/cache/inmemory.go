@@ -1,37 +1,147 @@
 package cache
 
 import (
+	"container/list"
 	"context"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// EvictionReason причина удаления элемента из кэша
+type EvictionReason int
+
+const (
+	// EvictedCapacity — вытеснен по LRU из-за лимита записей или памяти
+	EvictedCapacity EvictionReason = iota
+	// EvictedExpired — истек TTL
+	EvictedExpired
+	// EvictedReplaced — перезаписан новым значением по тому же ключу
+	EvictedReplaced
+	// EvictedDeleted — удален явно через Delete или Clear
+	EvictedDeleted
 )
 
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictedCapacity:
+		return "capacity"
+	case EvictedExpired:
+		return "expired"
+	case EvictedReplaced:
+		return "replaced"
+	case EvictedDeleted:
+		return "deleted"
+	default:
+		return "unknown"
+	}
+}
+
+// InMemoryCache нетипизированный in-memory кэш, реализует output.Cache
+type InMemoryCache = Memory[string, interface{}]
+
+// Options параметры нетипизированного in-memory кэша
+type Options = MemoryOptions[string, interface{}]
+
+// MemoryOptions параметры in-memory кэша
+type MemoryOptions[K comparable, V any] struct {
+	// CleanupInterval период удаления устаревших элементов
+	CleanupInterval time.Duration
+	// MaxEntries максимальное количество элементов, 0 — без ограничения
+	MaxEntries int
+	// MaxBytes приблизительный лимит памяти под элементы, 0 — без ограничения
+	MaxBytes int64
+	// Sizer оценка размера элемента в байтах; по умолчанию EstimateSize ключа и значения
+	Sizer func(key K, value V) int64
+	// OnEvict вызывается после удаления элемента из кэша. Вызывается вне блокировки,
+	// поэтому может обращаться к самому кэшу.
+	OnEvict func(key K, value V, reason EvictionReason)
+}
+
+// Stats счетчики кэша
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64 // вытеснения по лимитам
+	Expirations uint64 // удаления по TTL
+	Entries     int
+	Bytes       int64
+}
+
 // item представляет элемент кэша
-type item struct {
-	value      interface{}
+type item[K comparable, V any] struct {
+	key        K
+	value      V
+	size       int64
 	expiration time.Time
 }
 
 // isExpired проверяет, истек ли срок действия элемента
-func (i *item) isExpired() bool {
-	return time.Now().After(i.expiration)
+func (i *item[K, V]) isExpired(now time.Time) bool {
+	return now.After(i.expiration)
 }
 
-// InMemoryCache реализует in-memory кэш с TTL
-type InMemoryCache struct {
-	mu    sync.RWMutex
-	items map[string]*item
+// evicted удаленный элемент, обработчик которого нужно вызвать после снятия блокировки
+type evicted[K comparable, V any] struct {
+	item   *item[K, V]
+	reason EvictionReason
+}
+
+// Memory реализует типизированный in-memory кэш с TTL и LRU вытеснением
+type Memory[K comparable, V any] struct {
+	// Get тоже меняет порядок LRU, поэтому используется обычный мьютекс
+	mu    sync.Mutex
+	items map[K]*list.Element
+	lru   *list.List // в начале — недавно использованные
+	bytes int64
+
+	maxEntries int
+	maxBytes   int64
+	sizer      func(key K, value V) int64
+	onEvict    func(key K, value V, reason EvictionReason)
+
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
 
 	// Для автоматической очистки
 	cleanupInterval time.Duration
 	stopCleanup     chan struct{}
 }
 
-// NewInMemoryCache создает новый in-memory кэш
+// NewInMemoryCache создает новый in-memory кэш без ограничения размера
 func NewInMemoryCache(cleanupInterval time.Duration) *InMemoryCache {
-	c := &InMemoryCache{
-		items:           make(map[string]*item),
-		cleanupInterval: cleanupInterval,
+	return NewInMemoryCacheWithOptions(Options{CleanupInterval: cleanupInterval})
+}
+
+// NewInMemoryCacheWithOptions создает in-memory кэш с лимитами размера
+func NewInMemoryCacheWithOptions(opts Options) *InMemoryCache {
+	return NewMemory(opts)
+}
+
+// NewMemory создает типизированный in-memory кэш
+func NewMemory[K comparable, V any](opts MemoryOptions[K, V]) *Memory[K, V] {
+	if opts.CleanupInterval <= 0 {
+		opts.CleanupInterval = time.Minute
+	}
+	if opts.Sizer == nil {
+		opts.Sizer = func(key K, value V) int64 {
+			return EstimateSize(key) + EstimateSize(value)
+		}
+	}
+
+	c := &Memory[K, V]{
+		items:           make(map[K]*list.Element),
+		lru:             list.New(),
+		maxEntries:      opts.MaxEntries,
+		maxBytes:        opts.MaxBytes,
+		sizer:           opts.Sizer,
+		onEvict:         opts.OnEvict,
+		cleanupInterval: opts.CleanupInterval,
 		stopCleanup:     make(chan struct{}),
 	}
 
@@ -42,56 +152,138 @@ func NewInMemoryCache(cleanupInterval time.Duration) *InMemoryCache {
 }
 
 // Get получает значение из кэша
-func (c *InMemoryCache) Get(ctx context.Context, key string) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Memory[K, V]) Get(ctx context.Context, key K) (V, bool) {
+	value, _, ok := c.GetWithTTL(ctx, key)
+	return value, ok
+}
+
+// GetWithTTL получает значение из кэша вместе с оставшимся временем жизни
+func (c *Memory[K, V]) GetWithTTL(ctx context.Context, key K) (V, time.Duration, bool) {
+	var zero V
 
-	item, ok := c.items[key]
+	c.mu.Lock()
+
+	el, ok := c.items[key]
 	if !ok {
-		return nil, false
+		c.mu.Unlock()
+		c.misses.Add(1)
+		return zero, 0, false
 	}
 
-	if item.isExpired() {
-		return nil, false
+	now := time.Now()
+	it := el.Value.(*item[K, V])
+	if it.isExpired(now) {
+		c.removeElement(el)
+		c.mu.Unlock()
+
+		c.misses.Add(1)
+		c.expirations.Add(1)
+		c.notify([]evicted[K, V]{{it, EvictedExpired}})
+		return zero, 0, false
 	}
 
-	return item.value, true
-}
+	c.lru.MoveToFront(el)
+	c.mu.Unlock()
 
-// Set устанавливает значение в кэш с указанным TTL
-func (c *InMemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.hits.Add(1)
+	return it.value, it.expiration.Sub(now), true
+}
 
-	c.items[key] = &item{
+// Set устанавливает значение в кэш с указанным TTL.
+// Элемент, который один превышает лимит памяти, в кэш не попадает.
+func (c *Memory[K, V]) Set(ctx context.Context, key K, value V, ttl time.Duration) {
+	newItem := &item[K, V]{
+		key:        key,
 		value:      value,
 		expiration: time.Now().Add(ttl),
 	}
+	if c.maxBytes > 0 {
+		// Размер считаем только когда он нужен — оценка обходит значение целиком
+		newItem.size = c.sizer(key, value)
+	}
+
+	var removed []evicted[K, V]
+
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		removed = append(removed, evicted[K, V]{el.Value.(*item[K, V]), EvictedReplaced})
+		c.removeElement(el)
+	}
+
+	if c.maxBytes > 0 && newItem.size > c.maxBytes {
+		c.mu.Unlock()
+
+		c.evictions.Add(1)
+		removed = append(removed, evicted[K, V]{newItem, EvictedCapacity})
+		c.notify(removed)
+		return
+	}
+
+	c.items[key] = c.lru.PushFront(newItem)
+	c.bytes += newItem.size
+	removed = append(removed, c.evictOverflow()...)
+	c.mu.Unlock()
+
+	c.notify(removed)
 }
 
 // Delete удаляет значение из кэша
-func (c *InMemoryCache) Delete(ctx context.Context, key string) {
+func (c *Memory[K, V]) Delete(ctx context.Context, key K) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		c.mu.Unlock()
+		return
+	}
+	it := el.Value.(*item[K, V])
+	c.removeElement(el)
+	c.mu.Unlock()
 
-	delete(c.items, key)
+	c.notify([]evicted[K, V]{{it, EvictedDeleted}})
 }
 
 // Clear очищает весь кэш
-func (c *InMemoryCache) Clear(ctx context.Context) {
+func (c *Memory[K, V]) Clear(ctx context.Context) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	var removed []evicted[K, V]
+	if c.onEvict != nil {
+		removed = make([]evicted[K, V], 0, c.lru.Len())
+		for el := c.lru.Front(); el != nil; el = el.Next() {
+			removed = append(removed, evicted[K, V]{el.Value.(*item[K, V]), EvictedDeleted})
+		}
+	}
+
+	c.items = make(map[K]*list.Element)
+	c.lru.Init()
+	c.bytes = 0
+	c.mu.Unlock()
 
-	c.items = make(map[string]*item)
+	c.notify(removed)
+}
+
+// Stats возвращает счетчики попаданий, промахов и вытеснений
+func (c *Memory[K, V]) Stats() Stats {
+	c.mu.Lock()
+	entries, bytes := c.lru.Len(), c.bytes
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+		Entries:     entries,
+		Bytes:       bytes,
+	}
 }
 
 // Close останавливает горутину очистки
-func (c *InMemoryCache) Close() {
+func (c *Memory[K, V]) Close() {
 	close(c.stopCleanup)
 }
 
 // cleanup периодически очищает устаревшие элементы
-func (c *InMemoryCache) cleanup() {
+func (c *Memory[K, V]) cleanup() {
 	ticker := time.NewTicker(c.cleanupInterval)
 	defer ticker.Stop()
 
@@ -106,21 +298,99 @@ func (c *InMemoryCache) cleanup() {
 }
 
 // deleteExpired удаляет все устаревшие элементы
-func (c *InMemoryCache) deleteExpired() {
+func (c *Memory[K, V]) deleteExpired() {
+	now := time.Now()
+	var removed []evicted[K, V]
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	for el := c.lru.Back(); el != nil; {
+		prev := el.Prev()
+		if it := el.Value.(*item[K, V]); it.isExpired(now) {
+			c.removeElement(el)
+			removed = append(removed, evicted[K, V]{it, EvictedExpired})
+		}
+		el = prev
+	}
+	c.mu.Unlock()
 
-	for key, item := range c.items {
-		if item.isExpired() {
-			delete(c.items, key)
+	c.expirations.Add(uint64(len(removed)))
+	c.notify(removed)
+}
+
+// evictOverflow вытесняет наименее недавно использованные элементы сверх лимитов.
+// Вызывается под мьютексом.
+func (c *Memory[K, V]) evictOverflow() []evicted[K, V] {
+	var removed []evicted[K, V]
+	for c.overLimit() {
+		el := c.lru.Back()
+		if el == nil {
+			break
 		}
+		it := el.Value.(*item[K, V])
+		c.removeElement(el)
+		removed = append(removed, evicted[K, V]{it, EvictedCapacity})
 	}
+
+	c.evictions.Add(uint64(len(removed)))
+	return removed
+}
+
+func (c *Memory[K, V]) overLimit() bool {
+	return (c.maxEntries > 0 && c.lru.Len() > c.maxEntries) ||
+		(c.maxBytes > 0 && c.bytes > c.maxBytes)
+}
+
+// removeElement удаляет элемент из индекса и списка LRU. Вызывается под мьютексом.
+func (c *Memory[K, V]) removeElement(el *list.Element) {
+	it := c.lru.Remove(el).(*item[K, V])
+	delete(c.items, it.key)
+	c.bytes -= it.size
+}
+
+func (c *Memory[K, V]) notify(removed []evicted[K, V]) {
+	if c.onEvict == nil {
+		return
+	}
+	for _, e := range removed {
+		c.onEvict(e.item.key, e.item.value, e.reason)
+	}
+}
+
+// Entries возвращает сведения об актуальных элементах в порядке от недавно использованных.
+// Размер считается оценкой Sizer, если он не был посчитан при записи.
+func (c *Memory[K, V]) Entries(ctx context.Context) ([]output.CacheEntryInfo, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	items := make([]*item[K, V], 0, c.lru.Len())
+	for el := c.lru.Front(); el != nil; el = el.Next() {
+		if it := el.Value.(*item[K, V]); !it.isExpired(now) {
+			items = append(items, it)
+		}
+	}
+	c.mu.Unlock()
+
+	// Оценка размера обходит значения целиком — делаем ее вне блокировки
+	entries := make([]output.CacheEntryInfo, 0, len(items))
+	for _, it := range items {
+		size := it.size
+		if c.maxBytes <= 0 {
+			size = c.sizer(it.key, it.value)
+		}
+		entries = append(entries, output.CacheEntryInfo{
+			Key:       fmt.Sprint(it.key),
+			ExpiresIn: it.expiration.Sub(now),
+			SizeBytes: size,
+		})
+	}
+
+	return entries, nil
 }
 
 // Len возвращает количество элементов в кэше
-func (c *InMemoryCache) Len() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Memory[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	return len(c.items)
+	return c.lru.Len()
 }
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 )
@@ -68,6 +69,26 @@ func TestInMemoryCache_Expiration(t *testing.T) {
 	}
 }
 
+func TestInMemoryCache_GetWithTTL(t *testing.T) {
+	cache := NewInMemoryCache(time.Minute)
+	defer cache.Close()
+
+	ctx := context.Background()
+	cache.Set(ctx, "key", "value", time.Second)
+
+	val, ttl, ok := cache.GetWithTTL(ctx, "key")
+	if !ok || val != "value" {
+		t.Fatalf("expected value, got %v, %v", val, ok)
+	}
+	if ttl <= 0 || ttl > time.Second {
+		t.Errorf("expected ttl in (0, 1s], got %v", ttl)
+	}
+
+	if _, _, ok := cache.GetWithTTL(ctx, "missing"); ok {
+		t.Error("expected miss for missing key")
+	}
+}
+
 func TestInMemoryCache_Delete(t *testing.T) {
 	cache := NewInMemoryCache(time.Minute)
 	defer cache.Close()
@@ -192,3 +213,168 @@ func TestInMemoryCache_DifferentTypes(t *testing.T) {
 		t.Errorf("expected test, got %v", val)
 	}
 }
+
+func TestInMemoryCache_LRUEviction(t *testing.T) {
+	var evictedKeys []string
+	cache := NewInMemoryCacheWithOptions(Options{
+		MaxEntries: 2,
+		OnEvict: func(key string, value interface{}, reason EvictionReason) {
+			if reason == EvictedCapacity {
+				evictedKeys = append(evictedKeys, key)
+			}
+		},
+	})
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	cache.Set(ctx, "a", 1, time.Minute)
+	cache.Set(ctx, "b", 2, time.Minute)
+
+	// Чтение делает "a" недавно использованным — вытеснен должен быть "b"
+	cache.Get(ctx, "a")
+	cache.Set(ctx, "c", 3, time.Minute)
+
+	if _, ok := cache.Get(ctx, "b"); ok {
+		t.Error("expected least recently used key to be evicted")
+	}
+	if _, ok := cache.Get(ctx, "a"); !ok {
+		t.Error("expected recently used key to survive")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 items, got %d", cache.Len())
+	}
+	if len(evictedKeys) != 1 || evictedKeys[0] != "b" {
+		t.Errorf("expected eviction callback for b, got %v", evictedKeys)
+	}
+}
+
+func TestInMemoryCache_MaxBytes(t *testing.T) {
+	cache := NewInMemoryCacheWithOptions(Options{
+		MaxBytes: 100,
+		Sizer:    func(key string, value interface{}) int64 { return int64(len(value.(string))) },
+	})
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	cache.Set(ctx, "a", strings.Repeat("x", 40), time.Minute)
+	cache.Set(ctx, "b", strings.Repeat("x", 40), time.Minute)
+	cache.Set(ctx, "c", strings.Repeat("x", 40), time.Minute)
+
+	if _, ok := cache.Get(ctx, "a"); ok {
+		t.Error("expected oldest key to be evicted when over byte limit")
+	}
+	if stats := cache.Stats(); stats.Bytes != 80 || stats.Entries != 2 {
+		t.Errorf("expected 2 entries and 80 bytes, got %+v", stats)
+	}
+
+	// Значение больше всего лимита не сохраняется и не вытесняет остальные
+	cache.Set(ctx, "huge", strings.Repeat("x", 101), time.Minute)
+	if _, ok := cache.Get(ctx, "huge"); ok {
+		t.Error("expected oversized value to be rejected")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected existing entries to survive oversized set, got %d", cache.Len())
+	}
+
+	// Перезапись освобождает память старого значения
+	cache.Set(ctx, "b", "x", time.Minute)
+	if stats := cache.Stats(); stats.Bytes != 41 {
+		t.Errorf("expected 41 bytes after overwrite, got %d", stats.Bytes)
+	}
+}
+
+func TestInMemoryCache_Stats(t *testing.T) {
+	var reasons []EvictionReason
+	cache := NewInMemoryCacheWithOptions(Options{
+		MaxEntries: 1,
+		OnEvict: func(key string, value interface{}, reason EvictionReason) {
+			reasons = append(reasons, reason)
+		},
+	})
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	cache.Set(ctx, "a", 1, time.Minute)
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "missing")
+	cache.Set(ctx, "a", 2, time.Minute)
+	cache.Set(ctx, "b", 3, time.Minute)
+	cache.Set(ctx, "short", 4, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	cache.Get(ctx, "short")
+	cache.Set(ctx, "c", 5, time.Minute)
+	cache.Delete(ctx, "c")
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Evictions != 2 || stats.Expirations != 1 || stats.Entries != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	expected := []EvictionReason{EvictedReplaced, EvictedCapacity, EvictedCapacity, EvictedExpired, EvictedDeleted}
+	if len(reasons) != len(expected) {
+		t.Fatalf("expected reasons %v, got %v", expected, reasons)
+	}
+	for i := range expected {
+		if reasons[i] != expected[i] {
+			t.Errorf("reason %d: expected %s, got %s", i, expected[i], reasons[i])
+		}
+	}
+}
+
+func TestEstimateSize(t *testing.T) {
+	type entry struct {
+		Name  string
+		Price *int64
+	}
+
+	price := int64(100)
+	shared := &entry{Name: strings.Repeat("x", 1000), Price: &price}
+
+	single := EstimateSize([]*entry{shared})
+	if single < 1000 {
+		t.Errorf("expected size to include string data, got %d", single)
+	}
+
+	// Один и тот же объект учитывается один раз
+	twice := EstimateSize([]*entry{shared, shared})
+	if twice-single > 64 {
+		t.Errorf("expected shared pointer to be counted once, got %d vs %d", twice, single)
+	}
+
+	m := EstimateSize(map[string]*entry{"a": {Name: strings.Repeat("y", 500)}})
+	if m < 500 {
+		t.Errorf("expected map size to include values, got %d", m)
+	}
+}
+
+func TestInMemoryCache_Entries(t *testing.T) {
+	cache := NewInMemoryCache(time.Minute)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	cache.Set(ctx, "old", strings.Repeat("x", 100), time.Minute)
+	cache.Set(ctx, "new", "y", time.Hour)
+	cache.Set(ctx, "expired", "z", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	entries, err := cache.Entries(ctx)
+	if err != nil {
+		t.Fatalf("entries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 live entries, got %+v", entries)
+	}
+	if entries[0].Key != "new" || entries[1].Key != "old" {
+		t.Errorf("expected most recently used first, got %s, %s", entries[0].Key, entries[1].Key)
+	}
+	if entries[0].ExpiresIn <= time.Minute || entries[1].ExpiresIn > time.Minute {
+		t.Errorf("unexpected remaining ttl: %s, %s", entries[0].ExpiresIn, entries[1].ExpiresIn)
+	}
+	if entries[1].SizeBytes < 100 {
+		t.Errorf("expected size estimate to include value, got %d", entries[1].SizeBytes)
+	}
+}
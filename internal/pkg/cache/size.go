@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// EstimateSize приблизительно оценивает память, занимаемую значением: обходит указатели,
+// слайсы, строки и мапы, учитывая каждый объект один раз. Оценка не учитывает выравнивание
+// аллокатора и служебные структуры мапы, поэтому подходит для лимитов, а не для точного учета.
+func EstimateSize(v interface{}) int64 {
+	if v == nil {
+		return 0
+	}
+
+	e := sizeEstimator{seen: make(map[uintptr]struct{})}
+	rv := reflect.ValueOf(v)
+	return int64(rv.Type().Size()) + e.indirect(rv)
+}
+
+type sizeEstimator struct {
+	seen map[uintptr]struct{}
+}
+
+// visit отмечает объект по адресу и сообщает, встречался ли он раньше
+func (e *sizeEstimator) visit(ptr uintptr) bool {
+	if ptr == 0 {
+		return false
+	}
+	if _, ok := e.seen[ptr]; ok {
+		return false
+	}
+	e.seen[ptr] = struct{}{}
+	return true
+}
+
+// indirect возвращает размер данных, на которые ссылается значение, без размера самого значения
+func (e *sizeEstimator) indirect(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || !e.visit(v.Pointer()) {
+			return 0
+		}
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + e.indirect(elem)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + e.indirect(elem)
+
+	case reflect.String:
+		if v.Len() == 0 || !e.visit(uintptr(unsafe.Pointer(unsafe.StringData(v.String())))) {
+			return 0
+		}
+		return int64(v.Len())
+
+	case reflect.Slice:
+		if v.IsNil() || !e.visit(v.Pointer()) {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			size += e.indirect(v.Index(i))
+		}
+		return size
+
+	case reflect.Array:
+		var size int64
+		for i := 0; i < v.Len(); i++ {
+			size += e.indirect(v.Index(i))
+		}
+		return size
+
+	case reflect.Map:
+		if v.IsNil() || !e.visit(v.Pointer()) {
+			return 0
+		}
+		entry := int64(v.Type().Key().Size() + v.Type().Elem().Size())
+		size := int64(v.Len()) * entry
+		iter := v.MapRange()
+		for iter.Next() {
+			size += e.indirect(iter.Key()) + e.indirect(iter.Value())
+		}
+		return size
+
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += e.indirect(v.Field(i))
+		}
+		return size
+
+	default:
+		return 0
+	}
+}
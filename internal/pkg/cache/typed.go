@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// Typed адаптирует нетипизированный кэш к типизированному интерфейсу на время миграции.
+// Значение другого типа под ключом считается промахом, но не молча: оно учитывается
+// в Mismatches и передается в обработчик onMismatch.
+type Typed[V any] struct {
+	cache      output.Cache
+	onMismatch func(key string, value interface{})
+	mismatches atomic.Uint64
+}
+
+// NewTyped создает типизированную обертку над кэшем. onMismatch может быть nil.
+func NewTyped[V any](c output.Cache, onMismatch func(key string, value interface{})) *Typed[V] {
+	return &Typed[V]{cache: c, onMismatch: onMismatch}
+}
+
+// Get получает значение из кэша
+func (t *Typed[V]) Get(ctx context.Context, key string) (V, bool) {
+	var zero V
+
+	cached, ok := t.cache.Get(ctx, key)
+	if !ok {
+		return zero, false
+	}
+
+	value, ok := cached.(V)
+	if !ok {
+		t.mismatches.Add(1)
+		if t.onMismatch != nil {
+			t.onMismatch(key, cached)
+		}
+		return zero, false
+	}
+
+	return value, true
+}
+
+// Set устанавливает значение в кэш с указанным TTL
+func (t *Typed[V]) Set(ctx context.Context, key string, value V, ttl time.Duration) {
+	t.cache.Set(ctx, key, value, ttl)
+}
+
+// Delete удаляет значение из кэша
+func (t *Typed[V]) Delete(ctx context.Context, key string) {
+	t.cache.Delete(ctx, key)
+}
+
+// Clear очищает нижележащий кэш целиком, включая значения других типов
+func (t *Typed[V]) Clear(ctx context.Context) {
+	t.cache.Clear(ctx)
+}
+
+// Mismatches возвращает количество чтений, вернувших значение другого типа
+func (t *Typed[V]) Mismatches() uint64 {
+	return t.mismatches.Load()
+}
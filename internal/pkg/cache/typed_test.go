@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTyped_MismatchIsReported(t *testing.T) {
+	untyped := NewInMemoryCache(time.Minute)
+	defer untyped.Close()
+
+	var reported string
+	typed := NewTyped[[]string](untyped, func(key string, value interface{}) {
+		reported = key
+	})
+
+	ctx := context.Background()
+
+	typed.Set(ctx, "names", []string{"a", "b"}, time.Minute)
+	names, ok := typed.Get(ctx, "names")
+	if !ok || len(names) != 2 {
+		t.Fatalf("expected typed value, got %v, %v", names, ok)
+	}
+
+	// Другой код положил под ключ значение другого типа
+	untyped.Set(ctx, "names", 42, time.Minute)
+	if _, ok := typed.Get(ctx, "names"); ok {
+		t.Error("expected value of another type to be a miss")
+	}
+	if typed.Mismatches() != 1 || reported != "names" {
+		t.Errorf("expected mismatch to be reported, got %d mismatches, key %q", typed.Mismatches(), reported)
+	}
+
+	// Обычный промах не считается несовпадением типа
+	typed.Get(ctx, "missing")
+	if typed.Mismatches() != 1 {
+		t.Errorf("expected plain miss not to count as mismatch, got %d", typed.Mismatches())
+	}
+}
+
+func TestMemory_Typed(t *testing.T) {
+	type key struct {
+		currency string
+		game     int
+	}
+
+	var evicted []key
+	c := NewMemory(MemoryOptions[key, []int]{
+		MaxEntries: 1,
+		OnEvict: func(k key, v []int, reason EvictionReason) {
+			evicted = append(evicted, k)
+		},
+	})
+	defer c.Close()
+
+	ctx := context.Background()
+
+	c.Set(ctx, key{"USD", 730}, []int{1, 2}, time.Minute)
+	c.Set(ctx, key{"EUR", 730}, []int{3}, time.Minute)
+
+	if _, ok := c.Get(ctx, key{"USD", 730}); ok {
+		t.Error("expected first key to be evicted")
+	}
+	v, ok := c.Get(ctx, key{"EUR", 730})
+	if !ok || len(v) != 1 || v[0] != 3 {
+		t.Errorf("expected typed value for second key, got %v, %v", v, ok)
+	}
+	if len(evicted) != 1 || evicted[0] != (key{"USD", 730}) {
+		t.Errorf("expected typed eviction callback, got %v", evicted)
+	}
+}
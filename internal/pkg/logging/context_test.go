@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(NewContextHandler(slog.NewJSONHandler(buf, nil)))
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to decode log record %q: %v", buf.String(), err)
+	}
+	buf.Reset()
+	return record
+}
+
+func TestContextHandler_AddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf).With(slog.String("component", "test"))
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.InfoContext(ctx, "withdraw failed", slog.String("error", "boom"))
+
+	record := decode(t, &buf)
+	if record[RequestIDKey] != "req-1" {
+		t.Errorf("expected request_id req-1, got %v", record[RequestIDKey])
+	}
+	if record["component"] != "test" || record["error"] != "boom" {
+		t.Errorf("expected logger and call attributes to be kept, got %v", record)
+	}
+	if got := RequestID(ctx); got != "req-1" {
+		t.Errorf("RequestID() = %q, want req-1", got)
+	}
+}
+
+func TestContextHandler_WithoutContextAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf)
+
+	logger.Info("startup")
+
+	record := decode(t, &buf)
+	if _, ok := record[RequestIDKey]; ok {
+		t.Errorf("expected no request_id, got %v", record[RequestIDKey])
+	}
+	if got := RequestID(context.Background()); got != "" {
+		t.Errorf("RequestID() = %q, want empty", got)
+	}
+}
+
+func TestWithAttrs_Accumulates(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf)
+
+	base := WithRequestID(context.Background(), "req-1")
+	ctx := WithAttrs(base, slog.String("user_id", "42"))
+	logger.WarnContext(ctx, "frozen")
+
+	record := decode(t, &buf)
+	if record[RequestIDKey] != "req-1" || record["user_id"] != "42" {
+		t.Errorf("expected request_id and user_id, got %v", record)
+	}
+
+	// Родительский контекст не видит атрибуты дочернего
+	logger.WarnContext(base, "frozen")
+	if record := decode(t, &buf); record["user_id"] != nil {
+		t.Errorf("expected parent context without user_id, got %v", record)
+	}
+}
@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDKey имя атрибута лога с идентификатором запроса
+const RequestIDKey = "request_id"
+
+type requestIDKey struct{}
+
+// WithRequestID сохраняет идентификатор запроса в контексте и добавляет его
+// ко всем записям лога, сделанным с этим контекстом
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return WithAttrs(ctx, slog.String(RequestIDKey, id))
+}
+
+// RequestID возвращает идентификатор запроса из контекста или пустую строку
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID генерирует новый идентификатор запроса
+func NewRequestID() string {
+	return uuid.NewString()
+}
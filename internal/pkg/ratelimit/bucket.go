@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// Bucket состояние token bucket
+type Bucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// NewBucket создает полный bucket
+func NewBucket(limit output.RateLimit, now time.Time) *Bucket {
+	return &Bucket{Tokens: float64(limit.Burst), Updated: now}
+}
+
+// refill пополняет bucket за время, прошедшее с последнего обращения
+func (b *Bucket) refill(limit output.RateLimit, now time.Time) {
+	if elapsed := now.Sub(b.Updated); elapsed > 0 {
+		b.Tokens = math.Min(float64(limit.Burst), b.Tokens+elapsed.Seconds()*limit.Rate)
+	}
+	b.Updated = now
+}
+
+// Take пополняет bucket и пытается списать один токен
+func (b *Bucket) Take(limit output.RateLimit, now time.Time) output.RateLimitResult {
+	b.refill(limit, now)
+
+	allowed := b.Tokens >= 1
+	if allowed {
+		b.Tokens--
+	}
+
+	return Result(b.Tokens, allowed, limit)
+}
+
+// Full сообщает, наполнился ли bucket к моменту now — такой bucket можно забыть без потери состояния
+func (b *Bucket) Full(limit output.RateLimit, now time.Time) bool {
+	elapsed := now.Sub(b.Updated).Seconds()
+	return b.Tokens+elapsed*limit.Rate >= float64(limit.Burst)
+}
+
+// Result переводит остаток токенов в результат попытки
+func Result(tokens float64, allowed bool, limit output.RateLimit) output.RateLimitResult {
+	result := output.RateLimitResult{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+	}
+	if limit.Rate <= 0 {
+		return result
+	}
+
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	result.ResetAfter = seconds((float64(limit.Burst) - tokens) / limit.Rate)
+
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+// sweepInterval как часто забываются наполнившиеся bucket'ы
+const sweepInterval = time.Minute
+
+type entry struct {
+	bucket *Bucket
+	limit  output.RateLimit
+}
+
+// MemoryStore хранит bucket'ы в памяти процесса. Каждая реплика считает бюджет отдельно.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore создает новый MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries:   make(map[string]*entry),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Take реализует output.RateLimitStore
+func (s *MemoryStore) Take(_ context.Context, key string, limit output.RateLimit) (output.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	e, ok := s.entries[key]
+	if !ok {
+		e = &entry{bucket: NewBucket(limit, now)}
+		s.entries[key] = e
+	}
+	e.limit = limit
+
+	return e.bucket.Take(limit, now), nil
+}
+
+// Len возвращает количество отслеживаемых ключей
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// sweep удаляет полные bucket'ы, чтобы разовые клиенты не копились в памяти.
+// Полный bucket неотличим от нового, поэтому удаление не меняет поведение.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, e := range s.entries {
+		if e.bucket.Full(e.limit, now) {
+			delete(s.entries, key)
+		}
+	}
+}
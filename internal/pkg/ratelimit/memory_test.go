@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/ports/output"
+)
+
+func newTestStore(now *time.Time) *MemoryStore {
+	s := NewMemoryStore()
+	s.now = func() time.Time { return *now }
+	s.lastSweep = *now
+	return s
+}
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	s := newTestStore(&now)
+	limit := output.RateLimit{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res, _ := s.Take(ctx, "client", limit)
+		if !res.Allowed {
+			t.Fatalf("request %d: expected to be allowed within burst", i)
+		}
+		if res.Remaining != 2-i {
+			t.Errorf("request %d: expected remaining %d, got %d", i, 2-i, res.Remaining)
+		}
+	}
+
+	res, _ := s.Take(ctx, "client", limit)
+	if res.Allowed {
+		t.Fatal("expected request over burst to be rejected")
+	}
+	if res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("expected retry after 500ms, got %s", res.RetryAfter)
+	}
+	if res.ResetAfter != 1500*time.Millisecond {
+		t.Errorf("expected reset after 1.5s, got %s", res.ResetAfter)
+	}
+
+	// Другой ключ имеет собственный бюджет
+	if res, _ := s.Take(ctx, "other", limit); !res.Allowed {
+		t.Error("expected independent budget for another key")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if res, _ := s.Take(ctx, "client", limit); !res.Allowed {
+		t.Error("expected a token to be refilled after 500ms")
+	}
+	if res, _ := s.Take(ctx, "client", limit); res.Allowed {
+		t.Error("expected bucket to be empty again")
+	}
+
+	// Пополнение не превышает емкость
+	now = now.Add(time.Hour)
+	res, _ = s.Take(ctx, "client", limit)
+	if res.Remaining != limit.Burst-1 {
+		t.Errorf("expected refill capped at burst, remaining %d", res.Remaining)
+	}
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	s := newTestStore(&now)
+	limit := output.RateLimit{Rate: 1, Burst: 2}
+	ctx := context.Background()
+
+	s.Take(ctx, "one-off", limit)
+	s.Take(ctx, "busy", limit)
+	if s.Len() != 2 {
+		t.Fatalf("expected 2 keys, got %d", s.Len())
+	}
+
+	// Через минуту оба bucket'а полны, остается только ключ текущего запроса
+	now = now.Add(sweepInterval)
+	s.Take(ctx, "busy", limit)
+	if s.Len() != 1 {
+		t.Errorf("expected full buckets to be swept, got %d keys", s.Len())
+	}
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpTracesPath путь OTLP/HTTP, который добавляется к адресу коллектора без пути
+const otlpTracesPath = "/v1/traces"
+
+// OTLPOptions параметры экспорта в коллектор OpenTelemetry
+type OTLPOptions struct {
+	// Endpoint адрес коллектора, например http://otel-collector:4318. Если путь не указан,
+	// добавляется /v1/traces.
+	Endpoint string
+	// Headers дополнительные заголовки, например токен доступа
+	Headers map[string]string
+	Timeout time.Duration
+}
+
+// NewOTLPExporter создает экспортер OTLP/HTTP (protobuf) из OpenTelemetry SDK.
+// Адрес проверяется здесь: SDK молча заменяет некорректный адрес на localhost.
+func NewOTLPExporter(ctx context.Context, opts OTLPOptions) (sdktrace.SpanExporter, error) {
+	u, err := url.Parse(opts.Endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid otlp endpoint %q", opts.Endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpTracesPath
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(u.String()),
+		otlptracehttp.WithHeaders(opts.Headers),
+		otlptracehttp.WithTimeout(opts.Timeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// ParseHeaders разбирает заголовки вида "key=value,key2=value2"
+func ParseHeaders(spec string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid otlp header %q", pair)
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestOTLPExporter_Export(t *testing.T) {
+	var (
+		gotPath        string
+		gotHeader      string
+		gotContentType string
+		gotBody        []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("X-Token")
+		gotContentType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	headers, err := ParseHeaders("X-Token = secret")
+	if err != nil {
+		t.Fatalf("ParseHeaders() error = %v", err)
+	}
+	exp, err := NewOTLPExporter(context.Background(), OTLPOptions{Endpoint: server.URL, Headers: headers})
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() error = %v", err)
+	}
+	defer exp.Shutdown(context.Background()) //nolint:errcheck // test cleanup
+
+	spans := tracetest.SpanStubs{{Name: "UserRepository.GetByIDForUpdate"}}.Snapshots()
+	if err := exp.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("ExportSpans() error = %v", err)
+	}
+
+	if gotPath != "/v1/traces" || gotHeader != "secret" {
+		t.Errorf("unexpected request path %q header %q", gotPath, gotHeader)
+	}
+	if gotContentType != "application/x-protobuf" || len(gotBody) == 0 {
+		t.Errorf("unexpected content type %q, body %d bytes", gotContentType, len(gotBody))
+	}
+}
+
+func TestOTLPExporter_CustomPath(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exp, err := NewOTLPExporter(context.Background(), OTLPOptions{Endpoint: server.URL + "/custom/traces"})
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() error = %v", err)
+	}
+	defer exp.Shutdown(context.Background()) //nolint:errcheck // test cleanup
+
+	if err := exp.ExportSpans(context.Background(), tracetest.SpanStubs{{Name: "x"}}.Snapshots()); err != nil {
+		t.Fatalf("ExportSpans() error = %v", err)
+	}
+	if gotPath != "/custom/traces" {
+		t.Errorf("path = %q, want /custom/traces", gotPath)
+	}
+}
+
+func TestOTLPExporter_CollectorError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	exp, err := NewOTLPExporter(context.Background(), OTLPOptions{Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() error = %v", err)
+	}
+	defer exp.Shutdown(context.Background()) //nolint:errcheck // test cleanup
+
+	if err := exp.ExportSpans(context.Background(), tracetest.SpanStubs{{Name: "x"}}.Snapshots()); err == nil {
+		t.Fatal("expected error for 400")
+	}
+}
+
+func TestNewOTLPExporter_InvalidEndpoint(t *testing.T) {
+	if _, err := NewOTLPExporter(context.Background(), OTLPOptions{Endpoint: "collector:4318"}); err == nil {
+		t.Error("expected error for endpoint without scheme")
+	}
+	if _, err := ParseHeaders("novalue"); err == nil {
+		t.Error("expected error for header without value")
+	}
+}
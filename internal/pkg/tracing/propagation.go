@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Заголовки W3C Trace Context
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// propagator передает контекст трассы в формате W3C Trace Context независимо
+// от глобальных настроек OpenTelemetry
+var propagator = propagation.TraceContext{}
+
+// Inject записывает traceparent и tracestate текущего span'а в заголовки исходящего запроса
+func Inject(ctx context.Context, h http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// Extract читает traceparent входящего запроса: следующий Start станет продолжением
+// трассы вызывающего. Некорректный заголовок игнорируется, как требует спецификация.
+func Extract(ctx context.Context, h http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(h))
+}
@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationScope имя библиотеки инструментирования в трассах
+const instrumentationScope = "github.com/akonovalovdev/DDD_example"
+
+// Options параметры TracerProvider
+type Options struct {
+	// ServiceName атрибут service.name ресурса
+	ServiceName string
+	// SampleRatio доля новых трасс, которые записываются; решение вызывающего
+	// процесса (флаг sampled в traceparent) соблюдается всегда
+	SampleRatio float64
+	// BatchSize сколько span'ов отправлять за раз
+	BatchSize int
+	// FlushInterval как часто отправлять неполный пакет
+	FlushInterval time.Duration
+	// QueueSize сколько span'ов ждут отправки; сверх этого новые отбрасываются
+	QueueSize int
+}
+
+// NewProvider создает TracerProvider OpenTelemetry SDK, который пакетами отдает
+// завершенные span'ы экспортеру в фоне
+func NewProvider(exporter sdktrace.SpanExporter, opts Options) *sdktrace.TracerProvider {
+	var batch []sdktrace.BatchSpanProcessorOption
+	if opts.BatchSize > 0 {
+		batch = append(batch, sdktrace.WithMaxExportBatchSize(opts.BatchSize))
+	}
+	if opts.FlushInterval > 0 {
+		batch = append(batch, sdktrace.WithBatchTimeout(opts.FlushInterval))
+	}
+	if opts.QueueSize > 0 {
+		batch = append(batch, sdktrace.WithMaxQueueSize(opts.QueueSize))
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, batch...),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", opts.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+}
+
+// Start открывает дочерний span текущего span'а из ctx (или контекста вызывающего
+// процесса) через глобальный TracerProvider. Без провайдера span не записывается,
+// но сохраняет контекст родителя для исходящих запросов.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationScope).Start(ctx, name, opts...)
+}
+
+// RecordError добавляет событие exception и помечает span как ошибочный. nil игнорируется.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const incomingTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// useTestProvider делает глобальным провайдер с экспортером в память
+func useTestProvider(t *testing.T, ratio float64) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exp := tracetest.NewInMemoryExporter()
+	tp := NewProvider(exp, Options{ServiceName: "test", SampleRatio: ratio, FlushInterval: time.Hour})
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		_ = tp.Shutdown(context.Background())
+	})
+	return tp, exp
+}
+
+func flush(t *testing.T, tp *sdktrace.TracerProvider, exp *tracetest.InMemoryExporter) map[string]tracetest.SpanStub {
+	t.Helper()
+
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush() error = %v", err)
+	}
+	out := make(map[string]tracetest.SpanStub)
+	for _, s := range exp.GetSpans() {
+		out[s.Name] = s
+	}
+	return out
+}
+
+func TestStart_ParentChild(t *testing.T) {
+	tp, exp := useTestProvider(t, 1)
+
+	ctx, root := Start(context.Background(), "root", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := Start(ctx, "child")
+	RecordError(child, errors.New("boom"))
+	RecordError(child, nil)
+	child.End()
+	root.End()
+
+	spans := flush(t, tp, exp)
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	r, c := spans["root"], spans["child"]
+	if c.SpanContext.TraceID() != r.SpanContext.TraceID() || c.Parent.SpanID() != r.SpanContext.SpanID() {
+		t.Error("child must belong to the root trace")
+	}
+	if c.Status.Code != codes.Error || c.Status.Description != "boom" || len(c.Events) != 1 {
+		t.Errorf("unexpected child status %+v events %d", c.Status, len(c.Events))
+	}
+	if service, ok := r.Resource.Set().Value("service.name"); !ok || service.AsString() != "test" {
+		t.Errorf("unexpected service.name %v", service)
+	}
+}
+
+func TestStart_ContinuesRemoteTrace(t *testing.T) {
+	// Решение вызывающего соблюдается даже при нулевой доле сэмплирования
+	tp, exp := useTestProvider(t, 0)
+
+	h := http.Header{}
+	h.Set(TraceparentHeader, incomingTraceparent)
+	_, span := Start(Extract(context.Background(), h), "server")
+	span.End()
+
+	s, ok := flush(t, tp, exp)["server"]
+	if !ok {
+		t.Fatal("expected sampled remote trace to be exported")
+	}
+	if s.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || s.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("unexpected trace %s parent %s", s.SpanContext.TraceID(), s.Parent.SpanID())
+	}
+}
+
+func TestStart_SampleRatio(t *testing.T) {
+	tp, exp := useTestProvider(t, 0)
+
+	_, span := Start(context.Background(), "dropped")
+	span.End()
+
+	if spans := flush(t, tp, exp); len(spans) != 0 {
+		t.Errorf("expected no spans with zero ratio, got %d", len(spans))
+	}
+}
+
+func TestStart_WithoutProviderPropagatesIncomingTrace(t *testing.T) {
+	otel.SetTracerProvider(noop.NewTracerProvider())
+
+	in := http.Header{}
+	in.Set(TraceparentHeader, incomingTraceparent)
+	ctx, span := Start(Extract(context.Background(), in), "server")
+	defer span.End()
+
+	out := http.Header{}
+	Inject(ctx, out)
+	if got := out.Get(TraceparentHeader); got != incomingTraceparent {
+		t.Errorf("traceparent = %q, want %q", got, incomingTraceparent)
+	}
+}
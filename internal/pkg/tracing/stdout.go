@@ -0,0 +1,14 @@
+package tracing
+
+import (
+	"io"
+
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewStdoutExporter создает экспортер, пишущий span'ы строками JSON в w, — для локальной
+// разработки без коллектора
+func NewStdoutExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
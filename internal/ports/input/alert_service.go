@@ -0,0 +1,26 @@
+package input
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+)
+
+// CreateAlertRequest содержит параметры нового ценового алерта
+type CreateAlertRequest struct {
+	MarketHashName string
+	Direction      alert.Direction
+	PriceType      alert.PriceType
+	Threshold      decimal.Decimal
+	WebhookURL     string
+}
+
+// AlertService определяет интерфейс сервиса ценовых алертов
+type AlertService interface {
+	// CreateAlert регистрирует ценовой алерт пользователя
+	CreateAlert(ctx context.Context, userID int64, req CreateAlertRequest) (*alert.Alert, error)
+	// ListAlerts возвращает алерты пользователя
+	ListAlerts(ctx context.Context, userID int64) ([]*alert.Alert, error)
+}
@@ -15,11 +15,27 @@ type WithdrawResult struct {
 	BalanceAfter  decimal.Decimal
 }
 
+// DepositResult содержит результат операции зачисления
+type DepositResult struct {
+	Transaction   *transaction.Transaction
+	BalanceBefore decimal.Decimal
+	BalanceAfter  decimal.Decimal
+}
+
 // BalanceService определяет интерфейс сервиса для работы с балансом
 type BalanceService interface {
 	// WithdrawBalance списывает средства с баланса пользователя
 	WithdrawBalance(ctx context.Context, userID int64, amount decimal.Decimal) (*WithdrawResult, error)
 
+	// DepositBalance зачисляет средства на баланс пользователя
+	DepositBalance(ctx context.Context, userID int64, amount decimal.Decimal) (*DepositResult, error)
+
 	// GetBalance возвращает текущий баланс пользователя
 	GetBalance(ctx context.Context, userID int64) (decimal.Decimal, error)
+
+	// FreezeAccount замораживает счет: списания и зачисления запрещены до разморозки
+	FreezeAccount(ctx context.Context, userID int64) error
+
+	// UnfreezeAccount размораживает счет
+	UnfreezeAccount(ctx context.Context, userID int64) error
 }
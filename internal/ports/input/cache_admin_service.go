@@ -0,0 +1,38 @@
+package input
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrCacheInspectionUnsupported возвращается, если кэш не позволяет просмотреть содержимое
+var ErrCacheInspectionUnsupported = errors.New("cache does not support inspection")
+
+// AdminActor описывает, кто и откуда выполняет административное действие
+type AdminActor struct {
+	Name       string
+	RemoteAddr string
+}
+
+// CacheEntry сведения об элементе кэша
+type CacheEntry struct {
+	Key              string  `json:"key"`
+	ExpiresInSeconds float64 `json:"expires_in_seconds"` // -1 — без TTL
+	SizeBytes        int64   `json:"size_bytes"`
+}
+
+// CacheAdminService определяет интерфейс администрирования кэша.
+// Каждое действие записывается в журнал аудита.
+type CacheAdminService interface {
+	// ListEntries возвращает ключи кэша с оставшимся временем жизни и размером
+	ListEntries(ctx context.Context, actor AdminActor) ([]CacheEntry, error)
+
+	// RefreshCatalogue принудительно обновляет каталог из Skinport
+	RefreshCatalogue(ctx context.Context, actor AdminActor) error
+
+	// DeleteKey удаляет ключ из кэша
+	DeleteKey(ctx context.Context, actor AdminActor, key string) error
+
+	// Clear очищает кэш целиком
+	Clear(ctx context.Context, actor AdminActor) error
+}
@@ -0,0 +1,24 @@
+package input
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// InsightQuery содержит параметры ранжирования аналитики.
+// Незаданные комиссия и минимальное количество берутся из конфигурации.
+type InsightQuery struct {
+	SortBy      item.InsightSort
+	FeePercent  *decimal.Decimal
+	MinQuantity *int
+	Limit       int
+}
+
+// InsightService определяет интерфейс сервиса аналитики по каталогу
+type InsightService interface {
+	// GetInsights возвращает предметы, ранжированные по выбранной метрике по убыванию
+	GetInsights(ctx context.Context, q InsightQuery) ([]*item.Insight, error)
+}
@@ -10,4 +10,6 @@ import (
 type ItemService interface {
 	// GetItems возвращает список предметов с минимальными ценами
 	GetItems(ctx context.Context) ([]*item.Item, error)
+	// GetCatalogue возвращает каталог вместе с его версией и временем обновления
+	GetCatalogue(ctx context.Context) (*item.Catalogue, error)
 }
@@ -0,0 +1,14 @@
+package input
+
+import (
+	"context"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// PriceHistoryService определяет интерфейс сервиса собственной истории цен
+type PriceHistoryService interface {
+	// GetPriceCandles возвращает OHLC агрегаты цен предмета за период [from, to) с шагом interval
+	GetPriceCandles(ctx context.Context, marketHashName string, from, to time.Time, interval time.Duration) ([]*item.PriceCandle, error)
+}
@@ -0,0 +1,14 @@
+package input
+
+import (
+	"context"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// SaleFeedService определяет интерфейс сервиса ленты продаж в реальном времени
+type SaleFeedService interface {
+	// Subscribe подписывает клиента на события ленты продаж.
+	// Канал закрывается при отмене контекста или если клиент не успевает читать события.
+	Subscribe(ctx context.Context) <-chan *item.SaleEvent
+}
@@ -0,0 +1,15 @@
+package input
+
+import (
+	"context"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// SalesHistoryService определяет интерфейс сервиса истории продаж
+type SalesHistoryService interface {
+	// GetSalesHistory возвращает историю продаж предмета по market_hash_name
+	GetSalesHistory(ctx context.Context, marketHashName string) (*item.SalesHistory, error)
+	// GetSalesHistories возвращает историю продаж всех предметов, индексированную по market_hash_name
+	GetSalesHistories(ctx context.Context) (map[string]*item.SalesHistory, error)
+}
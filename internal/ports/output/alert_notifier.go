@@ -0,0 +1,14 @@
+package output
+
+import (
+	"context"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+)
+
+// AlertNotifier определяет интерфейс доставки уведомлений о срабатывании алертов
+type AlertNotifier interface {
+	// Notify выполняет одну попытку доставки уведомления на адрес вебхука.
+	// Ошибка alert.ErrDeliveryRejected означает, что повторять попытку бессмысленно.
+	Notify(ctx context.Context, webhookURL string, n *alert.Notification) error
+}
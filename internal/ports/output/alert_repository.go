@@ -0,0 +1,25 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/alert"
+)
+
+// AlertRepository определяет интерфейс репозитория ценовых алертов
+type AlertRepository interface {
+	// Save сохраняет новый алерт
+	Save(ctx context.Context, a *alert.Alert) error
+	// GetByUserID возвращает алерты пользователя
+	GetByUserID(ctx context.Context, userID int64) ([]*alert.Alert, error)
+	// ListActive возвращает все активные алерты
+	ListActive(ctx context.Context) ([]*alert.Alert, error)
+	// MarkTriggered деактивирует алерт, если он еще активен.
+	// Возвращает false если алерт уже сработал (например, на другой реплике).
+	MarkTriggered(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	// SaveDeadLetter сохраняет недоставленное уведомление
+	SaveDeadLetter(ctx context.Context, dl *alert.DeadLetter) error
+}
@@ -0,0 +1,13 @@
+package output
+
+import (
+	"context"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/audit"
+)
+
+// AuditLog определяет интерфейс журнала аудита административных действий
+type AuditLog interface {
+	// Record сохраняет запись журнала
+	Record(ctx context.Context, entry *audit.Entry) error
+}
@@ -19,3 +19,23 @@ type Cache interface {
 	// Clear очищает весь кэш
 	Clear(ctx context.Context)
 }
+
+// CacheEntryInfo сведения об элементе кэша для диагностики
+type CacheEntryInfo struct {
+	Key       string        `json:"key"`
+	ExpiresIn time.Duration `json:"expires_in"` // оставшееся время жизни; отрицательное — без TTL
+	SizeBytes int64         `json:"size_bytes"` // приблизительный размер значения
+}
+
+// CacheTTLReader реализуют кэши, умеющие вернуть значение вместе с оставшимся временем жизни
+type CacheTTLReader interface {
+	// GetWithTTL возвращает значение и оставшееся время жизни: отрицательное — ключ без TTL,
+	// ноль — время жизни неизвестно
+	GetWithTTL(ctx context.Context, key string) (value interface{}, ttl time.Duration, ok bool)
+}
+
+// CacheInspector реализуют кэши, позволяющие просмотреть свое содержимое
+type CacheInspector interface {
+	// Entries возвращает сведения обо всех актуальных элементах
+	Entries(ctx context.Context) ([]CacheEntryInfo, error)
+}
@@ -0,0 +1,26 @@
+package output
+
+import "context"
+
+// CacheInvalidation сообщение об изменении общего кэша. Реплики удаляют у себя
+// локальную копию ключа, чтобы следующее чтение взяло новую версию из общего кэша.
+type CacheInvalidation struct {
+	Origin string `json:"origin"`        // идентификатор реплики-отправителя
+	Key    string `json:"key,omitempty"` // измененный ключ
+	All    bool   `json:"all,omitempty"` // кэш очищен целиком
+}
+
+// CacheInvalidationHandler обрабатывает полученное сообщение об инвалидации
+type CacheInvalidationHandler func(ctx context.Context, msg CacheInvalidation)
+
+// CacheInvalidationBus определяет интерфейс рассылки инвалидаций между репликами
+type CacheInvalidationBus interface {
+	// Publish рассылает сообщение всем подписчикам, включая отправителя
+	Publish(ctx context.Context, msg CacheInvalidation) error
+
+	// Subscribe вызывает handler для каждого сообщения.
+	// Блокируется до отмены контекста, переподключаясь при обрывах соединения.
+	// Сообщения за время обрыва теряются, поэтому после переподключения
+	// handler получает сообщение с All = true.
+	Subscribe(ctx context.Context, handler CacheInvalidationHandler) error
+}
@@ -0,0 +1,17 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// CatalogueStore определяет интерфейс хранилища последнего успешно полученного каталога
+type CatalogueStore interface {
+	// Save заменяет сохраненный каталог
+	Save(ctx context.Context, items []*item.Item, savedAt time.Time) error
+	// Load возвращает сохраненный каталог; UpdatedAt — время сохранения.
+	// Если каталог не сохранялся, возвращает item.ErrCatalogueNotStored.
+	Load(ctx context.Context) (*item.Catalogue, error)
+}
@@ -0,0 +1,16 @@
+package output
+
+import "context"
+
+// Lock захваченная распределенная блокировка
+type Lock interface {
+	// Release освобождает блокировку
+	Release(ctx context.Context) error
+}
+
+// Locker определяет интерфейс распределенной блокировки между репликами
+type Locker interface {
+	// TryLock пытается захватить блокировку по имени без ожидания.
+	// Возвращает false, если блокировку держит другой экземпляр.
+	TryLock(ctx context.Context, name string) (Lock, bool, error)
+}
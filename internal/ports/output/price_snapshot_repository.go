@@ -0,0 +1,18 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// PriceSnapshotRepository определяет интерфейс репозитория истории цен
+type PriceSnapshotRepository interface {
+	// SaveSnapshots сохраняет срезы цен предметов
+	SaveSnapshots(ctx context.Context, snapshots []*item.PriceSnapshot) error
+	// GetCandles возвращает OHLC агрегаты цен предмета за период [from, to) с шагом interval
+	GetCandles(ctx context.Context, marketHashName string, from, to time.Time, interval time.Duration) ([]*item.PriceCandle, error)
+	// DeleteOlderThan удаляет срезы старше указанного момента и возвращает количество удаленных
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
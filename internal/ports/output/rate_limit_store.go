@@ -0,0 +1,40 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit параметры token bucket
+type RateLimit struct {
+	// Rate скорость пополнения, токенов в секунду
+	Rate float64
+	// Burst емкость bucket — сколько запросов можно сделать подряд
+	Burst int
+}
+
+// Window время, за которое пустой bucket наполняется целиком
+func (l RateLimit) Window() time.Duration {
+	if l.Rate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// RateLimitResult результат попытки взять токен
+type RateLimitResult struct {
+	Allowed bool
+	// Remaining сколько запросов еще можно сделать сразу
+	Remaining int
+	// RetryAfter через сколько появится следующий токен; 0, если запрос разрешен
+	RetryAfter time.Duration
+	// ResetAfter через сколько bucket наполнится целиком
+	ResetAfter time.Duration
+}
+
+// RateLimitStore определяет хранилище token bucket'ов. Общее хранилище позволяет
+// репликам делить один бюджет клиента.
+type RateLimitStore interface {
+	// Take списывает один токен из bucket по ключу
+	Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
@@ -0,0 +1,17 @@
+package output
+
+import (
+	"context"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// SaleEventHandler обрабатывает пачку событий ленты продаж
+type SaleEventHandler func(ctx context.Context, events []*item.SaleEvent)
+
+// SaleFeed определяет интерфейс подписки на ленту продаж внешнего источника
+type SaleFeed interface {
+	// Subscribe подключается к ленте и вызывает handler для каждой пачки событий.
+	// Блокируется до отмены контекста, переподключаясь при обрывах соединения.
+	Subscribe(ctx context.Context, handler SaleEventHandler) error
+}
@@ -0,0 +1,13 @@
+package output
+
+import (
+	"context"
+
+	"github.com/akonovalovdev/DDD_example/internal/domain/item"
+)
+
+// SalesHistoryFetcher определяет интерфейс для получения истории продаж из внешнего источника
+type SalesHistoryFetcher interface {
+	// FetchSalesHistory получает агрегированную историю продаж всех предметов
+	FetchSalesHistory(ctx context.Context) ([]*item.SalesHistory, error)
+}
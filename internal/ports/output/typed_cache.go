@@ -0,0 +1,22 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// TypedCache типобезопасный кэш: значения не нужно приводить из interface{},
+// а значение чужого типа не может оказаться под ключом незамеченным
+type TypedCache[K comparable, V any] interface {
+	// Get получает значение из кэша
+	Get(ctx context.Context, key K) (V, bool)
+
+	// Set устанавливает значение в кэш с указанным TTL
+	Set(ctx context.Context, key K, value V, ttl time.Duration)
+
+	// Delete удаляет значение из кэша
+	Delete(ctx context.Context, key K)
+
+	// Clear очищает весь кэш
+	Clear(ctx context.Context)
+}
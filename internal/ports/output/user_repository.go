@@ -20,6 +20,9 @@ type UserRepository interface {
 	// UpdateBalance обновляет баланс пользователя
 	UpdateBalance(ctx context.Context, tx *sql.Tx, id int64, balance decimal.Decimal) error
 
+	// SetFrozen замораживает или размораживает счет пользователя
+	SetFrozen(ctx context.Context, id int64, frozen bool) error
+
 	// BeginTx начинает транзакцию
 	BeginTx(ctx context.Context) (*sql.Tx, error)
 }
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS price_snapshots (
+    taken_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    market_hash_name VARCHAR(255) NOT NULL,
+    tradable_min_price DECIMAL(16, 4),
+    non_tradable_min_price DECIMAL(16, 4),
+    mean_price DECIMAL(16, 4),
+    max_price DECIMAL(16, 4),
+    quantity INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (market_hash_name, taken_at)
+);
+
+CREATE INDEX idx_price_snapshots_taken_at ON price_snapshots(taken_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS price_snapshots;
+-- +goose StatementEnd
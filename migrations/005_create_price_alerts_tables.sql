@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS price_alerts (
+    id UUID PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    market_hash_name VARCHAR(255) NOT NULL,
+    direction VARCHAR(16) NOT NULL CHECK (direction IN ('above', 'below')),
+    price_type VARCHAR(16) NOT NULL CHECK (price_type IN ('tradable', 'non_tradable')),
+    threshold DECIMAL(16, 4) NOT NULL,
+    webhook_url TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    triggered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_price_alerts_user_id ON price_alerts(user_id);
+CREATE INDEX idx_price_alerts_active ON price_alerts(market_hash_name) WHERE active;
+
+CREATE TABLE IF NOT EXISTS alert_dead_letters (
+    id UUID PRIMARY KEY,
+    alert_id UUID NOT NULL REFERENCES price_alerts(id) ON DELETE CASCADE,
+    webhook_url TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_alert_dead_letters_alert_id ON alert_dead_letters(alert_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS alert_dead_letters;
+DROP TABLE IF EXISTS price_alerts;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Последний успешно полученный каталог Skinport, хранится единственной строкой
+CREATE TABLE IF NOT EXISTS catalogue_snapshot (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    items JSONB NOT NULL,
+    item_count INTEGER NOT NULL,
+    saved_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS catalogue_snapshot;
+-- +goose StatementEnd